@@ -27,16 +27,102 @@ func main() {
 	}
 	defer logRepo.Close()
 
+	searchRepo, err := repository.NewSearchRepository(logRepo.DB())
+	if err != nil {
+		log.Fatal("初始化全文索引失败:", err)
+	}
+
+	analysisRepo, err := repository.NewAnalysisRepository(logRepo.DB())
+	if err != nil {
+		log.Fatal("初始化分析缓存失败:", err)
+	}
+
+	savedSearchRepo, err := repository.NewSavedSearchRepository(logRepo.DB())
+	if err != nil {
+		log.Fatal("初始化保存的搜索失败:", err)
+	}
+
+	ruleRepo, err := repository.NewRuleRepository(logRepo.DB())
+	if err != nil {
+		log.Fatal("初始化规则库失败:", err)
+	}
+
+	factRepo, err := repository.NewFactRepository(logRepo.DB())
+	if err != nil {
+		log.Fatal("初始化元数据库失败:", err)
+	}
+
+	fileIndexRepo, err := repository.NewFileIndexRepository(logRepo.DB())
+	if err != nil {
+		log.Fatal("初始化文件索引失败:", err)
+	}
+
+	fileTreeRepo, err := repository.NewFileTreeRepository(logRepo.DB())
+	if err != nil {
+		log.Fatal("初始化文件树失败:", err)
+	}
+
+	redactionService, err := services.NewRedactionService(cfg)
+	if err != nil {
+		log.Fatal("初始化脱敏规则失败:", err)
+	}
+
 	// 初始化服务
 	logService := services.NewLogService(logRepo)
 	remoteService := services.NewRemoteService(cfg)
-	fileService := services.NewFileService(cfg)
+	fileService := services.NewFileService(cfg, redactionService, fileIndexRepo, fileTreeRepo)
 	deviceService := services.NewDeviceService()
+	searchService := services.NewSearchService(searchRepo, fileService, redactionService)
+	timelineService := services.NewTimelineService(fileService, redactionService)
+	diffService := services.NewDiffService(fileService, redactionService)
+	compareService := services.NewCompareService(fileService, diffService)
+	patternService := services.NewPatternService(fileService, redactionService)
+	analysisCache := services.NewAnalysisCache(analysisRepo)
+	statsService := services.NewStatsService(fileService, analysisCache)
+	queryService := services.NewQueryService(fileService, redactionService)
+	savedSearchService := services.NewSavedSearchService(savedSearchRepo, searchService)
+	goroutineService := services.NewGoroutineService(fileService, redactionService)
+	ruleService := services.NewRuleService(ruleRepo, fileService, redactionService)
+	summaryService := services.NewSummaryService(fileService, statsService, patternService, ruleService, analysisCache, redactionService)
+	factService, err := services.NewFactService(cfg, factRepo, fileService, redactionService)
+	if err != nil {
+		log.Fatal("初始化元数据提取规则失败:", err)
+	}
+	retentionService := services.NewRetentionService(cfg, logService, fileService)
+	storageService := services.NewStorageService(cfg, logService, fileService)
+
+	// 注册日志生命周期钩子
+	logService.AddHook("files", fileService)
+	logService.AddHook("storage", storageService)
+	logService.AddHook("search", searchService)
+	logService.AddHook("analysis", analysisCache)
+	logService.AddHook("rules", ruleService)
+	logService.AddHook("summary", summaryService)
+	logService.AddHook("facts", factService)
 
 	// 初始化处理器
-	logHandler := handlers.NewLogHandler(logService, fileService)
+	logHandler := handlers.NewLogHandler(logService, fileService, redactionService, factService)
 	remoteHandler := handlers.NewRemoteHandler(remoteService)
 	deviceHandler := handlers.NewDeviceHandler(deviceService)
+	searchHandler := handlers.NewSearchHandler(searchService, logService, redactionService)
+	timelineHandler := handlers.NewTimelineHandler(timelineService, logService, redactionService)
+	diffHandler := handlers.NewDiffHandler(diffService, logService, redactionService)
+	compareHandler := handlers.NewCompareHandler(compareService, logService, redactionService)
+	patternHandler := handlers.NewPatternHandler(patternService, logService, redactionService)
+	statsHandler := handlers.NewStatsHandler(statsService, logService)
+	queryHandler := handlers.NewQueryHandler(queryService, logService, redactionService)
+	savedSearchHandler := handlers.NewSavedSearchHandler(savedSearchService, logService, redactionService)
+	goroutineHandler := handlers.NewGoroutineHandler(goroutineService, logService, redactionService)
+	ruleHandler := handlers.NewRuleHandler(ruleService, logService, redactionService)
+	summaryHandler := handlers.NewSummaryHandler(summaryService, logService, redactionService)
+	factHandler := handlers.NewFactHandler(factService, logService, redactionService)
+	retentionHandler := handlers.NewRetentionHandler(retentionService)
+	storageHandler := handlers.NewStorageHandler(storageService)
+
+	trustedRole, err := middleware.TrustedRole(cfg.Redaction.RoleHeader, cfg.Redaction.TrustedProxies)
+	if err != nil {
+		log.Fatal("初始化受信任代理失败:", err)
+	}
 
 	// 创建路由器
 	r := gin.New()
@@ -45,6 +131,7 @@ func main() {
 	r.Use(middleware.Logger())
 	r.Use(middleware.Recovery())
 	r.Use(middleware.CORS())
+	r.Use(trustedRole)
 
 	// 设置静态文件服务
 	r.Static("/static", "./web/static")
@@ -68,16 +155,70 @@ func main() {
 		api.GET("/logs/:log_id", logHandler.GetLog)
 		api.POST("/download", logHandler.DownloadLog)
 		api.GET("/logs/:log_id/files", logHandler.GetLogFiles)
+		api.GET("/logs/:log_id/dir", logHandler.ListDir)
+		api.POST("/logs/:log_id/files/reindex", logHandler.ReindexLogFiles)
 		api.GET("/logs/:log_id/file", logHandler.GetLogFile)
 		api.DELETE("/logs/:log_id", logHandler.DeleteLog)
 		api.PUT("/logs/:log_id/tags", logHandler.UpdateLogTags)
 		api.PUT("/logs/:log_id/notes", logHandler.UpdateLogNotes)
 		api.PUT("/logs/:log_id/metadata", logHandler.UpdateLogMetadata)
+		api.POST("/logs/:log_id/reindex", searchHandler.ReindexLog)
+		api.GET("/log-formats", logHandler.GetLogFormats)
+		api.GET("/logs/:log_id/timeline", timelineHandler.GetTimeline)
+		api.GET("/logs/:log_id/compare/:other_id", compareHandler.CompareLogs)
+		api.GET("/logs/:log_id/patterns", patternHandler.GetPatterns)
+		api.GET("/logs/:log_id/stats", statsHandler.GetStats)
+		api.GET("/logs/:log_id/query", queryHandler.Query)
+		api.POST("/logs/:log_id/saved-searches/:id/run", savedSearchHandler.RunSavedSearch)
+		api.GET("/logs/:log_id/goroutines", goroutineHandler.AnalyzeGoroutines)
+		api.GET("/logs/:log_id/findings", ruleHandler.GetFindings)
+		api.POST("/logs/:log_id/findings/rescan", ruleHandler.RescanFindings)
+		api.GET("/logs/:log_id/summary", summaryHandler.GetSummary)
+		api.GET("/logs/:log_id/facts", factHandler.GetFacts)
+		api.POST("/logs/:log_id/facts/extract", factHandler.ExtractFacts)
+		api.GET("/extractors", factHandler.GetExtractors)
+
+		// 全文搜索API
+		api.GET("/search", searchHandler.Search)
+
+		// 保存的搜索API
+		api.GET("/saved-searches", savedSearchHandler.ListSavedSearches)
+		api.POST("/saved-searches", savedSearchHandler.CreateSavedSearch)
+		api.GET("/saved-searches/:id", savedSearchHandler.GetSavedSearch)
+		api.PUT("/saved-searches/:id", savedSearchHandler.UpdateSavedSearch)
+		api.DELETE("/saved-searches/:id", savedSearchHandler.DeleteSavedSearch)
+
+		// 已知问题规则API
+		api.GET("/rules", ruleHandler.ListRules)
+		api.POST("/rules", ruleHandler.CreateRule)
+		api.GET("/rules/:id", ruleHandler.GetRule)
+		api.PUT("/rules/:id", ruleHandler.UpdateRule)
+		api.DELETE("/rules/:id", ruleHandler.DeleteRule)
+
+		// 文件比较API
+		api.GET("/diff", diffHandler.Diff)
+
+		// 保留策略API
+		api.GET("/retention", retentionHandler.GetPolicy)
+		api.GET("/retention/dry-run", retentionHandler.DryRun)
+		api.POST("/retention/run", retentionHandler.Run)
+
+		// 存储统计API
+		api.GET("/storage", storageHandler.GetStats)
 
 		// 设备检测API
 		api.POST("/device-check", deviceHandler.CheckDevice)
 	}
 
+	// 后台执行导入钩子
+	logService.StartImportWorker()
+
+	// 为之前导入的日志记录大小，然后启动保留策略后台清理
+	go func() {
+		storageService.BackfillSizes()
+		retentionService.StartJanitor()
+	}()
+
 	// 启动服务器
 	port := cfg.Server.Port
 	log.Printf("服务器启动在端口 %s", port)
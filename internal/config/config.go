@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 )
 
 // Config 应用配置
@@ -15,6 +16,12 @@ type Config struct {
 	Storage StorageConfig
 	// 远程API配置
 	RemoteAPI RemoteAPIConfig
+	// 脱敏配置
+	Redaction RedactionConfig
+	// 元数据提取配置
+	Facts FactsConfig
+	// 保留策略配置
+	Retention RetentionConfig
 }
 
 // ServerConfig 服务器配置
@@ -36,6 +43,7 @@ type StorageConfig struct {
 	ExtractDir string
 	MaxFileSize int64 // 最大文件大小（字节）
 	MaxPreview  int64 // 预览大小（字节）
+	Compression string // 解压文件的存储方式：空表示原样保存，zstd 表示压缩保存
 }
 
 // RemoteAPIConfig 远程API配置
@@ -46,6 +54,29 @@ type RemoteAPIConfig struct {
 	Timeout int // 超时时间（秒）
 }
 
+// RedactionConfig 脱敏配置
+type RedactionConfig struct {
+	Enabled        bool
+	RulesFile      string   // 自定义规则文件（JSON），在内置规则之前应用
+	RoleHeader     string   // 携带用户角色的请求头，由反向代理在认证后设置
+	RawRoles       []string // 允许查看原始内容的角色
+	TrustedProxies []string // 只接受来自这些地址（IP或CIDR）的角色请求头
+}
+
+// FactsConfig 元数据提取配置
+type FactsConfig struct {
+	ExtractorsFile string // 自定义提取规则文件（JSON），在内置规则之前应用
+}
+
+// RetentionConfig 保留策略配置，限制为0表示不限制
+type RetentionConfig struct {
+	MaxAgeDays    int      // 日志最长保留天数
+	MaxTotalBytes int64    // 所有日志解压后的总大小上限（字节）
+	MaxBundles    int      // 日志数量上限
+	ExemptTags    []string // 带有这些标签的日志不会被自动删除
+	Interval      int      // 后台清理间隔（分钟），0表示不自动清理
+}
+
 // Load 加载配置
 func Load() *Config {
 	return &Config{
@@ -63,6 +94,7 @@ func Load() *Config {
 			ExtractDir:  getEnv("STORAGE_EXTRACT_DIR", "storage/extracted"),
 			MaxFileSize: getEnvAsInt64("MAX_FILE_SIZE", 50*1024*1024), // 50MB
 			MaxPreview:  getEnvAsInt64("MAX_PREVIEW_SIZE", 10*1024*1024),  // 10MB
+			Compression: getEnv("STORAGE_COMPRESSION", ""),
 		},
 		RemoteAPI: RemoteAPIConfig{
 			BaseURL: getEnv("REMOTE_API_URL", "https://hlogs.lazycat.cloud/api/v1"),
@@ -70,6 +102,23 @@ func Load() *Config {
 			Password: getEnv("REMOTE_API_PASSWORD", "N5JKpyiw97zhrY0U"),
 			Timeout: getEnvAsInt("REMOTE_API_TIMEOUT", 300),
 		},
+		Redaction: RedactionConfig{
+			Enabled:        getEnvAsBool("REDACTION_ENABLED", true),
+			RulesFile:      getEnv("REDACTION_RULES_FILE", ""),
+			RoleHeader:     getEnv("REDACTION_ROLE_HEADER", "X-User-Role"),
+			RawRoles:       getEnvAsList("REDACTION_RAW_ROLES", []string{"admin"}),
+			TrustedProxies: getEnvAsList("REDACTION_TRUSTED_PROXIES", []string{"127.0.0.1", "::1"}),
+		},
+		Facts: FactsConfig{
+			ExtractorsFile: getEnv("FACT_EXTRACTORS_FILE", ""),
+		},
+		Retention: RetentionConfig{
+			MaxAgeDays:    getEnvAsInt("RETENTION_MAX_AGE_DAYS", 0),
+			MaxTotalBytes: getEnvAsInt64("RETENTION_MAX_TOTAL_BYTES", 0),
+			MaxBundles:    getEnvAsInt("RETENTION_MAX_BUNDLES", 0),
+			ExemptTags:    getEnvAsList("RETENTION_EXEMPT_TAGS", []string{"pinned"}),
+			Interval:      getEnvAsInt("RETENTION_INTERVAL", 60),
+		},
 	}
 }
 
@@ -99,4 +148,29 @@ func getEnvAsInt64(key string, defaultValue int64) int64 {
 		}
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// getEnvAsBool 获取环境变量并转换为bool
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsList 获取逗号分隔的环境变量
+func getEnvAsList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
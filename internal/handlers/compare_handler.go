@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CompareHandler 日志包比较处理器
+type CompareHandler struct {
+	compareService *services.CompareService
+	logService     *services.LogService
+	redaction      *services.RedactionService
+}
+
+// NewCompareHandler 创建日志包比较处理器
+func NewCompareHandler(compareService *services.CompareService, logService *services.LogService, redaction *services.RedactionService) *CompareHandler {
+	return &CompareHandler{
+		compareService: compareService,
+		logService:     logService,
+		redaction:      redaction,
+	}
+}
+
+// CompareLogs 比较两个日志包的文件树，指定path时返回该文件的内容差异
+// GET /api/logs/:log_id/compare/:other_id?path=&mode=&context=&ignore_whitespace=&ignore_timestamps=&raw=
+func (h *CompareHandler) CompareLogs(c *gin.Context) {
+	logA := c.Param("log_id")
+	logB := c.Param("other_id")
+
+	// 检查两个日志是否存在
+	for _, logID := range []string{logA, logB} {
+		log, err := h.logService.GetLog(logID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+			return
+		}
+		if log == nil {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrLogNotFound+": "+logID, models.StatusNotFound))
+			return
+		}
+	}
+
+	if path := c.Query("path"); path != "" {
+		opts, err := parseDiffOptions(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), models.StatusBadRequest))
+			return
+		}
+		raw, ok := requestRaw(c, h.redaction)
+		if !ok {
+			return
+		}
+		opts.Raw = raw
+		result, err := h.compareService.CompareFile(logA, logB, path, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+			return
+		}
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
+	result, err := h.compareService.CompareLogs(logA, logB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"fmt"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DiffHandler 文件比较处理器
+type DiffHandler struct {
+	diffService *services.DiffService
+	logService  *services.LogService
+	redaction   *services.RedactionService
+}
+
+// NewDiffHandler 创建文件比较处理器
+func NewDiffHandler(diffService *services.DiffService, logService *services.LogService, redaction *services.RedactionService) *DiffHandler {
+	return &DiffHandler{
+		diffService: diffService,
+		logService:  logService,
+		redaction:   redaction,
+	}
+}
+
+// Diff 比较两个文件，可以跨日志包
+// GET /api/diff?a=log1:path&b=log2:path&mode=unified|side-by-side&context=3&ignore_whitespace=1&ignore_timestamps=1&raw=
+func (h *DiffHandler) Diff(c *gin.Context) {
+	a, err := services.ParseFileRef(c.Query("a"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), models.StatusBadRequest))
+		return
+	}
+	b, err := services.ParseFileRef(c.Query("b"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), models.StatusBadRequest))
+		return
+	}
+
+	opts, err := parseDiffOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), models.StatusBadRequest))
+		return
+	}
+	raw, ok := requestRaw(c, h.redaction)
+	if !ok {
+		return
+	}
+	opts.Raw = raw
+
+	// 检查两侧日志是否存在
+	for _, ref := range []models.FileRef{a, b} {
+		log, err := h.logService.GetLog(ref.LogID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+			return
+		}
+		if log == nil {
+			c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrLogNotFound+": "+ref.LogID, models.StatusNotFound))
+			return
+		}
+	}
+
+	result, err := h.diffService.DiffFiles(a, b, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// parseDiffOptions 解析比较模式、上下文行数和归一化选项
+func parseDiffOptions(c *gin.Context) (models.DiffOptions, error) {
+	opts := models.DiffOptions{
+		Mode:             c.DefaultQuery("mode", services.DiffModeUnified),
+		Context:          queryInt(c, "context", 3),
+		IgnoreWhitespace: queryBool(c, "ignore_whitespace"),
+		IgnoreTimestamps: queryBool(c, "ignore_timestamps"),
+	}
+	if opts.Mode != services.DiffModeUnified && opts.Mode != services.DiffModeSideBySide {
+		return opts, fmt.Errorf(models.ErrInvalidDiffMode)
+	}
+	return opts, nil
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FactHandler 日志元数据处理器
+type FactHandler struct {
+	factService *services.FactService
+	logService  *services.LogService
+	redaction   *services.RedactionService
+}
+
+// NewFactHandler 创建日志元数据处理器
+func NewFactHandler(factService *services.FactService, logService *services.LogService, redaction *services.RedactionService) *FactHandler {
+	return &FactHandler{
+		factService: factService,
+		logService:  logService,
+		redaction:   redaction,
+	}
+}
+
+// checkLog 检查日志是否存在
+func (h *FactHandler) checkLog(c *gin.Context, logID string) bool {
+	log, err := h.logService.GetLog(logID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return false
+	}
+	if log == nil {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrLogNotFound, models.StatusNotFound))
+		return false
+	}
+	return true
+}
+
+// GetFacts 获取日志的元数据
+// GET /api/logs/:log_id/facts?raw=1
+func (h *FactHandler) GetFacts(c *gin.Context) {
+	logID := c.Param("log_id")
+	raw, ok := requestRaw(c, h.redaction)
+	if !ok {
+		return
+	}
+	if !h.checkLog(c, logID) {
+		return
+	}
+
+	result, err := h.factService.GetFacts(logID, raw)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExtractFacts 使用当前提取规则重新提取日志的元数据
+// POST /api/logs/:log_id/facts/extract?raw=1
+func (h *FactHandler) ExtractFacts(c *gin.Context) {
+	logID := c.Param("log_id")
+	raw, ok := requestRaw(c, h.redaction)
+	if !ok {
+		return
+	}
+	if !h.checkLog(c, logID) {
+		return
+	}
+
+	facts, err := h.factService.ExtractFacts(logID, raw)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.FactResult{LogID: logID, Facts: facts})
+}
+
+// GetExtractors 获取生效的元数据提取规则
+// GET /api/extractors
+func (h *FactHandler) GetExtractors(c *gin.Context) {
+	c.JSON(http.StatusOK, h.factService.Extractors())
+}
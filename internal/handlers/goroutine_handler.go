@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GoroutineHandler goroutine转储分析处理器
+type GoroutineHandler struct {
+	goroutineService *services.GoroutineService
+	logService       *services.LogService
+	redaction        *services.RedactionService
+}
+
+// NewGoroutineHandler 创建goroutine转储分析处理器
+func NewGoroutineHandler(goroutineService *services.GoroutineService, logService *services.LogService, redaction *services.RedactionService) *GoroutineHandler {
+	return &GoroutineHandler{
+		goroutineService: goroutineService,
+		logService:       logService,
+		redaction:        redaction,
+	}
+}
+
+// AnalyzeGoroutines 分析文件中的goroutine转储（panic、SIGQUIT）
+// GET /api/logs/:log_id/goroutines?path=文件路径&dump=&raw=
+func (h *GoroutineHandler) AnalyzeGoroutines(c *gin.Context) {
+	logID := c.Param("log_id")
+	filePath := c.Query("path")
+
+	if filePath == "" {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("文件路径不能为空", models.StatusBadRequest))
+		return
+	}
+	dump := queryInt(c, "dump", 0)
+	if dump < 0 {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(models.ErrInvalidDump, models.StatusBadRequest))
+		return
+	}
+	raw, ok := requestRaw(c, h.redaction)
+	if !ok {
+		return
+	}
+
+	// 检查日志是否存在
+	log, err := h.logService.GetLog(logID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+	if log == nil {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrLogNotFound, models.StatusNotFound))
+		return
+	}
+
+	result, err := h.goroutineService.Analyze(logID, filePath, dump, raw)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"logview-goversion/internal/models"
 	"logview-goversion/internal/services"
 	"net/http"
@@ -12,24 +13,53 @@ import (
 type LogHandler struct {
 	logService  *services.LogService
 	fileService *services.FileService
+	redaction   *services.RedactionService
+	factService *services.FactService
 }
 
 // NewLogHandler 创建日志处理器
-func NewLogHandler(logService *services.LogService, fileService *services.FileService) *LogHandler {
+func NewLogHandler(logService *services.LogService, fileService *services.FileService, redaction *services.RedactionService, factService *services.FactService) *LogHandler {
 	return &LogHandler{
 		logService:  logService,
 		fileService: fileService,
+		redaction:   redaction,
+		factService: factService,
 	}
 }
 
-// GetLogs 获取所有日志列表
-// GET /api/logs
+// GetLogs 获取所有日志列表，可按提取的元数据过滤，按下载时间或大小排序
+// 元数据值默认脱敏，过滤条件也只和脱敏后的值比较
+// GET /api/logs?fact=firmware_version=2.1.*&fact=hostname&sort=size&raw=1
 func (h *LogHandler) GetLogs(c *gin.Context) {
+	raw, ok := requestRaw(c, h.redaction)
+	if !ok {
+		return
+	}
+
+	var filters []models.FactFilter
+	for _, value := range c.QueryArray("fact") {
+		filter, err := services.ParseFactFilter(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), models.StatusBadRequest))
+			return
+		}
+		filters = append(filters, filter)
+	}
+
 	logs, err := h.logService.GetAllLogs()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
 		return
 	}
+	logs, err = h.factService.FilterLogs(logs, filters, raw)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+	if err := services.SortLogs(logs, c.Query("sort")); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), models.StatusBadRequest))
+		return
+	}
 	c.JSON(http.StatusOK, logs)
 }
 
@@ -82,8 +112,10 @@ func (h *LogHandler) DownloadLog(c *gin.Context) {
 		return
 	}
 
+	// 索引、规则扫描等在后台执行，进度通过 GET /api/logs/:log_id 查询
 	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{
 		"log_id": logID,
+		"status": models.ImportPending,
 	}))
 }
 
@@ -113,8 +145,69 @@ func (h *LogHandler) GetLogFiles(c *gin.Context) {
 	c.JSON(http.StatusOK, fileStructure)
 }
 
+// ListDir 分页获取日志中一个目录的子项，q 或 glob 非空时在该目录下递归查找匹配的文件和目录
+// GET /api/logs/:log_id/dir?path=&cursor=&limit=&q=&glob=
+func (h *LogHandler) ListDir(c *gin.Context) {
+	logID := c.Param("log_id")
+
+	opts := models.DirOptions{
+		Path:   c.Query("path"),
+		Query:  c.Query("q"),
+		Glob:   c.Query("glob"),
+		Limit:  queryInt(c, "limit", 0),
+		Cursor: c.Query("cursor"),
+	}
+	if err := services.ValidateDirOptions(opts); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), models.StatusBadRequest))
+		return
+	}
+
+	// 检查日志是否存在
+	log, err := h.logService.GetLog(logID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+	if log == nil {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrLogNotFound, models.StatusNotFound))
+		return
+	}
+
+	listing, err := h.fileService.ListDir(logID, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, listing)
+}
+
+// ReindexLogFiles 重建日志的文件索引（内容类型、编码、行数和错误/警告行数）
+// POST /api/logs/:log_id/files/reindex
+func (h *LogHandler) ReindexLogFiles(c *gin.Context) {
+	logID := c.Param("log_id")
+
+	// 检查日志是否存在
+	log, err := h.logService.GetLog(logID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+	if log == nil {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrLogNotFound, models.StatusNotFound))
+		return
+	}
+
+	if err := h.fileService.OnLogImported(logID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(nil))
+}
+
 // GetLogFile 获取日志文件内容
-// GET /api/logs/:log_id/file?path=文件路径
+// GET /api/logs/:log_id/file?path=文件路径&offset=&limit=&level=ERROR,WARN&since=&until=&parse=1&format=日志格式
 func (h *LogHandler) GetLogFile(c *gin.Context) {
 	logID := c.Param("log_id")
 	filePath := c.Query("path")
@@ -124,6 +217,17 @@ func (h *LogHandler) GetLogFile(c *gin.Context) {
 		return
 	}
 
+	opts, err := h.parseFileContentOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), models.StatusBadRequest))
+		return
+	}
+	raw, ok := requestRaw(c, h.redaction)
+	if !ok {
+		return
+	}
+	opts.Raw = raw
+
 	// 检查日志是否存在
 	log, err := h.logService.GetLog(logID)
 	if err != nil {
@@ -136,7 +240,7 @@ func (h *LogHandler) GetLogFile(c *gin.Context) {
 	}
 
 	// 获取文件内容
-	content, err := h.fileService.GetFileContent(logID, filePath)
+	content, err := h.fileService.GetFileContent(logID, filePath, opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
 		return
@@ -145,6 +249,40 @@ func (h *LogHandler) GetLogFile(c *gin.Context) {
 	c.JSON(http.StatusOK, content)
 }
 
+// parseFileContentOptions 解析文件内容的分页、过滤和解析参数
+func (h *LogHandler) parseFileContentOptions(c *gin.Context) (models.FileContentOptions, error) {
+	opts := models.FileContentOptions{
+		Parse:  queryBool(c, "parse"),
+		Format: c.Query("format"),
+		Offset: queryInt(c, "offset", 0),
+		Limit:  queryInt(c, "limit", 0),
+	}
+
+	if opts.Format != "" && !h.fileService.SupportsLogFormat(opts.Format) {
+		return opts, fmt.Errorf(models.ErrUnknownLogFormat)
+	}
+
+	levels, err := queryLevels(c, "level")
+	if err != nil {
+		return opts, err
+	}
+	opts.Levels = levels
+
+	if opts.Since, err = queryTime(c, "since"); err != nil {
+		return opts, err
+	}
+	if opts.Until, err = queryTime(c, "until"); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// GetLogFormats 获取支持的日志格式
+// GET /api/log-formats
+func (h *LogHandler) GetLogFormats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.fileService.LogFormats())
+}
+
 // DeleteLog 删除日志
 // DELETE /api/logs/:log_id
 func (h *LogHandler) DeleteLog(c *gin.Context) {
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"fmt"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/pkg/logparser"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// queryInt 读取整数查询参数，缺失或格式错误时返回默认值
+func queryInt(c *gin.Context, key string, defaultValue int) int {
+	if value := c.Query(key); value != "" {
+		if intVal, err := strconv.Atoi(value); err == nil {
+			return intVal
+		}
+	}
+	return defaultValue
+}
+
+// queryBool 读取布尔查询参数（1/true/yes 为真）
+func queryBool(c *gin.Context, key string) bool {
+	switch c.Query(key) {
+	case "1", "true", "yes":
+		return true
+	}
+	return false
+}
+
+// queryLevels 读取逗号分隔的日志级别参数并标准化
+func queryLevels(c *gin.Context, key string) ([]string, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	var levels []string
+	for _, item := range strings.Split(value, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		level := logparser.NormalizeLevel(item)
+		if level == "" {
+			return nil, fmt.Errorf("%s: %s", models.ErrInvalidLevel, item)
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+// queryTime 读取时间参数，支持RFC3339、常见日志时间格式和Unix时间戳
+func queryTime(c *gin.Context, key string) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, ok := logparser.ParseTime(value)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%s: %s=%s", models.ErrInvalidTime, key, value)
+	}
+	return t, nil
+}
+
+// queryList 读取列表参数，支持逗号分隔和重复参数两种写法
+func queryList(c *gin.Context, key string) []string {
+	var items []string
+	for _, value := range c.QueryArray(key) {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PatternHandler 日志模板处理器
+type PatternHandler struct {
+	patternService *services.PatternService
+	logService     *services.LogService
+	redaction      *services.RedactionService
+}
+
+// NewPatternHandler 创建日志模板处理器
+func NewPatternHandler(patternService *services.PatternService, logService *services.LogService, redaction *services.RedactionService) *PatternHandler {
+	return &PatternHandler{
+		patternService: patternService,
+		logService:     logService,
+		redaction:      redaction,
+	}
+}
+
+// GetPatterns 将相似的日志行归并为模板
+// GET /api/logs/:log_id/patterns?file=&level=ERROR,FATAL&min_count=&limit=&raw=
+func (h *PatternHandler) GetPatterns(c *gin.Context) {
+	logID := c.Param("log_id")
+
+	opts := models.PatternOptions{
+		File:     c.Query("file"),
+		MinCount: queryInt(c, "min_count", 0),
+		Limit:    queryInt(c, "limit", 0),
+	}
+	var err error
+	if opts.Levels, err = queryLevels(c, "level"); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), models.StatusBadRequest))
+		return
+	}
+	raw, ok := requestRaw(c, h.redaction)
+	if !ok {
+		return
+	}
+	opts.Raw = raw
+
+	// 检查日志是否存在
+	log, err := h.logService.GetLog(logID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+	if log == nil {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrLogNotFound, models.StatusNotFound))
+		return
+	}
+
+	result, err := h.patternService.GetPatterns(logID, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueryHandler JSON日志查询处理器
+type QueryHandler struct {
+	queryService *services.QueryService
+	logService   *services.LogService
+	redaction    *services.RedactionService
+}
+
+// NewQueryHandler 创建JSON日志查询处理器
+func NewQueryHandler(queryService *services.QueryService, logService *services.LogService, redaction *services.RedactionService) *QueryHandler {
+	return &QueryHandler{
+		queryService: queryService,
+		logService:   logService,
+		redaction:    redaction,
+	}
+}
+
+// Query 使用jq表达式查询JSON日志文件
+// GET /api/logs/:log_id/query?path=文件路径&expr=select(.level=="error")|{ts,msg}&offset=&limit=&raw=
+func (h *QueryHandler) Query(c *gin.Context) {
+	logID := c.Param("log_id")
+	filePath := c.Query("path")
+	expr := c.Query("expr")
+
+	if filePath == "" {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse("文件路径不能为空", models.StatusBadRequest))
+		return
+	}
+	if err := h.queryService.ValidateExpr(expr); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), models.StatusBadRequest))
+		return
+	}
+	raw, ok := requestRaw(c, h.redaction)
+	if !ok {
+		return
+	}
+
+	// 检查日志是否存在
+	log, err := h.logService.GetLog(logID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+	if log == nil {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrLogNotFound, models.StatusNotFound))
+		return
+	}
+
+	result, err := h.queryService.Query(logID, filePath, expr, queryInt(c, "offset", 0), queryInt(c, "limit", 0), raw)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
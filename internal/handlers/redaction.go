@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"logview-goversion/internal/middleware"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestRaw 读取 raw 参数，请求未脱敏内容时按受信任代理设置的角色检查权限
+// 无权查看时返回403，ok为false
+func requestRaw(c *gin.Context, redaction *services.RedactionService) (raw bool, ok bool) {
+	if !queryBool(c, "raw") {
+		return false, true
+	}
+	if !redaction.CanViewRaw(middleware.Role(c)) {
+		c.JSON(http.StatusForbidden, models.NewErrorResponse(models.ErrRawForbidden, models.StatusForbidden))
+		return false, false
+	}
+	return true, true
+}
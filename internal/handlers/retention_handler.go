@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RetentionHandler 保留策略处理器
+type RetentionHandler struct {
+	retentionService *services.RetentionService
+}
+
+// NewRetentionHandler 创建保留策略处理器
+func NewRetentionHandler(retentionService *services.RetentionService) *RetentionHandler {
+	return &RetentionHandler{
+		retentionService: retentionService,
+	}
+}
+
+// GetPolicy 获取生效的保留策略
+// GET /api/retention
+func (h *RetentionHandler) GetPolicy(c *gin.Context) {
+	c.JSON(http.StatusOK, h.retentionService.Policy())
+}
+
+// DryRun 预览按保留策略将被删除的日志，不执行删除
+// GET /api/retention/dry-run
+func (h *RetentionHandler) DryRun(c *gin.Context) {
+	plan, err := h.retentionService.Run(true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// Run 立即按保留策略删除日志
+// POST /api/retention/run
+func (h *RetentionHandler) Run(c *gin.Context) {
+	plan, err := h.retentionService.Run(false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RuleHandler 已知问题规则处理器
+type RuleHandler struct {
+	ruleService *services.RuleService
+	logService  *services.LogService
+	redaction   *services.RedactionService
+}
+
+// NewRuleHandler 创建已知问题规则处理器
+func NewRuleHandler(ruleService *services.RuleService, logService *services.LogService, redaction *services.RedactionService) *RuleHandler {
+	return &RuleHandler{
+		ruleService: ruleService,
+		logService:  logService,
+		redaction:   redaction,
+	}
+}
+
+// ruleID 读取路径中的规则ID
+func ruleID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrRuleNotFound, models.StatusNotFound))
+		return 0, false
+	}
+	return id, true
+}
+
+// bindRule 绑定并校验请求体
+func (h *RuleHandler) bindRule(c *gin.Context) (*models.RuleRequest, bool) {
+	var req models.RuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(models.ErrInvalidRequest, models.StatusBadRequest))
+		return nil, false
+	}
+	if err := h.ruleService.ValidateRule(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), models.StatusBadRequest))
+		return nil, false
+	}
+	return &req, true
+}
+
+// checkLog 检查日志是否存在
+func (h *RuleHandler) checkLog(c *gin.Context, logID string) bool {
+	log, err := h.logService.GetLog(logID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return false
+	}
+	if log == nil {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrLogNotFound, models.StatusNotFound))
+		return false
+	}
+	return true
+}
+
+// ListRules 获取规则列表
+// GET /api/rules
+func (h *RuleHandler) ListRules(c *gin.Context) {
+	rules, err := h.ruleService.ListRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// CreateRule 创建规则
+// POST /api/rules
+func (h *RuleHandler) CreateRule(c *gin.Context) {
+	req, ok := h.bindRule(c)
+	if !ok {
+		return
+	}
+
+	rule, err := h.ruleService.CreateRule(req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// GetRule 获取规则
+// GET /api/rules/:id
+func (h *RuleHandler) GetRule(c *gin.Context) {
+	id, ok := ruleID(c)
+	if !ok {
+		return
+	}
+
+	rule, err := h.ruleService.GetRule(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+	if rule == nil {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrRuleNotFound, models.StatusNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// UpdateRule 更新规则
+// PUT /api/rules/:id
+func (h *RuleHandler) UpdateRule(c *gin.Context) {
+	id, ok := ruleID(c)
+	if !ok {
+		return
+	}
+	req, ok := h.bindRule(c)
+	if !ok {
+		return
+	}
+
+	rule, err := h.ruleService.UpdateRule(id, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+	if rule == nil {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrRuleNotFound, models.StatusNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule 删除规则
+// DELETE /api/rules/:id
+func (h *RuleHandler) DeleteRule(c *gin.Context) {
+	id, ok := ruleID(c)
+	if !ok {
+		return
+	}
+
+	success, err := h.ruleService.DeleteRule(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+	if !success {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrRuleNotFound, models.StatusNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(nil))
+}
+
+// GetFindings 获取日志的规则匹配结果
+// GET /api/logs/:log_id/findings?raw=
+func (h *RuleHandler) GetFindings(c *gin.Context) {
+	logID := c.Param("log_id")
+	raw, ok := requestRaw(c, h.redaction)
+	if !ok {
+		return
+	}
+	if !h.checkLog(c, logID) {
+		return
+	}
+
+	result, err := h.ruleService.GetFindings(logID, raw)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RescanFindings 使用当前规则重新扫描日志
+// POST /api/logs/:log_id/findings/rescan
+func (h *RuleHandler) RescanFindings(c *gin.Context) {
+	logID := c.Param("log_id")
+	if !h.checkLog(c, logID) {
+		return
+	}
+
+	result, err := h.ruleService.ScanLog(logID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SavedSearchHandler 保存的搜索处理器
+type SavedSearchHandler struct {
+	savedSearchService *services.SavedSearchService
+	logService         *services.LogService
+	redaction          *services.RedactionService
+}
+
+// NewSavedSearchHandler 创建保存的搜索处理器
+func NewSavedSearchHandler(savedSearchService *services.SavedSearchService, logService *services.LogService, redaction *services.RedactionService) *SavedSearchHandler {
+	return &SavedSearchHandler{
+		savedSearchService: savedSearchService,
+		logService:         logService,
+		redaction:          redaction,
+	}
+}
+
+// savedSearchID 读取路径中的搜索ID
+func savedSearchID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrSearchNotFound, models.StatusNotFound))
+		return 0, false
+	}
+	return id, true
+}
+
+// bindSavedSearch 绑定并校验请求体
+func (h *SavedSearchHandler) bindSavedSearch(c *gin.Context) (*models.SavedSearchRequest, bool) {
+	var req models.SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(models.ErrInvalidRequest, models.StatusBadRequest))
+		return nil, false
+	}
+	if err := h.savedSearchService.ValidateSavedSearch(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), models.StatusBadRequest))
+		return nil, false
+	}
+	return &req, true
+}
+
+// ListSavedSearches 获取保存的搜索列表
+// GET /api/saved-searches?owner=
+func (h *SavedSearchHandler) ListSavedSearches(c *gin.Context) {
+	searches, err := h.savedSearchService.ListSavedSearches(c.Query("owner"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, searches)
+}
+
+// CreateSavedSearch 创建保存的搜索
+// POST /api/saved-searches
+func (h *SavedSearchHandler) CreateSavedSearch(c *gin.Context) {
+	req, ok := h.bindSavedSearch(c)
+	if !ok {
+		return
+	}
+
+	search, err := h.savedSearchService.CreateSavedSearch(req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusCreated, search)
+}
+
+// GetSavedSearch 获取保存的搜索
+// GET /api/saved-searches/:id
+func (h *SavedSearchHandler) GetSavedSearch(c *gin.Context) {
+	id, ok := savedSearchID(c)
+	if !ok {
+		return
+	}
+
+	search, err := h.savedSearchService.GetSavedSearch(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+	if search == nil {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrSearchNotFound, models.StatusNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, search)
+}
+
+// UpdateSavedSearch 更新保存的搜索
+// PUT /api/saved-searches/:id
+func (h *SavedSearchHandler) UpdateSavedSearch(c *gin.Context) {
+	id, ok := savedSearchID(c)
+	if !ok {
+		return
+	}
+	req, ok := h.bindSavedSearch(c)
+	if !ok {
+		return
+	}
+
+	search, err := h.savedSearchService.UpdateSavedSearch(id, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+	if search == nil {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrSearchNotFound, models.StatusNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, search)
+}
+
+// DeleteSavedSearch 删除保存的搜索
+// DELETE /api/saved-searches/:id
+func (h *SavedSearchHandler) DeleteSavedSearch(c *gin.Context) {
+	id, ok := savedSearchID(c)
+	if !ok {
+		return
+	}
+
+	success, err := h.savedSearchService.DeleteSavedSearch(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+	if !success {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrSearchNotFound, models.StatusNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(nil))
+}
+
+// RunSavedSearch 在指定日志上执行保存的搜索
+// POST /api/logs/:log_id/saved-searches/:id/run?offset=&limit=&raw=
+func (h *SavedSearchHandler) RunSavedSearch(c *gin.Context) {
+	logID := c.Param("log_id")
+	id, ok := savedSearchID(c)
+	if !ok {
+		return
+	}
+	raw, ok := requestRaw(c, h.redaction)
+	if !ok {
+		return
+	}
+
+	// 检查日志是否存在
+	log, err := h.logService.GetLog(logID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+	if log == nil {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrLogNotFound, models.StatusNotFound))
+		return
+	}
+
+	result, err := h.savedSearchService.RunSavedSearch(id, logID, queryInt(c, "offset", 0), queryInt(c, "limit", 0), raw)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+	if result == nil {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrSearchNotFound, models.StatusNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
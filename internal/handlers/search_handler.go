@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchHandler 全文搜索处理器
+type SearchHandler struct {
+	searchService *services.SearchService
+	logService    *services.LogService
+	redaction     *services.RedactionService
+}
+
+// NewSearchHandler 创建全文搜索处理器
+func NewSearchHandler(searchService *services.SearchService, logService *services.LogService, redaction *services.RedactionService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+		logService:    logService,
+		redaction:     redaction,
+	}
+}
+
+// Search 在所有日志中全文搜索
+// GET /api/search?q=关键词&log_id=&offset=&limit=&raw=
+func (h *SearchHandler) Search(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(models.ErrEmptyQuery, models.StatusBadRequest))
+		return
+	}
+	raw, ok := requestRaw(c, h.redaction)
+	if !ok {
+		return
+	}
+
+	result, err := h.searchService.Search(query, c.Query("log_id"), queryInt(c, "offset", 0), queryInt(c, "limit", 0), raw)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ReindexLog 重建日志的全文索引
+// POST /api/logs/:log_id/reindex
+func (h *SearchHandler) ReindexLog(c *gin.Context) {
+	logID := c.Param("log_id")
+
+	// 检查日志是否存在
+	log, err := h.logService.GetLog(logID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+	if log == nil {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrLogNotFound, models.StatusNotFound))
+		return
+	}
+
+	result, err := h.searchService.IndexLog(logID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(result))
+}
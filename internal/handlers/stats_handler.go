@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatsHandler 日志统计处理器
+type StatsHandler struct {
+	statsService *services.StatsService
+	logService   *services.LogService
+}
+
+// NewStatsHandler 创建日志统计处理器
+func NewStatsHandler(statsService *services.StatsService, logService *services.LogService) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+		logService:   logService,
+	}
+}
+
+// GetStats 获取日志包按级别和时间桶的行数分布
+// GET /api/logs/:log_id/stats?bucket=5m
+func (h *StatsHandler) GetStats(c *gin.Context) {
+	logID := c.Param("log_id")
+
+	bucket, ok := parseBucket(c.Query("bucket"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(models.ErrInvalidBucket, models.StatusBadRequest))
+		return
+	}
+
+	// 检查日志是否存在
+	log, err := h.logService.GetLog(logID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+	if log == nil {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrLogNotFound, models.StatusNotFound))
+		return
+	}
+
+	stats, err := h.statsService.GetStats(logID, bucket)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// parseBucket 解析时间桶大小，支持秒数或 5m、1h 等写法，为空时返回0表示自动选择
+func parseBucket(value string) (time.Duration, bool) {
+	if value == "" || value == "auto" {
+		return 0, true
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, seconds > 0
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < time.Second {
+		return 0, false
+	}
+	return d, true
+}
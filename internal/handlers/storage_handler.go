@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StorageHandler 存储统计处理器
+type StorageHandler struct {
+	storageService *services.StorageService
+}
+
+// NewStorageHandler 创建存储统计处理器
+func NewStorageHandler(storageService *services.StorageService) *StorageHandler {
+	return &StorageHandler{
+		storageService: storageService,
+	}
+}
+
+// GetStats 获取存储使用情况
+// GET /api/storage
+func (h *StorageHandler) GetStats(c *gin.Context) {
+	stats, err := h.storageService.GetStats()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SummaryHandler 日志包概览处理器
+type SummaryHandler struct {
+	summaryService *services.SummaryService
+	logService     *services.LogService
+	redaction      *services.RedactionService
+}
+
+// NewSummaryHandler 创建日志包概览处理器
+func NewSummaryHandler(summaryService *services.SummaryService, logService *services.LogService, redaction *services.RedactionService) *SummaryHandler {
+	return &SummaryHandler{
+		summaryService: summaryService,
+		logService:     logService,
+		redaction:      redaction,
+	}
+}
+
+// GetSummary 获取日志包概览
+// GET /api/logs/:log_id/summary?format=json|markdown|html&raw=
+func (h *SummaryHandler) GetSummary(c *gin.Context) {
+	logID := c.Param("log_id")
+
+	format := c.DefaultQuery("format", "json")
+	if format == "md" {
+		format = "markdown"
+	}
+	if format != "json" && format != "markdown" && format != "html" {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(models.ErrInvalidFormat, models.StatusBadRequest))
+		return
+	}
+	raw, ok := requestRaw(c, h.redaction)
+	if !ok {
+		return
+	}
+
+	// 检查日志是否存在
+	log, err := h.logService.GetLog(logID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+	if log == nil {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrLogNotFound, models.StatusNotFound))
+		return
+	}
+
+	summary, err := h.summaryService.GetSummary(logID, raw)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	switch format {
+	case "markdown":
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(services.RenderSummaryMarkdown(summary)))
+	case "html":
+		page, err := services.RenderSummaryHTML(summary)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+	default:
+		c.JSON(http.StatusOK, summary)
+	}
+}
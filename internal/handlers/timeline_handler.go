@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimelineHandler 时间线处理器
+type TimelineHandler struct {
+	timelineService *services.TimelineService
+	logService      *services.LogService
+	redaction       *services.RedactionService
+}
+
+// NewTimelineHandler 创建时间线处理器
+func NewTimelineHandler(timelineService *services.TimelineService, logService *services.LogService, redaction *services.RedactionService) *TimelineHandler {
+	return &TimelineHandler{
+		timelineService: timelineService,
+		logService:      logService,
+		redaction:       redaction,
+	}
+}
+
+// GetTimeline 按时间合并多个文件
+// GET /api/logs/:log_id/timeline?files=a.log,b.log&since=&until=&level=&limit=&cursor=&raw=
+func (h *TimelineHandler) GetTimeline(c *gin.Context) {
+	logID := c.Param("log_id")
+
+	opts := models.TimelineOptions{
+		Files:  queryList(c, "files"),
+		Limit:  queryInt(c, "limit", 0),
+		Cursor: c.Query("cursor"),
+	}
+	if len(opts.Files) == 0 {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(models.ErrNoFilesSelected, models.StatusBadRequest))
+		return
+	}
+
+	var err error
+	if opts.Levels, err = queryLevels(c, "level"); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), models.StatusBadRequest))
+		return
+	}
+	if opts.Since, err = queryTime(c, "since"); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), models.StatusBadRequest))
+		return
+	}
+	if opts.Until, err = queryTime(c, "until"); err != nil {
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), models.StatusBadRequest))
+		return
+	}
+	raw, ok := requestRaw(c, h.redaction)
+	if !ok {
+		return
+	}
+	opts.Raw = raw
+
+	// 检查日志是否存在
+	log, err := h.logService.GetLog(logID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+	if log == nil {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(models.ErrLogNotFound, models.StatusNotFound))
+		return
+	}
+
+	result, err := h.timelineService.GetTimeline(logID, opts)
+	switch {
+	case errors.Is(err, services.ErrTimelineFileNotFound):
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(err.Error(), models.StatusNotFound))
+		return
+	case errors.Is(err, services.ErrTimelineInvalidCursor),
+		errors.Is(err, services.ErrTimelineNoFiles),
+		errors.Is(err, services.ErrTimelineTooManyFiles),
+		errors.Is(err, services.ErrTimelineDuplicateFile):
+		c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error(), models.StatusBadRequest))
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error(), models.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
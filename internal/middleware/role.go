@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// roleKey 上下文中保存用户角色的键
+const roleKey = "user_role"
+
+// TrustedRole 从反向代理设置的请求头读取用户角色
+// 只有直接连接的对端地址（不是 X-Forwarded-For）在受信任列表中时才接受该请求头，
+// 浏览器或其他客户端直接发送的同名请求头会被忽略；代理必须在转发前删除客户端发送的该请求头
+func TrustedRole(header string, trustedProxies []string) (gin.HandlerFunc, error) {
+	prefixes := make([]netip.Prefix, 0, len(trustedProxies))
+	for _, item := range trustedProxies {
+		prefix, err := parseTrustedProxy(item)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix)
+	}
+
+	return func(c *gin.Context) {
+		role := c.GetHeader(header)
+		// 后续处理只能通过 Role 获取角色
+		c.Request.Header.Del(header)
+		if role != "" && trustedPeer(c.Request.RemoteAddr, prefixes) {
+			c.Set(roleKey, strings.TrimSpace(role))
+		}
+		c.Next()
+	}, nil
+}
+
+// Role 获取受信任代理设置的用户角色，没有时返回空字符串
+func Role(c *gin.Context) string {
+	return c.GetString(roleKey)
+}
+
+// parseTrustedProxy 解析IP或CIDR
+func parseTrustedProxy(item string) (netip.Prefix, error) {
+	if strings.Contains(item, "/") {
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("无效的受信任代理地址 %q: %w", item, err)
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(item)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("无效的受信任代理地址 %q: %w", item, err)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// trustedPeer 对端地址是否在受信任列表中
+func trustedPeer(remoteAddr string, prefixes []netip.Prefix) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
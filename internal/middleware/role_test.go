@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTrustedRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		proxies    []string
+		remoteAddr string
+		role       string
+		want       string
+	}{
+		{name: "受信任的IPv4", proxies: []string{"127.0.0.1"}, remoteAddr: "127.0.0.1:5000", role: "admin", want: "admin"},
+		{name: "受信任的IPv6", proxies: []string{"::1"}, remoteAddr: "[::1]:5000", role: "admin", want: "admin"},
+		{name: "IPv4映射的IPv6地址", proxies: []string{"10.0.0.1"}, remoteAddr: "[::ffff:10.0.0.1]:5000", role: "admin", want: "admin"},
+		{name: "CIDR", proxies: []string{"10.1.0.0/16"}, remoteAddr: "10.1.2.3:5000", role: " auditor ", want: "auditor"},
+		{name: "不在列表中", proxies: []string{"127.0.0.1"}, remoteAddr: "192.0.2.2:5000", role: "admin", want: ""},
+		{name: "没有受信任代理", proxies: nil, remoteAddr: "127.0.0.1:5000", role: "admin", want: ""},
+		{name: "没有请求头", proxies: []string{"127.0.0.1"}, remoteAddr: "127.0.0.1:5000", want: ""},
+		{name: "无效的对端地址", proxies: []string{"127.0.0.1"}, remoteAddr: "pipe", role: "admin", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, err := TrustedRole("X-User-Role", tt.proxies)
+			if err != nil {
+				t.Fatalf("TrustedRole() 错误: %v", err)
+			}
+
+			var role, header string
+			r := gin.New()
+			r.Use(handler)
+			r.GET("/", func(c *gin.Context) {
+				role = Role(c)
+				header = c.GetHeader("X-User-Role")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.role != "" {
+				req.Header.Set("X-User-Role", tt.role)
+			}
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			if role != tt.want {
+				t.Errorf("Role() = %q, 期望 %q", role, tt.want)
+			}
+			if header != "" {
+				t.Errorf("请求头应被删除，得到 %q", header)
+			}
+		})
+	}
+}
+
+func TestTrustedRoleInvalidProxy(t *testing.T) {
+	for _, proxy := range []string{"localhost", "10.0.0.0/33", ""} {
+		if _, err := TrustedRole("X-User-Role", []string{proxy}); err == nil {
+			t.Errorf("TrustedRole(%q) 应返回错误", proxy)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+// 文件比较状态
+const (
+	CompareAdded     = "added"
+	CompareRemoved   = "removed"
+	CompareChanged   = "changed"
+	CompareUnchanged = "unchanged"
+)
+
+// CompareFile 两个日志包中同一路径文件的比较结果
+type CompareFile struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	SizeA  int64  `json:"size_a,omitempty"`
+	SizeB  int64  `json:"size_b,omitempty"`
+	HashA  string `json:"hash_a,omitempty"` // 大小相同时才计算哈希
+	HashB  string `json:"hash_b,omitempty"`
+}
+
+// CompareSummary 比较统计
+type CompareSummary struct {
+	Added     int `json:"added"`
+	Removed   int `json:"removed"`
+	Changed   int `json:"changed"`
+	Unchanged int `json:"unchanged"`
+}
+
+// CompareResult 两个日志包文件树的比较结果
+type CompareResult struct {
+	A       string         `json:"a"`
+	B       string         `json:"b"`
+	Summary CompareSummary `json:"summary"`
+	Files   []CompareFile  `json:"files"` // 只包含有差异的文件，按路径排序
+}
@@ -0,0 +1,67 @@
+package models
+
+// FileRef 日志包内的文件引用（log_id:path）
+type FileRef struct {
+	LogID string `json:"log_id"`
+	Path  string `json:"path"`
+}
+
+// DiffOptions 文件比较选项
+type DiffOptions struct {
+	Mode             string // unified 或 side-by-side
+	Context          int    // 上下文行数
+	IgnoreWhitespace bool   // 忽略空白差异
+	IgnoreTimestamps bool   // 忽略时间戳差异
+	Raw              bool   // 返回未脱敏的原始内容
+}
+
+// DiffLine unified模式下的一行（type: context/add/delete）
+type DiffLine struct {
+	Type  string `json:"type"`
+	ALine int    `json:"a_line,omitempty"`
+	BLine int    `json:"b_line,omitempty"`
+	Text  string `json:"text"`
+}
+
+// DiffCell 并排模式下一侧的单元格
+type DiffCell struct {
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// DiffRow 并排模式下的一行（type: equal/change/add/delete）
+type DiffRow struct {
+	Type  string    `json:"type"`
+	Left  *DiffCell `json:"left,omitempty"`
+	Right *DiffCell `json:"right,omitempty"`
+}
+
+// DiffHunk 差异块
+type DiffHunk struct {
+	Header string     `json:"header"`
+	AStart int        `json:"a_start"`
+	ALines int        `json:"a_lines"`
+	BStart int        `json:"b_start"`
+	BLines int        `json:"b_lines"`
+	Lines  []DiffLine `json:"lines,omitempty"`
+	Rows   []DiffRow  `json:"rows,omitempty"`
+}
+
+// DiffStats 差异统计
+type DiffStats struct {
+	Added     int `json:"added"`
+	Removed   int `json:"removed"`
+	Unchanged int `json:"unchanged"`
+}
+
+// DiffResult 文件比较结果
+type DiffResult struct {
+	A         FileRef    `json:"a"`
+	B         FileRef    `json:"b"`
+	Mode      string     `json:"mode"`
+	Identical bool       `json:"identical"`
+	Truncated bool       `json:"truncated,omitempty"` // 文件过大，只比较了前面部分
+	Stats     DiffStats  `json:"stats"`
+	Hunks     []DiffHunk `json:"hunks"`
+	Patch     string     `json:"patch,omitempty"` // unified模式下的补丁文本
+}
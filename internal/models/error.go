@@ -36,6 +36,7 @@ func NewSuccessResponse(data interface{}) SuccessResponse {
 const (
 	StatusOK                  = http.StatusOK
 	StatusBadRequest          = http.StatusBadRequest
+	StatusForbidden           = http.StatusForbidden
 	StatusNotFound            = http.StatusNotFound
 	StatusInternalServerError = http.StatusInternalServerError
 )
@@ -50,4 +51,32 @@ const (
 	ErrInvalidLogID      = "无效的日志ID"
 	ErrDeviceCheckFailed = "设备检测失败"
 	ErrDeviceTimeout     = "设备检测超时"
+	ErrEmptyQuery        = "搜索关键词不能为空"
+	ErrUnknownLogFormat  = "不支持的日志格式"
+	ErrInvalidLevel      = "无效的日志级别"
+	ErrInvalidTime       = "无效的时间参数"
+	ErrNoFilesSelected   = "请选择至少一个文件"
+	ErrTooManyFiles      = "选择的文件过多"
+	ErrDuplicateFile     = "文件重复选择"
+	ErrInvalidCursor     = "无效的分页游标"
+	ErrInvalidFileRef    = "无效的文件引用，格式应为 log_id:path"
+	ErrInvalidDiffMode   = "无效的比较模式，可选 unified 或 side-by-side"
+	ErrInvalidBucket     = "无效的时间桶大小，例如 60、5m、1h"
+	ErrEmptyExpr         = "查询表达式不能为空"
+	ErrInvalidExpr       = "无效的查询表达式"
+	ErrSearchNotFound    = "保存的搜索不存在"
+	ErrEmptyName         = "名称不能为空"
+	ErrInvalidPattern    = "无效的正则表达式"
+	ErrInvalidGlob       = "无效的文件匹配模式"
+	ErrRawForbidden      = "当前角色无权查看未脱敏的原始内容"
+	ErrNoGoroutineDump   = "文件中没有goroutine转储"
+	ErrInvalidDump       = "无效的转储序号"
+	ErrRuleNotFound      = "规则不存在"
+	ErrEmptyRule         = "规则至少需要一个匹配条件"
+	ErrInvalidSeverity   = "无效的严重程度，可选 critical、high、medium、low、info"
+	ErrInvalidRuleMatch  = "无效的条件组合方式，可选 all 或 any"
+	ErrInvalidFormat     = "无效的报告格式，可选 json、markdown 或 html"
+	ErrInvalidFact       = "无效的元数据过滤条件，格式应为 name 或 name=value"
+	ErrInvalidSort       = "无效的排序方式，可选 time 或 size"
+	ErrSearchUnavailable = "全文索引不可用，请使用 -tags sqlite_fts5 重新构建"
 )
\ No newline at end of file
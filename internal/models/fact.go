@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Fact 从日志包中的已知文件提取的元数据
+type Fact struct {
+	Name      string    `json:"name"`
+	Value     string    `json:"value"`
+	FilePath  string    `json:"file_path"` // 提取来源文件
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FactResult 日志的元数据
+type FactResult struct {
+	LogID string `json:"log_id"`
+	Facts []Fact `json:"facts"`
+}
+
+// FactFilter 日志列表的元数据过滤条件，Value 为空时只要求存在该元数据，支持 * 通配符
+type FactFilter struct {
+	Name  string
+	Value string
+}
@@ -0,0 +1,56 @@
+package models
+
+// StackFrame 栈帧
+type StackFrame struct {
+	Func string `json:"func"`
+	Args string `json:"args,omitempty"` // 组内第一个goroutine的参数
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+// GoroutineGroup 状态和调用栈相同的一组goroutine
+type GoroutineGroup struct {
+	ID             int          `json:"id"`
+	Count          int          `json:"count"`
+	State          string       `json:"state"`
+	MinWait        int          `json:"min_wait_minutes"` // 等待时长（分钟），转储中只记录超过1分钟的等待
+	MaxWait        int          `json:"max_wait_minutes"`
+	LockedToThread int          `json:"locked_to_thread,omitempty"`
+	Goroutines     []int        `json:"goroutines"` // goroutine ID（最多返回100个）
+	Frames         []StackFrame `json:"frames"`
+	Elided         bool         `json:"elided,omitempty"`
+	CreatedBy      *StackFrame  `json:"created_by,omitempty"`
+	Panicking      bool         `json:"panicking,omitempty"`
+}
+
+// 死锁/阻塞提示类型
+const (
+	HintRuntimeDeadlock = "runtime-deadlock" // 运行时检测到所有goroutine都在等待
+	HintBlockedForever  = "blocked-forever"  // nil channel 或空 select，永远不会被唤醒
+	HintLongLockWait    = "long-lock-wait"   // 长时间等待锁
+	HintLockContention  = "lock-contention"  // 大量goroutine在同一位置等待锁
+)
+
+// GoroutineHint 可能的死锁或阻塞
+type GoroutineHint struct {
+	Kind       string `json:"kind"`
+	Message    string `json:"message"`
+	Groups     []int  `json:"groups"`
+	Goroutines int    `json:"goroutines"`
+}
+
+// GoroutineAnalysis goroutine转储分析结果
+type GoroutineAnalysis struct {
+	LogID          string           `json:"log_id"`
+	Path           string           `json:"path"`
+	Dump           int              `json:"dump"`  // 当前分析的转储（从1开始）
+	Dumps          int              `json:"dumps"` // 文件中的转储数量
+	Line           int              `json:"line"`  // 转储在文件中的起始行
+	Total          int              `json:"total"`
+	Panic          string           `json:"panic,omitempty"`
+	Signal         string           `json:"signal,omitempty"`
+	PanicGoroutine int              `json:"panic_goroutine,omitempty"`
+	States         map[string]int   `json:"states"`
+	Groups         []GoroutineGroup `json:"groups"`
+	Hints          []GoroutineHint  `json:"hints"`
+}
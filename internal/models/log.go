@@ -11,6 +11,29 @@ type Log struct {
 	DownloadTime time.Time `json:"download_time"`
 	Tags         string    `json:"tags"`
 	Notes        string    `json:"notes"`
+	Size         int64     `json:"size"`      // 解压后的总大小（字节），导入时记录
+	DiskSize     int64     `json:"disk_size"` // 在磁盘上实际占用的大小（字节），压缩保存时小于 size
+
+	Status      string          `json:"status"`                 // 导入处理状态：pending、processing、ready、failed
+	StatusError string          `json:"status_error,omitempty"` // 处理失败的步骤和原因
+	Progress    *ImportProgress `json:"progress,omitempty"`     // 正在处理时的进度
+
+	Facts map[string]string `json:"facts,omitempty"` // 提取的元数据（列表接口返回）
+}
+
+// 导入处理状态：下载解压完成后，索引、规则扫描、概览等在后台依次执行
+const (
+	ImportPending    = "pending"
+	ImportProcessing = "processing"
+	ImportReady      = "ready"
+	ImportFailed     = "failed"
+)
+
+// ImportProgress 导入处理进度
+type ImportProgress struct {
+	Step  string `json:"step"`  // 正在执行的步骤
+	Done  int    `json:"done"`  // 已完成的步骤数
+	Total int    `json:"total"` // 总步骤数
 }
 
 // RemoteLog 远程日志模型
@@ -25,16 +48,109 @@ type RemoteLog struct {
 type FileNode struct {
 	Name     string      `json:"name"`
 	Path     string      `json:"path"`
-	Type     string      `json:"type"` // "file" or "directory"
-	Size     int64       `json:"size,omitempty"`
+	Type     string      `json:"type"`             // "file" or "directory"
+	Size     int64       `json:"size,omitempty"`   // 目录为其下所有文件的总大小
+	Binary   bool        `json:"binary,omitempty"` // 二进制文件，内容接口返回十六进制视图
+	ModTime  time.Time   `json:"mod_time"`         // 压缩包中记录的修改时间
 	Children []*FileNode `json:"children,omitempty"`
+
+	ChildCount int `json:"child_count,omitempty"` // 目录的直接子项数
+	FileCount  int `json:"file_count,omitempty"`  // 目录下（含子目录）的文件数
+
+	// 以下字段来自导入后的索引，未索引的文件为空
+	ContentType string `json:"content_type,omitempty"` // json、jsonl、xml、yaml、html、text 或 binary
+	Encoding    string `json:"encoding,omitempty"`     // ascii、utf-8、utf-8-bom、gbk 或 unknown
+	Compression string `json:"compression,omitempty"`
+	Lines       int    `json:"lines,omitempty"`
+	Errors      int    `json:"errors,omitempty"` // ERROR 和 FATAL 行数
+	Warnings    int    `json:"warnings,omitempty"`
+}
+
+// FileIndex 导入后对单个文件建立的索引
+type FileIndex struct {
+	Path        string
+	ContentType string
+	Encoding    string
+	Compression string
+	Lines       int
+	Errors      int
+	Warnings    int
+}
+
+// DirListing 单层目录列表（或按名称过滤的结果），分页返回
+type DirListing struct {
+	Path       string      `json:"path"`
+	Entries    []*FileNode `json:"entries"`
+	Total      int         `json:"total"` // 目录的子项数或过滤命中的总数
+	NextCursor string      `json:"next_cursor,omitempty"`
+	HasMore    bool        `json:"has_more"`
+}
+
+// DirOptions 目录列表查询选项，Query 或 Glob 非空时在 Path 下递归查找匹配的文件和目录
+type DirOptions struct {
+	Path   string
+	Query  string // 名称包含的关键词，不区分大小写
+	Glob   string // 匹配相对路径或文件名
+	Limit  int
+	Cursor string // 上一页返回的 next_cursor
 }
 
 // FileContent 文件内容模型
 type FileContent struct {
-	Content string `json:"content"`
-	Type    string `json:"type"` // json, xml, yaml, html, text, error
-	Size    int    `json:"size"`
+	Content string      `json:"content"`
+	Type    string      `json:"type"` // json, xml, yaml, html, text, hex, error
+	Size    int         `json:"size"`
+	Format  string      `json:"format,omitempty"`  // 日志格式（解析时返回）
+	Records []LogRecord `json:"records,omitempty"` // 解析后的日志记录
+
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty"` // JSON/JSON Lines/XML/YAML 语法错误
+
+	Compression string `json:"compression,omitempty"` // 原文件的压缩格式（gzip/zstd/bzip2/xz），内容为解压后的文本
+	BinaryType  string `json:"binary_type,omitempty"` // 二进制文件类型（elf、sqlite等，未知为data），此时content为十六进制视图，分页单位为行（16字节）
+
+	// 分页信息（分页或过滤时返回）
+	Paginated   bool  `json:"paginated,omitempty"`
+	TotalLines  int   `json:"total_lines,omitempty"` // 总行数，过滤时为匹配的行数
+	Offset      int   `json:"offset,omitempty"`
+	Limit       int   `json:"limit,omitempty"`
+	Filtered    bool  `json:"filtered,omitempty"`
+	LineNumbers []int `json:"line_numbers,omitempty"` // 当前页各行在原文件中的行号
+}
+
+// FileContentOptions 文件内容读取选项
+type FileContentOptions struct {
+	Parse  bool      // 是否返回解析后的记录
+	Format string    // 指定日志格式，为空时自动检测
+	Offset int       // 起始行（从0开始，过滤时为匹配结果中的偏移）
+	Limit  int       // 每页行数，0表示不分页
+	Levels []string  // 级别过滤（标准级别）
+	Since  time.Time // 起始时间（含）
+	Until  time.Time // 结束时间（含）
+	Raw    bool      // 返回未脱敏的原始内容
+}
+
+// HasFilter 是否设置了过滤条件
+func (o FileContentOptions) HasFilter() bool {
+	return len(o.Levels) > 0 || !o.Since.IsZero() || !o.Until.IsZero()
+}
+
+// Diagnostic 语法错误位置，行号为原文件中的行号，列号未知时为0
+type Diagnostic struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// LogRecord 解析后的日志记录
+type LogRecord struct {
+	Line      int               `json:"line"`
+	EndLine   int               `json:"end_line,omitempty"` // 多行事件（堆栈、panic等）的最后一行
+	Timestamp string            `json:"timestamp,omitempty"`
+	Level     string            `json:"level,omitempty"`
+	Component string            `json:"component,omitempty"`
+	PID       int               `json:"pid,omitempty"`
+	Message   string            `json:"message"`
+	Fields    map[string]string `json:"fields,omitempty"`
 }
 
 // DownloadRequest 下载请求
@@ -0,0 +1,39 @@
+package models
+
+// PatternExample 模板的示例行
+type PatternExample struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Content string `json:"content"`
+}
+
+// Pattern 日志模板（数字、IP、UUID、路径等可变部分已替换为占位符）
+type Pattern struct {
+	ID        int              `json:"id"`
+	Template  string           `json:"template"`
+	Count     int              `json:"count"`
+	Level     string           `json:"level,omitempty"` // 出现最多的级别
+	FirstSeen string           `json:"first_seen,omitempty"`
+	LastSeen  string           `json:"last_seen,omitempty"`
+	Files     []string         `json:"files"`
+	Examples  []PatternExample `json:"examples"`
+}
+
+// PatternResult 模板挖掘结果
+type PatternResult struct {
+	LogID      string    `json:"log_id"`
+	File       string    `json:"file,omitempty"` // 为空时为整个日志包
+	TotalLines int       `json:"total_lines"`    // 参与归并的行数
+	Total      int       `json:"total"`          // 过滤前的模板总数
+	Truncated  bool      `json:"truncated,omitempty"`
+	Patterns   []Pattern `json:"patterns"`
+}
+
+// PatternOptions 模板挖掘选项
+type PatternOptions struct {
+	File     string   // 为空时分析整个日志包
+	Levels   []string // 只分析指定级别的行
+	MinCount int
+	Limit    int
+	Raw      bool // 返回未脱敏的模板和示例
+}
@@ -0,0 +1,23 @@
+package models
+
+// QueryMatch 表达式的一个输出结果
+type QueryMatch struct {
+	Line  int         `json:"line"` // 来源记录在文件中的行号
+	Value interface{} `json:"value"`
+}
+
+// QueryResult JSON日志查询结果
+type QueryResult struct {
+	Path      string       `json:"path"`
+	Expr      string       `json:"expr"`
+	Offset    int          `json:"offset"`
+	Limit     int          `json:"limit"`
+	Total     int          `json:"total"`                // 已得到的输出结果数，has_more 为true时只统计到下一页的第一个结果
+	HasMore   bool         `json:"has_more"`             // 还有下一页，查询在得到当前页后停止
+	Records   int          `json:"records"`              // 参与计算的JSON记录数（只统计已读取的部分）
+	Skipped   int          `json:"skipped"`              // 不是JSON的行数（只统计已读取的部分）
+	Errors    int          `json:"errors"`               // 计算出错的记录数（只统计已读取的部分）
+	LastError string       `json:"last_error,omitempty"` // 最后一次计算错误
+	Truncated bool         `json:"truncated,omitempty"`  // 超时，统计不完整
+	Results   []QueryMatch `json:"results"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// 删除原因
+const (
+	RetentionMaxAge     = "max_age"
+	RetentionMaxBundles = "max_bundles"
+	RetentionMaxBytes   = "max_total_bytes"
+)
+
+// RetentionPolicy 保留策略，限制为0表示不限制
+type RetentionPolicy struct {
+	MaxAgeDays    int      `json:"max_age_days"`
+	MaxTotalBytes int64    `json:"max_total_bytes"`
+	MaxBundles    int      `json:"max_bundles"`
+	ExemptTags    []string `json:"exempt_tags"`
+	Interval      int      `json:"interval_minutes"` // 后台清理间隔，0表示不自动清理
+}
+
+// RetentionCandidate 按保留策略需要删除的日志
+type RetentionCandidate struct {
+	LogID        string    `json:"log_id"`
+	DownloadTime time.Time `json:"download_time"`
+	Tags         string    `json:"tags"`
+	Size         int64     `json:"size"`         // 在磁盘上实际占用的大小
+	LogicalSize  int64     `json:"logical_size"` // 解压后的大小
+	Reason       string    `json:"reason"`       // max_age、max_bundles 或 max_total_bytes
+	Error        string    `json:"error,omitempty"`
+}
+
+// RetentionPlan 保留策略的执行计划或执行结果
+type RetentionPlan struct {
+	Policy     RetentionPolicy      `json:"policy"`
+	DryRun     bool                 `json:"dry_run"`
+	Bundles    int                  `json:"bundles"`     // 执行前的日志数量
+	TotalBytes int64                `json:"total_bytes"` // 执行前在磁盘上实际占用的总大小
+	Exempt     int                  `json:"exempt"`      // 带有豁免标签的日志数量
+	Evict      []RetentionCandidate `json:"evict"`
+	FreedBytes int64                `json:"freed_bytes"`
+	RunAt      time.Time            `json:"run_at"`
+}
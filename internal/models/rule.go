@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+// 规则严重程度
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityInfo     = "info"
+)
+
+// 多个条件的组合方式
+const (
+	RuleMatchAll = "all" // 所有条件都在文件中出现
+	RuleMatchAny = "any" // 任一条件在文件中出现
+)
+
+// RuleCondition 匹配条件，negate 为 true 时要求文件中不出现该内容
+type RuleCondition struct {
+	Pattern string `json:"pattern"`
+	Negate  bool   `json:"negate,omitempty"`
+}
+
+// Rule 已知问题规则：在匹配 file_glob 的文件中出现指定内容时产生一条发现
+type Rule struct {
+	ID          int64           `json:"id"`
+	Name        string          `json:"name"`
+	FileGlob    string          `json:"file_glob"`  // 为空时检查所有文本文件
+	Pattern     string          `json:"pattern"`    // 单个正则，与 conditions 合并使用
+	Conditions  []RuleCondition `json:"conditions"` // 多条件匹配，以文件为单位判断
+	Match       string          `json:"match"`      // all 或 any
+	Severity    string          `json:"severity"`
+	Remediation string          `json:"remediation"`
+	Enabled     bool            `json:"enabled"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// RuleRequest 创建或更新规则
+type RuleRequest struct {
+	Name        string          `json:"name"`
+	FileGlob    string          `json:"file_glob"`
+	Pattern     string          `json:"pattern"`
+	Conditions  []RuleCondition `json:"conditions"`
+	Match       string          `json:"match"`    // 默认 all
+	Severity    string          `json:"severity"` // 默认 medium
+	Remediation string          `json:"remediation"`
+	Enabled     *bool           `json:"enabled"` // 默认启用
+}
+
+// Finding 规则在日志文件中的匹配结果
+type Finding struct {
+	ID          int64     `json:"id"`
+	LogID       string    `json:"log_id"`
+	RuleID      int64     `json:"rule_id"`
+	RuleName    string    `json:"rule_name"`
+	Severity    string    `json:"severity"`
+	Remediation string    `json:"remediation"`
+	FilePath    string    `json:"file_path"`
+	Line        int       `json:"line"`    // 第一处匹配的行号
+	Content     string    `json:"content"` // 第一处匹配的行
+	Count       int       `json:"count"`   // 匹配的行数
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// FindingResult 日志的规则匹配结果
+type FindingResult struct {
+	LogID      string         `json:"log_id"`
+	Total      int            `json:"total"`
+	Severities map[string]int `json:"severities"`
+	Findings   []Finding      `json:"findings"`
+}
+
+// RuleScanResult 规则扫描统计
+type RuleScanResult struct {
+	LogID    string `json:"log_id"`
+	Rules    int    `json:"rules"`
+	Files    int    `json:"files"`
+	Findings int    `json:"findings"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// SavedSearch 保存的搜索（正则、级别和文件匹配模式），可以在任意日志包上重复执行
+type SavedSearch struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Pattern   string    `json:"pattern"`   // 正则表达式
+	Levels    []string  `json:"levels"`    // 只匹配这些级别的行，为空时不限
+	FileGlob  string    `json:"file_glob"` // 文件匹配模式（如 *.log、var/log/*），为空时搜索所有文本文件
+	Owner     string    `json:"owner"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SavedSearchRequest 创建或更新保存的搜索
+type SavedSearchRequest struct {
+	Name     string   `json:"name"`
+	Pattern  string   `json:"pattern"`
+	Levels   []string `json:"levels"`
+	FileGlob string   `json:"file_glob"`
+	Owner    string   `json:"owner"`
+}
+
+// GrepOptions 正则搜索选项
+type GrepOptions struct {
+	Pattern  string
+	Levels   []string
+	FileGlob string
+	Offset   int
+	Limit    int
+	Raw      bool // 返回未脱敏的原始内容
+}
+
+// GrepHit 正则搜索命中的事件
+type GrepHit struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`                 // 第一个命中的行
+	StartLine int    `json:"start_line,omitempty"` // 多行事件的起止行
+	EndLine   int    `json:"end_line,omitempty"`
+	Level     string `json:"level,omitempty"`
+	Content   string `json:"content"` // 命中行所在的整个事件
+}
+
+// GrepResult 正则搜索结果
+type GrepResult struct {
+	LogID  string         `json:"log_id"`
+	Search *SavedSearch   `json:"search,omitempty"`
+	Total  int            `json:"total"`
+	Files  map[string]int `json:"files"` // 各文件命中的行数
+	Offset int            `json:"offset"`
+	Limit  int            `json:"limit"`
+	Hits   []GrepHit      `json:"hits"`
+}
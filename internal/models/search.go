@@ -0,0 +1,38 @@
+package models
+
+// SearchHit 全文搜索命中的行
+type SearchHit struct {
+	LogID    string `json:"log_id"`
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line"`
+	Snippet  string `json:"snippet"` // HTML，日志内容已转义，命中部分用 <mark> 标签高亮
+
+	// 命中行属于多行事件（堆栈、panic等）时返回整个事件
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+	Event     string `json:"event,omitempty"`
+}
+
+// SearchBundle 单个日志包的命中统计
+type SearchBundle struct {
+	LogID string `json:"log_id"`
+	Hits  int    `json:"hits"`
+	Files int    `json:"files"`
+}
+
+// SearchResult 全文搜索结果
+type SearchResult struct {
+	Query   string         `json:"query"`
+	Total   int            `json:"total"`
+	Offset  int            `json:"offset"`
+	Limit   int            `json:"limit"`
+	Bundles []SearchBundle `json:"bundles"`
+	Hits    []SearchHit    `json:"hits"`
+}
+
+// IndexResult 索引结果
+type IndexResult struct {
+	LogID string `json:"log_id"`
+	Files int    `json:"files"`
+	Lines int    `json:"lines"`
+}
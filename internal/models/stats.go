@@ -0,0 +1,33 @@
+package models
+
+// StatsBucket 一个时间桶内各级别的行数
+type StatsBucket struct {
+	Start  string         `json:"start"`
+	Total  int            `json:"total"`
+	Levels map[string]int `json:"levels"`
+}
+
+// FileStats 单个文件的统计
+type FileStats struct {
+	Path      string         `json:"path"`
+	Format    string         `json:"format"`
+	Lines     int            `json:"lines"`
+	Levels    map[string]int `json:"levels"`
+	FirstTime string         `json:"first_time,omitempty"`
+	LastTime  string         `json:"last_time,omitempty"`
+}
+
+// LogStats 日志包的级别/时间分布统计
+type LogStats struct {
+	LogID         string         `json:"log_id"`
+	TotalLines    int            `json:"total_lines"`
+	UntimedLines  int            `json:"untimed_lines"` // 无法确定时间、未计入时间桶的行数
+	Levels        map[string]int `json:"levels"`
+	Start         string         `json:"start,omitempty"`
+	End           string         `json:"end,omitempty"`
+	BucketSeconds int64          `json:"bucket_seconds"`
+	Buckets       []StatsBucket  `json:"buckets"`
+	Files         []FileStats    `json:"files"`
+	ComputedAt    string         `json:"computed_at"`
+	Cached        bool           `json:"cached"`
+}
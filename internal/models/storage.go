@@ -0,0 +1,30 @@
+package models
+
+// BundleUsage 单个日志包占用的空间
+type BundleUsage struct {
+	LogID       string `json:"log_id"`
+	Size        int64  `json:"size"`         // 在磁盘上实际占用的大小
+	LogicalSize int64  `json:"logical_size"` // 解压后的大小，压缩保存时大于 size
+	InDB        bool   `json:"in_db"`        // 数据库中有记录
+	OnDisk      bool   `json:"on_disk"`      // 解压目录存在
+}
+
+// VolumeUsage 解压目录所在卷的空间
+type VolumeUsage struct {
+	Total     uint64 `json:"total"`
+	Free      uint64 `json:"free"`
+	Available uint64 `json:"available"`
+}
+
+// StorageStats 存储使用情况
+type StorageStats struct {
+	ExtractDir string        `json:"extract_dir"`
+	TotalBytes int64         `json:"total_bytes"` // 所有解压目录在磁盘上实际占用的大小之和
+	Bundles    []BundleUsage `json:"bundles"`     // 按下载时间从新到旧，只在磁盘上的目录排在最后
+	Largest    []BundleUsage `json:"largest"`
+	Volume     *VolumeUsage  `json:"volume,omitempty"` // 当前平台不支持时为空
+	DBCount    int           `json:"db_count"`
+	DiskCount  int           `json:"disk_count"`
+	Orphaned   []string      `json:"orphaned"` // 磁盘上有目录但数据库中没有记录
+	Missing    []string      `json:"missing"`  // 数据库中有记录但解压目录不存在
+}
@@ -0,0 +1,35 @@
+package models
+
+// SummaryFile 文件大小
+type SummaryFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// SummaryFileLevels 单个文件的错误/警告行数
+type SummaryFileLevels struct {
+	Path     string `json:"path"`
+	Lines    int    `json:"lines"`
+	Errors   int    `json:"errors"` // ERROR 和 FATAL
+	Warnings int    `json:"warnings"`
+}
+
+// BundleSummary 日志包概览
+type BundleSummary struct {
+	LogID        string              `json:"log_id"`
+	Files        int                 `json:"files"`
+	TextFiles    int                 `json:"text_files"`
+	TotalSize    int64               `json:"total_size"`
+	LargestFiles []SummaryFile       `json:"largest_files"`
+	Start        string              `json:"start,omitempty"`
+	End          string              `json:"end,omitempty"`
+	TotalLines   int                 `json:"total_lines"`
+	Errors       int                 `json:"errors"`
+	Warnings     int                 `json:"warnings"`
+	FileLevels   []SummaryFileLevels `json:"file_levels"` // 只包含有错误或警告的文件，按错误数降序
+	TopErrors    []Pattern           `json:"top_errors"`
+	Severities   map[string]int      `json:"severities"`
+	Findings     []Finding           `json:"findings"` // 读取时从规则匹配结果获取，规则重新扫描后即时更新
+	GeneratedAt  string              `json:"generated_at"`
+	Cached       bool                `json:"cached"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// TimelineEntry 时间线中的一个事件，多行事件（堆栈、panic等）的 Content 包含所有行
+type TimelineEntry struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	EndLine   int    `json:"end_line,omitempty"` // 多行事件的最后一行
+	Timestamp string `json:"timestamp,omitempty"`
+	Level     string `json:"level,omitempty"`
+	Content   string `json:"content"`
+}
+
+// TimelineResult 多文件合并时间线
+type TimelineResult struct {
+	Files      []string        `json:"files"`
+	Entries    []TimelineEntry `json:"entries"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	HasMore    bool            `json:"has_more"`
+}
+
+// TimelineOptions 时间线查询选项
+type TimelineOptions struct {
+	Files  []string
+	Since  time.Time
+	Until  time.Time
+	Levels []string
+	Limit  int
+	Cursor string // 上一页返回的 next_cursor
+	Raw    bool   // 返回未脱敏的原始内容
+}
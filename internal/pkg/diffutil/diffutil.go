@@ -0,0 +1,310 @@
+package diffutil
+
+import (
+	"strings"
+)
+
+// Myers算法的资源上限，超出后差异部分按整块删除/新增处理
+const (
+	// maxEditDistance 最大编辑距离，每轮保存的V数组总共占用 O(D^2) 内存（约 D^2 个int）
+	maxEditDistance = 1000
+	// maxSnakeSteps 沿对角线比较的总次数，限制 O((N+M)·D) 的比较开销
+	maxSnakeSteps = 1 << 24
+)
+
+// OpKind 编辑操作类型
+type OpKind int
+
+const (
+	// Equal 两侧相同
+	Equal OpKind = iota
+	// Delete 仅在A侧
+	Delete
+	// Insert 仅在B侧
+	Insert
+)
+
+// Op 单行编辑操作，AIndex/BIndex 为0起始的行下标，不适用的一侧为-1
+type Op struct {
+	Kind   OpKind
+	AIndex int
+	BIndex int
+}
+
+// Options 比较选项
+type Options struct {
+	IgnoreWhitespace bool                // 忽略空白差异（首尾空白、连续空白）
+	Normalize        func(string) string // 自定义归一化，如替换时间戳
+}
+
+// Hunk 差异块
+type Hunk struct {
+	AStart int // 1起始行号
+	ALines int
+	BStart int
+	BLines int
+	Ops    []Op
+}
+
+// Lines 比较两组行，返回完整的编辑序列
+func Lines(a, b []string, opts Options) []Op {
+	ka, kb := intern(a, b, opts)
+
+	// 去掉公共前缀和后缀，只对中间部分运行Myers算法
+	prefix := 0
+	for prefix < len(ka) && prefix < len(kb) && ka[prefix] == kb[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(ka)-prefix && suffix < len(kb)-prefix &&
+		ka[len(ka)-1-suffix] == kb[len(kb)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]Op, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, Op{Kind: Equal, AIndex: i, BIndex: i})
+	}
+	for _, op := range myers(ka[prefix:len(ka)-suffix], kb[prefix:len(kb)-suffix]) {
+		if op.AIndex >= 0 {
+			op.AIndex += prefix
+		}
+		if op.BIndex >= 0 {
+			op.BIndex += prefix
+		}
+		ops = append(ops, op)
+	}
+	for i := 0; i < suffix; i++ {
+		ops = append(ops, Op{Kind: Equal, AIndex: len(a) - suffix + i, BIndex: len(b) - suffix + i})
+	}
+	return ops
+}
+
+// Hunks 将编辑序列按上下文行数切分为差异块，间隔不超过 2*context 的变化合并为一块
+func Hunks(ops []Op, context int) []Hunk {
+	if context < 0 {
+		context = 0
+	}
+
+	var hunks []Hunk
+	i := 0
+	for i < len(ops) {
+		if ops[i].Kind == Equal {
+			i++
+			continue
+		}
+
+		first, last := i, i
+		j := i + 1
+		for j < len(ops) {
+			if ops[j].Kind != Equal {
+				last = j
+				j++
+				continue
+			}
+			k := j
+			for k < len(ops) && ops[k].Kind == Equal {
+				k++
+			}
+			if k < len(ops) && k-j <= 2*context {
+				j = k
+				continue
+			}
+			break
+		}
+
+		start := first - context
+		if start < 0 {
+			start = 0
+		}
+		end := last + 1 + context
+		if end > len(ops) {
+			end = len(ops)
+		}
+		hunks = append(hunks, newHunk(ops, start, end))
+		i = last + 1
+	}
+	return hunks
+}
+
+// newHunk 创建差异块并计算两侧的起始行号和行数
+func newHunk(ops []Op, start, end int) Hunk {
+	h := Hunk{Ops: ops[start:end]}
+	h.AStart, h.BStart = -1, -1
+	for _, op := range h.Ops {
+		if op.AIndex >= 0 {
+			if h.AStart < 0 {
+				h.AStart = op.AIndex + 1
+			}
+			h.ALines++
+		}
+		if op.BIndex >= 0 {
+			if h.BStart < 0 {
+				h.BStart = op.BIndex + 1
+			}
+			h.BLines++
+		}
+	}
+
+	// 一侧没有行时，按 unified diff 约定使用前一行的行号（文件开头为0）
+	if h.AStart < 0 {
+		h.AStart = 0
+		for i := start - 1; i >= 0; i-- {
+			if ops[i].AIndex >= 0 {
+				h.AStart = ops[i].AIndex + 1
+				break
+			}
+		}
+	}
+	if h.BStart < 0 {
+		h.BStart = 0
+		for i := start - 1; i >= 0; i-- {
+			if ops[i].BIndex >= 0 {
+				h.BStart = ops[i].BIndex + 1
+				break
+			}
+		}
+	}
+	return h
+}
+
+// intern 将归一化后的行映射为整数，加快比较
+func intern(a, b []string, opts Options) ([]int, []int) {
+	ids := make(map[string]int)
+	convert := func(lines []string) []int {
+		keys := make([]int, len(lines))
+		for i, line := range lines {
+			key := normalize(line, opts)
+			id, ok := ids[key]
+			if !ok {
+				id = len(ids)
+				ids[key] = id
+			}
+			keys[i] = id
+		}
+		return keys
+	}
+	return convert(a), convert(b)
+}
+
+// normalize 按选项归一化单行
+func normalize(line string, opts Options) string {
+	if opts.Normalize != nil {
+		line = opts.Normalize(line)
+	}
+	if opts.IgnoreWhitespace {
+		line = strings.Join(strings.Fields(line), " ")
+	}
+	return line
+}
+
+// myers Myers O(ND) 差分算法，编辑距离或比较次数超过上限时退化为整块替换
+func myers(a, b []int) []Op {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return blockReplace(a, b)
+	}
+
+	max := n + m
+	if max > maxEditDistance {
+		max = maxEditDistance
+	}
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	var trace [][]int
+
+	found := -1
+	steps := 0
+	for d := 0; d <= max && steps <= maxSnakeSteps; d++ {
+		// 只保存本轮用到的区间，控制内存为 O(D^2)
+		snapshot := make([]int, 2*d+3)
+		copy(snapshot, v[offset-d-1:offset+d+2])
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			start := x
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			steps += x - start + 1
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = d
+				break
+			}
+		}
+		if found >= 0 {
+			break
+		}
+	}
+
+	if found < 0 {
+		return blockReplace(a, b)
+	}
+	return backtrack(trace, a, b, found)
+}
+
+// backtrack 根据每轮保存的V数组回溯出编辑序列
+func backtrack(trace [][]int, a, b []int, d int) []Op {
+	x, y := len(a), len(b)
+	var reversed []Op
+
+	for ; d > 0; d-- {
+		snapshot := trace[d]
+		get := func(k int) int { return snapshot[k+d+1] }
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && get(k-1) < get(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := get(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, Op{Kind: Equal, AIndex: x, BIndex: y})
+		}
+		if x == prevX {
+			y--
+			reversed = append(reversed, Op{Kind: Insert, AIndex: -1, BIndex: y})
+		} else {
+			x--
+			reversed = append(reversed, Op{Kind: Delete, AIndex: x, BIndex: -1})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		reversed = append(reversed, Op{Kind: Equal, AIndex: x, BIndex: y})
+	}
+
+	ops := make([]Op, len(reversed))
+	for i, op := range reversed {
+		ops[len(reversed)-1-i] = op
+	}
+	return ops
+}
+
+// blockReplace 生成整块删除A、整块新增B的编辑序列
+func blockReplace(a, b []int) []Op {
+	ops := make([]Op, 0, len(a)+len(b))
+	for i := range a {
+		ops = append(ops, Op{Kind: Delete, AIndex: i, BIndex: -1})
+	}
+	for i := range b {
+		ops = append(ops, Op{Kind: Insert, AIndex: -1, BIndex: i})
+	}
+	return ops
+}
@@ -0,0 +1,236 @@
+package diffutil
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// render 将编辑序列转换为 unified diff 风格的行，便于比较
+func render(ops []Op, a, b []string) []string {
+	lines := make([]string, 0, len(ops))
+	for _, op := range ops {
+		switch op.Kind {
+		case Equal:
+			lines = append(lines, " "+a[op.AIndex])
+		case Delete:
+			lines = append(lines, "-"+a[op.AIndex])
+		case Insert:
+			lines = append(lines, "+"+b[op.BIndex])
+		}
+	}
+	return lines
+}
+
+// checkOps 检查编辑序列按顺序覆盖了两侧的每一行
+func checkOps(t *testing.T, ops []Op, a, b []string) {
+	t.Helper()
+	ai, bi := 0, 0
+	for _, op := range ops {
+		switch op.Kind {
+		case Equal:
+			if op.AIndex != ai || op.BIndex != bi {
+				t.Fatalf("Equal 操作下标 (%d, %d), 期望 (%d, %d)", op.AIndex, op.BIndex, ai, bi)
+			}
+			ai++
+			bi++
+		case Delete:
+			if op.AIndex != ai || op.BIndex != -1 {
+				t.Fatalf("Delete 操作下标 (%d, %d), 期望 (%d, -1)", op.AIndex, op.BIndex, ai)
+			}
+			ai++
+		case Insert:
+			if op.AIndex != -1 || op.BIndex != bi {
+				t.Fatalf("Insert 操作下标 (%d, %d), 期望 (-1, %d)", op.AIndex, op.BIndex, bi)
+			}
+			bi++
+		}
+	}
+	if ai != len(a) || bi != len(b) {
+		t.Fatalf("编辑序列覆盖了 %d/%d 行，期望 %d/%d", ai, bi, len(a), len(b))
+	}
+}
+
+func TestLines(t *testing.T) {
+	timestamp := regexp.MustCompile(`^\d{2}:\d{2}:\d{2} `)
+
+	tests := []struct {
+		name string
+		a, b []string
+		opts Options
+		want []string
+	}{
+		{
+			name: "都为空",
+			want: []string{},
+		},
+		{
+			name: "相同",
+			a:    []string{"a", "b"},
+			b:    []string{"a", "b"},
+			want: []string{" a", " b"},
+		},
+		{
+			name: "全部新增",
+			b:    []string{"a", "b"},
+			want: []string{"+a", "+b"},
+		},
+		{
+			name: "全部删除",
+			a:    []string{"a", "b"},
+			want: []string{"-a", "-b"},
+		},
+		{
+			name: "中间替换",
+			a:    []string{"a", "b", "c"},
+			b:    []string{"a", "x", "c"},
+			want: []string{" a", "-b", "+x", " c"},
+		},
+		{
+			name: "插入和删除",
+			a:    []string{"a", "b", "c", "d"},
+			b:    []string{"a", "c", "d", "e"},
+			want: []string{" a", "-b", " c", " d", "+e"},
+		},
+		{
+			name: "最短编辑序列",
+			a:    strings.Split("ABCABBA", ""),
+			b:    strings.Split("CBABAC", ""),
+			want: []string{"-A", "-B", " C", "+B", " A", " B", "-B", " A", "+C"},
+		},
+		{
+			name: "忽略空白",
+			a:    []string{"a  b", " c"},
+			b:    []string{"a b", "c "},
+			opts: Options{IgnoreWhitespace: true},
+			want: []string{" a  b", "  c"},
+		},
+		{
+			name: "不忽略空白",
+			a:    []string{"a  b"},
+			b:    []string{"a b"},
+			want: []string{"-a  b", "+a b"},
+		},
+		{
+			name: "自定义归一化",
+			a:    []string{"10:00:00 start", "10:00:01 stop"},
+			b:    []string{"11:30:00 start", "11:30:05 stop"},
+			opts: Options{Normalize: func(s string) string { return timestamp.ReplaceAllString(s, "") }},
+			want: []string{" 10:00:00 start", " 10:00:01 stop"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := Lines(tt.a, tt.b, tt.opts)
+			checkOps(t, ops, tt.a, tt.b)
+			if got := render(ops, tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines() = %q, 期望 %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinesEditDistanceLimit(t *testing.T) {
+	// 编辑距离超过上限时按整块删除和新增处理，公共前后缀保持不变
+	n := maxEditDistance
+	a := []string{"head"}
+	b := []string{"head"}
+	for i := 0; i < n; i++ {
+		a = append(a, fmt.Sprintf("a%d", i))
+		b = append(b, fmt.Sprintf("b%d", i))
+	}
+	a = append(a, "tail")
+	b = append(b, "tail")
+
+	ops := Lines(a, b, Options{})
+	checkOps(t, ops, a, b)
+
+	counts := map[OpKind]int{}
+	for _, op := range ops {
+		counts[op.Kind]++
+	}
+	if counts[Equal] != 2 || counts[Delete] != n || counts[Insert] != n {
+		t.Errorf("操作数 = %v, 期望 Equal 2、Delete %d、Insert %d", counts, n, n)
+	}
+	if ops[1].Kind != Delete || ops[n+1].Kind != Insert {
+		t.Errorf("整块替换应先删除后新增")
+	}
+}
+
+func TestHunks(t *testing.T) {
+	lines := func(prefix string, n int) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = fmt.Sprintf("%s%d", prefix, i+1)
+		}
+		return out
+	}
+	// 第3行和第10行被修改，中间相隔6行相同内容
+	a := lines("l", 12)
+	b := append([]string(nil), a...)
+	b[2] = "changed3"
+	b[9] = "changed10"
+
+	tests := []struct {
+		name    string
+		a, b    []string
+		context int
+		want    [][4]int // AStart, ALines, BStart, BLines
+	}{
+		{name: "没有差异", a: a, b: a, context: 3, want: nil},
+		{name: "无上下文", a: a, b: b, context: 0, want: [][4]int{{3, 1, 3, 1}, {10, 1, 10, 1}}},
+		{name: "上下文不足以合并", a: a, b: b, context: 2, want: [][4]int{{1, 5, 1, 5}, {8, 5, 8, 5}}},
+		{name: "上下文相连时合并", a: a, b: b, context: 3, want: [][4]int{{1, 12, 1, 12}}},
+		{name: "负数上下文按0处理", a: a, b: b, context: -1, want: [][4]int{{3, 1, 3, 1}, {10, 1, 10, 1}}},
+		{name: "开头新增", a: []string{"x"}, b: []string{"new", "x"}, context: 0, want: [][4]int{{0, 0, 1, 1}}},
+		{name: "末尾删除", a: []string{"x", "y"}, b: []string{"x"}, context: 0, want: [][4]int{{2, 1, 1, 0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hunks := Hunks(Lines(tt.a, tt.b, Options{}), tt.context)
+			var got [][4]int
+			for _, h := range hunks {
+				got = append(got, [4]int{h.AStart, h.ALines, h.BStart, h.BLines})
+				if len(h.Ops) == 0 {
+					t.Errorf("差异块没有操作")
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Hunks() = %v, 期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinesWorstCaseBudget(t *testing.T) {
+	// 两侧交错出现少量重复行时编辑距离大且对角线很长，内存和耗时都应受上限约束
+	const n = 20000
+	a := make([]string, n)
+	b := make([]string, n)
+	for i := 0; i < n; i++ {
+		a[i] = fmt.Sprintf("l%d", i%3)
+		b[i] = fmt.Sprintf("l%d", i%5)
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	ops := Lines(a, b, Options{})
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	checkOps(t, ops, a, b)
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 32<<20 {
+		t.Errorf("分配内存 %d 字节，超过 32MB", alloc)
+	}
+	if elapsed > 5*time.Second {
+		t.Errorf("耗时 %v，超过 5s", elapsed)
+	}
+}
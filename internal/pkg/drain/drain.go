@@ -0,0 +1,199 @@
+package drain
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Wildcard 模板中的可变部分
+const Wildcard = "<*>"
+
+// Options 模板挖掘参数
+type Options struct {
+	Depth        int     // 前缀树深度（包含根节点、长度层和叶子层），至少为3
+	SimThreshold float64 // 归入已有模板所需的最小相似度
+	MaxChildren  int     // 每个内部节点的最大子节点数，超出后归入通配节点
+	MaxClusters  int     // 最大模板数，超出后新模板不再创建
+}
+
+// DefaultOptions 默认参数，与Drain论文中的推荐值一致
+func DefaultOptions() Options {
+	return Options{
+		Depth:        4,
+		SimThreshold: 0.4,
+		MaxChildren:  100,
+		MaxClusters:  5000,
+	}
+}
+
+// Cluster 日志模板
+type Cluster struct {
+	ID     int
+	Tokens []string
+	Count  int
+}
+
+// Template 模板文本
+func (c *Cluster) Template() string {
+	return strings.Join(c.Tokens, " ")
+}
+
+// maskRule 可变内容替换规则
+type maskRule struct {
+	pattern *regexp.Regexp
+	repl    string
+}
+
+// maskRules 按顺序替换，较具体的规则在前
+var maskRules = []maskRule{
+	{regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`), "<UUID>"},
+	{regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}(?::\d+)?\b`), "<IP>"},
+	{regexp.MustCompile(`\b(?:[0-9a-fA-F]{2}:){5}[0-9a-fA-F]{2}\b`), "<MAC>"},
+	{regexp.MustCompile(`(^|[\s=:("'\[])(?:/[\w.@%+~-]+)+/?`), "${1}<PATH>"},
+	{regexp.MustCompile(`\b0x[0-9a-fA-F]+\b|\b[0-9a-fA-F]{16,}\b`), "<HEX>"},
+	{regexp.MustCompile(`[-+]?\b\d+(?:\.\d+)?(?:[a-zA-Z]{1,3})?\b`), "<NUM>"},
+}
+
+// Mask 将数字、IP、UUID、路径等可变内容替换为占位符
+func Mask(line string) string {
+	for _, rule := range maskRules {
+		line = rule.pattern.ReplaceAllString(line, rule.repl)
+	}
+	return line
+}
+
+// node 前缀树节点
+type node struct {
+	children map[string]*node
+	clusters []*Cluster
+}
+
+func newNode() *node {
+	return &node{children: make(map[string]*node)}
+}
+
+// Miner Drain 风格的日志模板挖掘器，非并发安全
+type Miner struct {
+	opts     Options
+	root     *node
+	clusters []*Cluster
+}
+
+// NewMiner 创建模板挖掘器
+func NewMiner(opts Options) *Miner {
+	def := DefaultOptions()
+	if opts.Depth < 3 {
+		opts.Depth = def.Depth
+	}
+	if opts.SimThreshold <= 0 {
+		opts.SimThreshold = def.SimThreshold
+	}
+	if opts.MaxChildren <= 0 {
+		opts.MaxChildren = def.MaxChildren
+	}
+	if opts.MaxClusters <= 0 {
+		opts.MaxClusters = def.MaxClusters
+	}
+	return &Miner{opts: opts, root: newNode()}
+}
+
+// Add 将一行归入模板，返回所属模板；空行或模板数达到上限时返回nil
+func (m *Miner) Add(line string) *Cluster {
+	tokens := strings.Fields(Mask(line))
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	leaf := m.leaf(tokens)
+	if cluster := m.match(leaf.clusters, tokens); cluster != nil {
+		cluster.Count++
+		for i, token := range tokens {
+			if cluster.Tokens[i] != token {
+				cluster.Tokens[i] = Wildcard
+			}
+		}
+		return cluster
+	}
+
+	if len(m.clusters) >= m.opts.MaxClusters {
+		return nil
+	}
+	cluster := &Cluster{
+		ID:     len(m.clusters) + 1,
+		Tokens: append([]string(nil), tokens...),
+		Count:  1,
+	}
+	m.clusters = append(m.clusters, cluster)
+	leaf.clusters = append(leaf.clusters, cluster)
+	return cluster
+}
+
+// Clusters 返回所有模板（按创建顺序）
+func (m *Miner) Clusters() []*Cluster {
+	return m.clusters
+}
+
+// leaf 按长度和前几个词元定位叶子节点，不存在时创建
+func (m *Miner) leaf(tokens []string) *node {
+	cur := m.child(m.root, strconv.Itoa(len(tokens)))
+
+	for depth := 0; depth < m.opts.Depth-3 && depth < len(tokens); depth++ {
+		key := tokens[depth]
+		if hasDigit(key) || strings.HasPrefix(key, "<") {
+			key = Wildcard
+		}
+		if _, ok := cur.children[key]; !ok && len(cur.children) >= m.opts.MaxChildren {
+			key = Wildcard
+		}
+		cur = m.child(cur, key)
+	}
+	return cur
+}
+
+// child 获取或创建子节点
+func (m *Miner) child(parent *node, key string) *node {
+	n, ok := parent.children[key]
+	if !ok {
+		n = newNode()
+		parent.children[key] = n
+	}
+	return n
+}
+
+// match 在叶子节点中查找最相似的模板，相似度相同时优先通配符更多的模板
+func (m *Miner) match(clusters []*Cluster, tokens []string) *Cluster {
+	var best *Cluster
+	bestSim, bestParams := -1.0, -1
+	for _, cluster := range clusters {
+		if len(cluster.Tokens) != len(tokens) {
+			continue
+		}
+		same, params := 0, 0
+		for i, token := range cluster.Tokens {
+			if token == Wildcard {
+				params++
+			} else if token == tokens[i] {
+				same++
+			}
+		}
+		sim := float64(same) / float64(len(tokens))
+		if sim > bestSim || (sim == bestSim && params > bestParams) {
+			best, bestSim, bestParams = cluster, sim, params
+		}
+	}
+	if best == nil || bestSim < m.opts.SimThreshold {
+		return nil
+	}
+	return best
+}
+
+// hasDigit 判断词元是否包含数字
+func hasDigit(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package drain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"connect to 10.0.0.1:8080 failed", "connect to <IP> failed"},
+		{"request 550e8400-e29b-41d4-a716-446655440000 done", "request <UUID> done"},
+		{"iface aa:bb:cc:dd:ee:ff up", "iface <MAC> up"},
+		{"open /var/log/app.log: denied", "open <PATH>: denied"},
+		{"file=/tmp/x.txt", "file=<PATH>"},
+		{"addr 0xdeadbeef", "addr <HEX>"},
+		{"took 15ms, retry 3 of 5", "took <NUM>, retry <NUM> of <NUM>"},
+		{"no variables here", "no variables here"},
+	}
+	for _, tt := range tests {
+		if got := Mask(tt.in); got != tt.want {
+			t.Errorf("Mask(%q) = %q, 期望 %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMinerMerge(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  []string // 每个模板的文本
+		count []int
+	}{
+		{
+			name:  "数字被掩码后归入同一模板",
+			lines: []string{"user 1 logged in", "user 22 logged in", "user 333 logged in"},
+			want:  []string{"user <NUM> logged in"},
+			count: []int{3},
+		},
+		{
+			name:  "不同的词元合并为通配符",
+			lines: []string{"user alice logged in", "user bob logged in"},
+			want:  []string{"user <*> logged in"},
+			count: []int{2},
+		},
+		{
+			name:  "长度不同的行不合并",
+			lines: []string{"cache hit", "cache hit again"},
+			want:  []string{"cache hit", "cache hit again"},
+			count: []int{1, 1},
+		},
+		{
+			name:  "首个词元不同时位于不同的叶子节点",
+			lines: []string{"start worker a", "stop worker a"},
+			want:  []string{"start worker a", "stop worker a"},
+			count: []int{1, 1},
+		},
+		{
+			name:  "相似度低于阈值时创建新模板",
+			lines: []string{"job a b c d e", "job v w x y z"},
+			want:  []string{"job a b c d e", "job v w x y z"},
+			count: []int{1, 1},
+		},
+		{
+			name:  "空行被忽略",
+			lines: []string{"", "   ", "hello world"},
+			want:  []string{"hello world"},
+			count: []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMiner(DefaultOptions())
+			for _, line := range tt.lines {
+				m.Add(line)
+			}
+			var got []string
+			var count []int
+			for _, c := range m.Clusters() {
+				got = append(got, c.Template())
+				count = append(count, c.Count)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("模板 = %q, 期望 %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(count, tt.count) {
+				t.Errorf("计数 = %v, 期望 %v", count, tt.count)
+			}
+		})
+	}
+}
+
+func TestMinerMergedTemplate(t *testing.T) {
+	m := NewMiner(DefaultOptions())
+	m.Add("get key alpha ok")
+	m.Add("get key beta ok")
+	// 合并后的模板在通配位置接受任意词元
+	if c := m.Add("get key gamma ok"); c == nil || c.ID != 1 || c.Count != 3 {
+		t.Errorf("Add() = %+v, 期望归入模板1", c)
+	}
+	if got := m.Clusters()[0].Template(); got != "get key <*> ok" {
+		t.Errorf("Template() = %q", got)
+	}
+}
+
+func TestMinerMaxClusters(t *testing.T) {
+	m := NewMiner(Options{MaxClusters: 2})
+	m.Add("alpha one")
+	m.Add("beta two words")
+	if c := m.Add("gamma three more words"); c != nil {
+		t.Errorf("模板数达到上限后 Add() = %+v, 期望 nil", c)
+	}
+	if c := m.Add("alpha one"); c == nil || c.Count != 2 {
+		t.Errorf("已有模板仍应计数, Add() = %+v", c)
+	}
+	if n := len(m.Clusters()); n != 2 {
+		t.Errorf("模板数 = %d, 期望 2", n)
+	}
+}
+
+func TestNewMinerDefaults(t *testing.T) {
+	m := NewMiner(Options{Depth: 1, SimThreshold: -1})
+	if !reflect.DeepEqual(m.opts, DefaultOptions()) {
+		t.Errorf("opts = %+v, 期望 %+v", m.opts, DefaultOptions())
+	}
+}
@@ -0,0 +1,217 @@
+// Package extract 从日志包中的已知文件（系统版本、固件版本、运行时间、磁盘和网络配置等）提取元数据
+package extract
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/itchyny/gojq"
+)
+
+// Extractor 提取规则，将匹配 Path 的文件中的值提取为名为 Field 的元数据，Pattern 和 JSONPath 二选一
+//   - Pattern: 正则表达式，取命名分组 (?P<value>...)，没有时取第一个分组，都没有时取整个匹配
+//   - JSONPath: jq 表达式（如 .firmware.version），取第一个非空结果
+type Extractor struct {
+	Field    string `json:"field"`
+	Path     string `json:"path"` // 文件匹配模式，匹配相对路径或文件名
+	Pattern  string `json:"pattern,omitempty"`
+	JSONPath string `json:"json_path,omitempty"`
+}
+
+// Fact 提取到的元数据
+type Fact struct {
+	Field string
+	Value string
+}
+
+// valueGroup 正则中表示值的命名分组
+const valueGroup = "value"
+
+// maxValueLen 元数据值的最大长度
+const maxValueLen = 256
+
+// BuiltinExtractors 内置规则：系统版本、内核版本、主机名、运行时间、根分区使用率和默认网关
+func BuiltinExtractors() []Extractor {
+	return []Extractor{
+		{Field: "os_name", Path: "os-release", Pattern: `(?m)^NAME="?([^"\r\n]+)"?`},
+		{Field: "os_version", Path: "os-release", Pattern: `(?m)^VERSION_ID="?([^"\r\n]+)"?`},
+		{Field: "kernel_version", Path: "version", Pattern: `^Linux version (\S+)`},
+		{Field: "kernel_version", Path: "uname*", Pattern: `^Linux \S+ (\S+)`},
+		{Field: "hostname", Path: "hostname", Pattern: `^\s*(\S+)`},
+		{Field: "firmware_version", Path: "*firmware*", Pattern: `(?im)^\s*(?:firmware[ _-]?)?version\s*[:=]\s*"?([^"\s]+)`},
+		{Field: "uptime", Path: "uptime*", Pattern: `\bup\s+(.+?),\s+\d+\s+users?`},
+		{Field: "disk_usage_root", Path: "df*", Pattern: `(?m)\s(\d+%)\s+/\s*$`},
+		{Field: "default_gateway", Path: "*route*", Pattern: `(?m)^default via (\S+)`},
+		{Field: "default_gateway", Path: "*route*", Pattern: `(?m)^0\.0\.0\.0\s+(\d+\.\d+\.\d+\.\d+)`},
+	}
+}
+
+// LoadExtractors 从JSON文件读取规则列表
+func LoadExtractors(file string) ([]Extractor, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("读取元数据提取规则失败: %w", err)
+	}
+	var extractors []Extractor
+	if err := json.Unmarshal(data, &extractors); err != nil {
+		return nil, fmt.Errorf("解析元数据提取规则失败: %w", err)
+	}
+	return extractors, nil
+}
+
+// compiledExtractor 编译后的规则
+type compiledExtractor struct {
+	Extractor
+	re    *regexp.Regexp
+	group int
+	code  *gojq.Code
+}
+
+// Set 按顺序应用的提取规则，同一字段以第一个提取到的值为准
+type Set struct {
+	extractors []*compiledExtractor
+}
+
+// New 编译规则
+func New(extractors []Extractor) (*Set, error) {
+	s := &Set{}
+	for _, e := range extractors {
+		if e.Field == "" {
+			return nil, fmt.Errorf("元数据提取规则缺少字段名")
+		}
+		if e.Path == "" {
+			return nil, fmt.Errorf("元数据提取规则 %s 缺少 path", e.Field)
+		}
+		if _, err := path.Match(e.Path, ""); err != nil {
+			return nil, fmt.Errorf("元数据提取规则 %s 的 path 无效: %w", e.Field, err)
+		}
+		if (e.Pattern == "") == (e.JSONPath == "") {
+			return nil, fmt.Errorf("元数据提取规则 %s 需要设置 pattern 或 json_path 之一", e.Field)
+		}
+
+		c := &compiledExtractor{Extractor: e}
+		if e.Pattern != "" {
+			re, err := regexp.Compile(e.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("元数据提取规则 %s 的正则无效: %w", e.Field, err)
+			}
+			c.re = re
+			if c.group = re.SubexpIndex(valueGroup); c.group < 0 {
+				c.group = min(1, re.NumSubexp())
+			}
+		} else {
+			query, err := gojq.Parse(e.JSONPath)
+			if err != nil {
+				return nil, fmt.Errorf("元数据提取规则 %s 的 json_path 无效: %w", e.Field, err)
+			}
+			if c.code, err = gojq.Compile(query); err != nil {
+				return nil, fmt.Errorf("元数据提取规则 %s 的 json_path 无效: %w", e.Field, err)
+			}
+		}
+		s.extractors = append(s.extractors, c)
+	}
+	return s, nil
+}
+
+// Extractors 返回所有规则
+func (s *Set) Extractors() []Extractor {
+	extractors := make([]Extractor, len(s.extractors))
+	for i, c := range s.extractors {
+		extractors[i] = c.Extractor
+	}
+	return extractors
+}
+
+// Match 是否有规则适用于该文件
+func (s *Set) Match(relPath string) bool {
+	for _, c := range s.extractors {
+		if matchPath(c.Path, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extract 对文件内容应用所有适用的规则，每个字段最多返回一个值
+func (s *Set) Extract(relPath, content string) []Fact {
+	var facts []Fact
+	seen := make(map[string]bool)
+	var doc interface{}
+	parsed := false
+
+	for _, c := range s.extractors {
+		if seen[c.Field] || !matchPath(c.Path, relPath) {
+			continue
+		}
+		var value string
+		if c.re != nil {
+			value = c.extractPattern(content)
+		} else {
+			if !parsed {
+				parsed = true
+				if err := json.Unmarshal([]byte(content), &doc); err != nil {
+					doc = nil
+				}
+			}
+			if doc != nil {
+				value = c.extractJSON(doc)
+			}
+		}
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		if len(value) > maxValueLen {
+			value = strings.ToValidUTF8(value[:maxValueLen], "")
+		}
+		seen[c.Field] = true
+		facts = append(facts, Fact{Field: c.Field, Value: value})
+	}
+	return facts
+}
+
+// extractPattern 取第一处匹配的值
+func (c *compiledExtractor) extractPattern(content string) string {
+	m := c.re.FindStringSubmatchIndex(content)
+	if m == nil || m[2*c.group] < 0 {
+		return ""
+	}
+	return content[m[2*c.group]:m[2*c.group+1]]
+}
+
+// extractJSON 取第一个非空结果，非字符串的值以JSON表示
+func (c *compiledExtractor) extractJSON(doc interface{}) string {
+	iter := c.code.Run(doc)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			return ""
+		}
+		switch v := v.(type) {
+		case error, nil:
+			continue
+		case string:
+			if v != "" {
+				return v
+			}
+		default:
+			data, err := json.Marshal(v)
+			if err == nil {
+				return string(data)
+			}
+		}
+	}
+}
+
+// matchPath 文件匹配模式可以匹配相对路径或文件名
+func matchPath(pattern, relPath string) bool {
+	relPath = strings.ReplaceAll(relPath, "\\", "/")
+	if ok, _ := path.Match(pattern, relPath); ok {
+		return true
+	}
+	ok, _ := path.Match(pattern, path.Base(relPath))
+	return ok
+}
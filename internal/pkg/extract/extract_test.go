@@ -0,0 +1,117 @@
+package extract
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name       string
+		extractors []Extractor
+		wantErr    bool
+	}{
+		{name: "内置规则", extractors: BuiltinExtractors()},
+		{name: "缺少字段名", extractors: []Extractor{{Path: "a", Pattern: "x"}}, wantErr: true},
+		{name: "缺少 path", extractors: []Extractor{{Field: "f", Pattern: "x"}}, wantErr: true},
+		{name: "无效的 path", extractors: []Extractor{{Field: "f", Path: "[", Pattern: "x"}}, wantErr: true},
+		{name: "缺少 pattern 和 json_path", extractors: []Extractor{{Field: "f", Path: "a"}}, wantErr: true},
+		{name: "同时设置 pattern 和 json_path", extractors: []Extractor{{Field: "f", Path: "a", Pattern: "x", JSONPath: ".x"}}, wantErr: true},
+		{name: "无效的正则", extractors: []Extractor{{Field: "f", Path: "a", Pattern: "("}}, wantErr: true},
+		{name: "无效的 json_path", extractors: []Extractor{{Field: "f", Path: "a", JSONPath: ".["}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.extractors)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() 错误 = %v, 期望错误 %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExtract(t *testing.T) {
+	custom := []Extractor{
+		{Field: "firmware_version", Path: "config/device.json", JSONPath: ".firmware.version"},
+		{Field: "slots", Path: "device.json", JSONPath: ".slots"},
+		{Field: "serial", Path: "*.txt", Pattern: `serial=(?P<value>\w+) batch=(\w+)`},
+		{Field: "model", Path: "*.txt", Pattern: `MODEL-\d+`},
+	}
+	set, err := New(append(custom, BuiltinExtractors()...))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    []Fact
+	}{
+		{
+			name:    "os-release",
+			path:    "etc/os-release",
+			content: "NAME=\"Ubuntu\"\nVERSION=\"22.04.3 LTS (Jammy Jellyfish)\"\nVERSION_ID=\"22.04\"\n",
+			want:    []Fact{{"os_name", "Ubuntu"}, {"os_version", "22.04"}},
+		},
+		{
+			name:    "uptime",
+			path:    "sys/uptime.txt",
+			content: " 10:15:01 up 12 days,  3:04,  2 users,  load average: 0.00, 0.01, 0.05",
+			want:    []Fact{{"uptime", "12 days,  3:04"}},
+		},
+		{
+			name:    "根分区使用率",
+			path:    "df-h.out",
+			content: "Filesystem Size Used Avail Use% Mounted on\n/dev/sda1 50G 20G 30G 40% /\n/dev/sdb1 100G 90G 10G 90% /data\n",
+			want:    []Fact{{"disk_usage_root", "40%"}},
+		},
+		{
+			name:    "默认网关",
+			path:    "net/ip-route",
+			content: "default via 192.168.1.1 dev eth0\n192.168.1.0/24 dev eth0\n",
+			want:    []Fact{{"default_gateway", "192.168.1.1"}},
+		},
+		{
+			name:    "自定义规则优先于内置规则",
+			path:    "config/device.json",
+			content: `{"firmware": {"version": "2.4.1"}, "slots": [1, 2]}`,
+			want:    []Fact{{"firmware_version", "2.4.1"}, {"slots", "[1,2]"}},
+		},
+		{
+			name:    "内置规则作为后备",
+			path:    "firmware.txt",
+			content: "Firmware Version: 1.0.7\n",
+			want:    []Fact{{"firmware_version", "1.0.7"}},
+		},
+		{
+			name:    "命名分组优先，没有分组时取整个匹配",
+			path:    "info.txt",
+			content: "serial=ABC123 batch=B7 MODEL-42",
+			want:    []Fact{{"serial", "ABC123"}, {"model", "MODEL-42"}},
+		},
+		{
+			name:    "不是JSON的内容",
+			path:    "device.json",
+			content: "not json",
+		},
+		{
+			name:    "没有适用的规则",
+			path:    "app.log",
+			content: "NAME=\"Ubuntu\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := set.Extract(tt.path, tt.content)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Extract() = %v, 期望 %v", got, tt.want)
+			}
+			if set.Match(tt.path) != (tt.path != "app.log") {
+				t.Errorf("Match(%s) = %v", tt.path, set.Match(tt.path))
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package fileutil
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// HexBytesPerLine 十六进制视图每行的字节数
+const HexBytesPerLine = 16
+
+// binaryMagic 常见二进制文件的文件头
+var binaryMagic = []struct {
+	kind  string
+	magic []byte
+}{
+	{"elf", []byte{0x7f, 'E', 'L', 'F'}},
+	{"sqlite", []byte("SQLite format 3\x00")},
+	{"png", []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}},
+	{"jpeg", []byte{0xff, 0xd8, 0xff}},
+	{"gif", []byte("GIF8")},
+	{"pdf", []byte("%PDF-")},
+	{"zip", []byte("PK\x03\x04")},
+	{"7z", []byte{'7', 'z', 0xbc, 0xaf, 0x27, 0x1c}},
+	{"rar", []byte("Rar!\x1a\x07")},
+	{"macho", []byte{0xcf, 0xfa, 0xed, 0xfe}},
+	{"macho", []byte{0xce, 0xfa, 0xed, 0xfe}},
+	{"pe", []byte("MZ")},
+	{"java-class", []byte{0xca, 0xfe, 0xba, 0xbe}},
+	{"wasm", []byte("\x00asm")},
+	{"pcap", []byte{0xd4, 0xc3, 0xb2, 0xa1}},
+	{"pcap", []byte{0xa1, 0xb2, 0xc3, 0xd4}},
+	{"pcapng", []byte{0x0a, 0x0d, 0x0d, 0x0a}},
+}
+
+// maxControlRatio 控制字符占比超过该值时视为二进制
+const maxControlRatio = 0.1
+
+// DetectBinary 根据文件头和内容判断是否为二进制，返回二进制类型（未知类型为"data"）
+// 依次检查：已知文件头、NUL字节、无效UTF-8和控制字符占比（用于识别protobuf等无文件头的数据）
+func DetectBinary(head []byte) (string, bool) {
+	if len(head) == 0 {
+		return "", false
+	}
+	for _, m := range binaryMagic {
+		if bytes.HasPrefix(head, m.magic) {
+			// "MZ" 过短，只在包含其他二进制特征时才认定为PE
+			if m.kind != "pe" || looksBinary(head) {
+				return m.kind, true
+			}
+		}
+	}
+	if looksBinary(head) {
+		return "data", true
+	}
+	return "", false
+}
+
+// looksBinary 内容嗅探
+func looksBinary(head []byte) bool {
+	if bytes.IndexByte(head, 0) != -1 {
+		return true
+	}
+
+	// 末尾可能截断了多字节字符，只检查完整的部分
+	sample := head
+	for i := 0; i < utf8.UTFMax && len(sample) > 0 && !utf8.Valid(sample); i++ {
+		sample = sample[:len(sample)-1]
+	}
+	if !utf8.Valid(sample) {
+		// 可能是GBK等本地编码的文本，按控制字符占比判断
+		sample = head
+	}
+
+	control := 0
+	for _, b := range sample {
+		if b < 0x20 && b != '\n' && b != '\r' && b != '\t' && b != '\f' && b != '\b' && b != 0x1b {
+			control++
+		}
+	}
+	return float64(control) > float64(len(sample))*maxControlRatio
+}
+
+// SniffBinary 读取文件头部（压缩文件按解压后的内容）判断是否为二进制文件
+func (f *FileUtil) SniffBinary(filePath string) (string, bool) {
+	head, err := f.readHead(filePath)
+	if err != nil {
+		return "", false
+	}
+	return DetectBinary(head)
+}
+
+// HexDumpLine 生成一行 hexdump -C 风格的十六进制+ASCII文本
+// masked 不为nil时，对应位置为true的字节（脱敏内容）在两栏中都显示为 *
+func HexDumpLine(offset int64, data []byte, masked []bool) string {
+	isMasked := func(i int) bool { return masked != nil && masked[i] }
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%08x  ", offset)
+	for i := 0; i < HexBytesPerLine; i++ {
+		if i < len(data) && isMasked(i) {
+			sb.WriteString("** ")
+		} else if i < len(data) {
+			fmt.Fprintf(&sb, "%02x ", data[i])
+		} else {
+			sb.WriteString("   ")
+		}
+		if i == HexBytesPerLine/2-1 {
+			sb.WriteByte(' ')
+		}
+	}
+	sb.WriteString(" |")
+	for i, b := range data {
+		if isMasked(i) {
+			sb.WriteByte('*')
+		} else if b >= 0x20 && b < 0x7f {
+			sb.WriteByte(b)
+		} else {
+			sb.WriteByte('.')
+		}
+	}
+	sb.WriteByte('|')
+	return sb.String()
+}
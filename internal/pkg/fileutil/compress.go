@@ -0,0 +1,178 @@
+package fileutil
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// 支持透明解压的压缩格式
+const (
+	CompressionGzip  = "gzip"
+	CompressionZstd  = "zstd"
+	CompressionBzip2 = "bzip2"
+	CompressionXz    = "xz"
+)
+
+// compressionMagic 各压缩格式的文件头
+var compressionMagic = []struct {
+	kind  string
+	magic []byte
+}{
+	{CompressionGzip, []byte{0x1f, 0x8b}},
+	{CompressionZstd, []byte{0x28, 0xb5, 0x2f, 0xfd}},
+	{CompressionBzip2, []byte("BZh")},
+	{CompressionXz, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}},
+}
+
+// compressionExts 压缩文件扩展名
+var compressionExts = map[string]string{
+	".gz":   CompressionGzip,
+	".zst":  CompressionZstd,
+	".zstd": CompressionZstd,
+	".bz2":  CompressionBzip2,
+	".xz":   CompressionXz,
+}
+
+// DetectCompression 根据文件头判断压缩格式，未压缩时返回空字符串
+func DetectCompression(head []byte) string {
+	for _, m := range compressionMagic {
+		if bytes.HasPrefix(head, m.magic) {
+			return m.kind
+		}
+	}
+	return ""
+}
+
+// TrimCompressionExt 去掉压缩扩展名（如 app.json.gz -> app.json），用于按内容类型识别文件
+func TrimCompressionExt(name string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	if _, ok := compressionExts[ext]; ok {
+		return name[:len(name)-len(ext)]
+	}
+	return name
+}
+
+// Compression 返回文件的压缩格式，未压缩或无法读取时返回空字符串
+func (f *FileUtil) Compression(filePath string) string {
+	file, err := f.storage.Open(filePath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	head := make([]byte, 8)
+	n, _ := io.ReadFull(file, head)
+	return DetectCompression(head[:n])
+}
+
+// decompressReader 解压读取器，关闭时同时关闭解压器和底层文件
+type decompressReader struct {
+	io.Reader
+	closers []func() error
+	limit   *limitReader
+}
+
+// Truncated 解压后的内容是否超过读取上限而被截断，读到 EOF 后才有意义
+func (r *decompressReader) Truncated() bool {
+	return r.limit != nil && r.limit.truncated
+}
+
+// IsTruncated 判断 OpenFile、OpenFileAt 返回的读取器是否因解压后超过 MaxFileSize 而被截断，读到 EOF 后才有意义
+// 截断的内容不能用于判断两个文件是否相同
+func IsTruncated(r interface{}) bool {
+	t, ok := r.(interface{ Truncated() bool })
+	return ok && t.Truncated()
+}
+
+// limitReader 最多读取 remaining 字节，到达上限时多读一个字节判断内容是否还有剩余
+type limitReader struct {
+	r         io.Reader
+	remaining int64
+	truncated bool
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		if !l.truncated {
+			var probe [1]byte
+			n, _ := io.ReadFull(l.r, probe[:])
+			l.truncated = n > 0
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
+
+func (r *decompressReader) Close() error {
+	var firstErr error
+	for _, closeFn := range r.closers {
+		if err := closeFn(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// openDecompressed 从存储中打开文件，压缩文件返回解压后的数据流
+// 解压后最多读取 maxSize 字节（0表示不限制），避免压缩率极高的文件（解压炸弹）耗尽内存和时间，超出时 IsTruncated 返回true
+func openDecompressed(storage *Storage, filePath string, maxSize int64) (io.ReadCloser, error) {
+	file, err := storage.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	buffered := bufio.NewReader(file)
+	head, _ := buffered.Peek(8)
+
+	var reader io.Reader
+	closers := []func() error{file.Close}
+	compression := DetectCompression(head)
+	switch compression {
+	case CompressionGzip:
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		reader = gz
+		closers = append([]func() error{gz.Close}, closers...)
+	case CompressionZstd:
+		dec, err := zstd.NewReader(buffered)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		reader = dec
+		closers = append([]func() error{func() error { dec.Close(); return nil }}, closers...)
+	case CompressionBzip2:
+		reader = bzip2.NewReader(buffered)
+	case CompressionXz:
+		xzReader, err := xz.NewReader(buffered)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		reader = xzReader
+	default:
+		reader = buffered
+	}
+	result := &decompressReader{Reader: reader, closers: closers}
+	if compression != "" && maxSize > 0 {
+		result.limit = &limitReader{r: reader, remaining: maxSize}
+		result.Reader = result.limit
+	}
+	return result, nil
+}
@@ -0,0 +1,13 @@
+package fileutil
+
+import "errors"
+
+// ErrDiskUsageUnsupported 当前平台不支持查询磁盘空间
+var ErrDiskUsageUnsupported = errors.New("当前平台不支持查询磁盘空间")
+
+// DiskUsage 文件所在卷的空间（字节）
+type DiskUsage struct {
+	Total     uint64
+	Free      uint64
+	Available uint64 // 非特权用户可用的空间
+}
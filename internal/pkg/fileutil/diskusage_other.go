@@ -0,0 +1,8 @@
+//go:build !(linux || darwin || freebsd)
+
+package fileutil
+
+// GetDiskUsage 查询路径所在卷的总空间和剩余空间，当前平台不支持
+func GetDiskUsage(path string) (DiskUsage, error) {
+	return DiskUsage{}, ErrDiskUsageUnsupported
+}
@@ -0,0 +1,19 @@
+//go:build linux || darwin || freebsd
+
+package fileutil
+
+import "syscall"
+
+// GetDiskUsage 查询路径所在卷的总空间和剩余空间
+func GetDiskUsage(path string) (DiskUsage, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return DiskUsage{}, err
+	}
+	bsize := uint64(st.Bsize)
+	return DiskUsage{
+		Total:     uint64(st.Blocks) * bsize,
+		Free:      uint64(st.Bfree) * bsize,
+		Available: uint64(st.Bavail) * bsize,
+	}, nil
+}
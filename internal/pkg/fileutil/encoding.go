@@ -0,0 +1,97 @@
+package fileutil
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// 文本编码
+const (
+	EncodingASCII   = "ascii"
+	EncodingUTF8    = "utf-8"
+	EncodingUTF8BOM = "utf-8-bom"
+	EncodingGBK     = "gbk"
+	EncodingUnknown = "unknown"
+)
+
+// utf8BOM UTF-8 字节顺序标记
+var utf8BOM = []byte{0xef, 0xbb, 0xbf}
+
+// ContentInfo 文件内容的类型和编码
+type ContentInfo struct {
+	Type        string // json、jsonl、xml、yaml、html、text 或 binary
+	Encoding    string // 二进制文件为空
+	Compression string
+	BinaryType  string
+}
+
+// DetectEncoding 根据文件头部判断文本编码，末尾被截断的多字节字符不影响结果
+func DetectEncoding(head []byte) string {
+	if bytes.HasPrefix(head, utf8BOM) {
+		return EncodingUTF8BOM
+	}
+
+	ascii := true
+	for _, b := range head {
+		if b >= 0x80 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return EncodingASCII
+	}
+
+	sample := head
+	for i := 0; i < utf8.UTFMax && len(sample) > 0 && !utf8.Valid(sample); i++ {
+		sample = sample[:len(sample)-1]
+	}
+	if utf8.Valid(sample) {
+		return EncodingUTF8
+	}
+	if validGBK(head) {
+		return EncodingGBK
+	}
+	return EncodingUnknown
+}
+
+// validGBK 非ASCII字节是否都能组成GBK双字节字符（首字节 0x81-0xFE，尾字节 0x40-0xFE 且不为 0x7F）
+func validGBK(data []byte) bool {
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		if b < 0x80 {
+			continue
+		}
+		if b == 0x80 || b == 0xff {
+			return false
+		}
+		if i+1 == len(data) {
+			// 截断的最后一个字符
+			return true
+		}
+		trail := data[i+1]
+		if trail < 0x40 || trail == 0x7f || trail == 0xff {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// DetectContent 读取文件头部（压缩文件按解压后的内容）判断内容类型和编码
+func (f *FileUtil) DetectContent(filePath string) (ContentInfo, error) {
+	info := ContentInfo{Compression: f.Compression(filePath)}
+	head, err := f.readHead(filePath)
+	if err != nil {
+		return info, err
+	}
+
+	if binaryType, binary := DetectBinary(head); binary {
+		info.Type = "binary"
+		info.BinaryType = binaryType
+		return info, nil
+	}
+	info.Type = f.DetectFileType(TrimCompressionExt(filePath), string(head))
+	info.Encoding = DetectEncoding(head)
+	return info, nil
+}
@@ -0,0 +1,76 @@
+package fileutil
+
+import (
+	"bytes"
+	"compress/gzip"
+	"logview-goversion/internal/config"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		head []byte
+		want string
+	}{
+		{name: "空内容", head: nil, want: EncodingASCII},
+		{name: "ASCII", head: []byte("2024-01-01 INFO ok\n"), want: EncodingASCII},
+		{name: "UTF-8", head: []byte("启动完成\n"), want: EncodingUTF8},
+		{name: "末尾截断的UTF-8字符", head: []byte("启动完成")[:10], want: EncodingUTF8},
+		{name: "UTF-8 BOM", head: []byte("\xef\xbb\xbfname,value\n"), want: EncodingUTF8BOM},
+		// "启动完成" 的GBK编码
+		{name: "GBK", head: []byte("\xc6\xf4\xb6\xaf\xcd\xea\xb3\xc9\n"), want: EncodingGBK},
+		{name: "末尾截断的GBK字符", head: []byte("\xc6\xf4\xb6\xaf\xcd"), want: EncodingGBK},
+		{name: "无法识别", head: []byte("ok \x80\x80 done"), want: EncodingUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectEncoding(tt.head); got != tt.want {
+				t.Errorf("DetectEncoding() = %s, 期望 %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectContent(t *testing.T) {
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte(`{"level":"info","msg":"启动"}` + "\n" + `{"level":"error","msg":"x"}` + "\n"))
+	w.Close()
+
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	cfg.Storage.MaxFileSize = 1 << 20
+	f := NewFileUtil(cfg)
+
+	tests := []struct {
+		name string
+		file string
+		data []byte
+		want ContentInfo
+	}{
+		{name: "文本日志", file: "app.log", data: []byte("2024-01-01 INFO ok\n"), want: ContentInfo{Type: "text", Encoding: EncodingASCII}},
+		{name: "JSON", file: "config.json", data: []byte(`{"a": "值"}`), want: ContentInfo{Type: "json", Encoding: EncodingUTF8}},
+		{name: "按解压后的内容识别", file: "events.gz", data: gz.Bytes(), want: ContentInfo{Type: "jsonl", Encoding: EncodingUTF8, Compression: "gzip"}},
+		{name: "二进制文件没有编码", file: "core", data: []byte("\x7fELF\x02\x01\x01\x00\x00\x00"), want: ContentInfo{Type: "binary", BinaryType: "elf"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, tt.data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := f.DetectContent(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("DetectContent() = %+v, 期望 %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,16 +4,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
 	"logview-goversion/internal/config"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 )
 
 // FileUtil 文件工具
 type FileUtil struct {
-	cfg *config.Config
+	cfg     *config.Config
+	storage *Storage
 }
 
 // NewFileUtil 创建文件工具
@@ -23,10 +26,36 @@ func NewFileUtil(cfg *config.Config) *FileUtil {
 	os.MkdirAll(cfg.Storage.ExtractDir, 0755)
 
 	return &FileUtil{
-		cfg: cfg,
+		cfg:     cfg,
+		storage: NewStorage(cfg.Storage.Compression),
 	}
 }
 
+// Storage 解压文件的存储层
+func (f *FileUtil) Storage() *Storage {
+	return f.storage
+}
+
+// Stat 获取文件信息，压缩保存的文件返回原大小
+func (f *FileUtil) Stat(path string) (os.FileInfo, error) {
+	return f.storage.Stat(path)
+}
+
+// ReadDir 读取目录，压缩保存的文件按原文件名返回
+func (f *FileUtil) ReadDir(path string) ([]os.DirEntry, error) {
+	return f.storage.ReadDir(path)
+}
+
+// WalkDir 遍历目录，压缩保存的文件按原文件名返回
+func (f *FileUtil) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return f.storage.WalkDir(root, fn)
+}
+
+// OpenRaw 打开文件读取原内容（压缩日志文件不解压），支持随机访问
+func (f *FileUtil) OpenRaw(filePath string) (File, error) {
+	return f.storage.Open(filePath)
+}
+
 // BuildTree 构建文件树
 func (f *FileUtil) BuildTree(rootPath, relativePath string) (*FileNode, error) {
 	node := &FileNode{
@@ -34,16 +63,17 @@ func (f *FileUtil) BuildTree(rootPath, relativePath string) (*FileNode, error) {
 		Path: relativePath,
 	}
 
-	fileInfo, err := os.Stat(rootPath)
+	fileInfo, err := f.storage.Stat(rootPath)
 	if err != nil {
 		return nil, err
 	}
+	node.ModTime = fileInfo.ModTime()
 
 	if fileInfo.IsDir() {
 		node.Type = "directory"
 		node.Children = []*FileNode{}
 
-		entries, err := os.ReadDir(rootPath)
+		entries, err := f.storage.ReadDir(rootPath)
 		if err != nil {
 			return nil, err
 		}
@@ -64,6 +94,7 @@ func (f *FileUtil) BuildTree(rootPath, relativePath string) (*FileNode, error) {
 	} else {
 		node.Type = "file"
 		node.Size = fileInfo.Size()
+		node.Binary = !f.IsTextFile(rootPath)
 	}
 
 	return node, nil
@@ -72,7 +103,7 @@ func (f *FileUtil) BuildTree(rootPath, relativePath string) (*FileNode, error) {
 // ReadFileContent 读取文件内容
 func (f *FileUtil) ReadFileContent(filePath string) (string, error) {
 	// 检查文件大小
-	fileInfo, err := os.Stat(filePath)
+	fileInfo, err := f.storage.Stat(filePath)
 	if err != nil {
 		return "", err
 	}
@@ -106,14 +137,17 @@ func (f *FileUtil) ReadFileContent(filePath string) (string, error) {
 
 // readFilePreview 读取文件的预览部分
 func (f *FileUtil) readFilePreview(filePath string, maxBytes int64) (string, error) {
-	file, err := os.Open(filePath)
+	file, err := f.storage.Open(filePath)
 	if err != nil {
 		return "", err
 	}
 	defer file.Close()
 
 	buffer := make([]byte, maxBytes)
-	n, err := file.Read(buffer)
+	n, err := io.ReadFull(file, buffer)
+	if err == io.ErrUnexpectedEOF {
+		err = nil
+	}
 	if err != nil && err != io.EOF {
 		return "", err
 	}
@@ -124,7 +158,7 @@ func (f *FileUtil) readFilePreview(filePath string, maxBytes int64) (string, err
 
 // readFileWithEncoding 使用指定编码读取文件
 func (f *FileUtil) readFileWithEncoding(filePath, encoding string) (string, error) {
-	data, err := os.ReadFile(filePath)
+	data, err := f.storage.ReadFile(filePath)
 	if err != nil {
 		return "", err
 	}
@@ -147,6 +181,8 @@ func (f *FileUtil) DetectFileType(filename, content string) string {
 	switch ext {
 	case ".json":
 		return "json"
+	case ".jsonl", ".ndjson":
+		return "jsonl"
 	case ".log", ".txt":
 		return "text"
 	case ".xml":
@@ -160,6 +196,17 @@ func (f *FileUtil) DetectFileType(filename, content string) string {
 		if f.isJSON(content) {
 			return "json"
 		}
+		if IsJSONLines(content) {
+			return "jsonl"
+		}
+		// 未知扩展名时按内容嗅探二进制
+		head := content
+		if len(head) > sniffSize {
+			head = head[:sniffSize]
+		}
+		if _, binary := DetectBinary([]byte(head)); binary {
+			return "binary"
+		}
 		return "text"
 	}
 }
@@ -170,26 +217,25 @@ func (f *FileUtil) isJSON(content string) bool {
 	return json.Unmarshal([]byte(content), &v) == nil
 }
 
-// FormatContent 格式化内容
-func (f *FileUtil) FormatContent(content, fileType string) string {
+// FormatContent 格式化内容（JSON、JSON Lines、XML、YAML），同时返回语法错误；无法格式化时返回原内容
+func (f *FileUtil) FormatContent(content, fileType string) (string, []Diagnostic) {
 	switch fileType {
 	case "json":
-		var v interface{}
-		if err := json.Unmarshal([]byte(content), &v); err == nil {
-			formatted, err := json.MarshalIndent(v, "", "  ")
-			if err == nil {
-				return string(formatted)
-			}
-		}
-		return content
+		return formatJSON(content)
+	case "jsonl":
+		return formatJSONLines(content)
+	case "xml":
+		return formatXML(content)
+	case "yaml":
+		return formatYAML(content)
 	default:
-		return content
+		return content, nil
 	}
 }
 
 // IsFile 检查是否为文件
 func (f *FileUtil) IsFile(path string) bool {
-	fileInfo, err := os.Stat(path)
+	fileInfo, err := f.storage.Stat(path)
 	if err != nil {
 		return false
 	}
@@ -214,5 +260,7 @@ type FileNode struct {
 	Path     string      `json:"path"`
 	Type     string      `json:"type"` // "file" or "directory"
 	Size     int64       `json:"size,omitempty"`
+	Binary   bool        `json:"binary,omitempty"`
+	ModTime  time.Time   `json:"mod_time"`
 	Children []*FileNode `json:"children,omitempty"`
 }
\ No newline at end of file
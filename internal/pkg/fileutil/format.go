@@ -0,0 +1,273 @@
+package fileutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Diagnostic 格式化时发现的语法错误，行列号从1开始（未知时为0）
+type Diagnostic struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+// formatIndent 格式化缩进
+const formatIndent = "  "
+
+// formatJSON 缩进整个JSON文档，保留原有键顺序
+func formatJSON(content string) (string, []Diagnostic) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(content), "", formatIndent); err != nil {
+		return content, []Diagnostic{jsonDiagnostic(content, err)}
+	}
+	return buf.String(), nil
+}
+
+// formatJSONLines 逐行缩进JSON Lines，无法解析的行原样保留并报告错误
+func formatJSONLines(content string) (string, []Diagnostic) {
+	var diagnostics []Diagnostic
+	var out strings.Builder
+	for i, line := range strings.Split(content, "\n") {
+		if i > 0 {
+			out.WriteByte('\n')
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			out.WriteString(line)
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, []byte(trimmed), "", formatIndent); err != nil {
+			diag := jsonDiagnostic(trimmed, err)
+			diag.Line = i + 1
+			if diag.Column > 0 {
+				diag.Column += len(line) - len(strings.TrimLeft(line, " \t"))
+			}
+			diagnostics = append(diagnostics, diag)
+			out.WriteString(line)
+			continue
+		}
+		out.Write(buf.Bytes())
+	}
+	return out.String(), diagnostics
+}
+
+// jsonDiagnostic 将JSON解析错误的字节偏移转换为行列号
+func jsonDiagnostic(content string, err error) Diagnostic {
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return Diagnostic{Message: err.Error()}
+	}
+	// Offset 包含出错的字节，行列号指向该字节本身
+	offset := int(syntaxErr.Offset) - 1
+	if offset < 0 {
+		offset = 0
+	}
+	line, col := lineColumn(content, offset)
+	return Diagnostic{Line: line, Column: col, Message: syntaxErr.Error()}
+}
+
+// lineColumn 计算字节偏移所在的行列号
+func lineColumn(content string, offset int) (int, int) {
+	if offset > len(content) {
+		offset = len(content)
+	}
+	before := content[:offset]
+	line := strings.Count(before, "\n") + 1
+	col := offset - strings.LastIndex(before, "\n")
+	return line, col
+}
+
+// IsJSONLines 判断内容是否为JSON Lines（至少两行且每个非空行都是JSON对象）
+func IsJSONLines(content string) bool {
+	records := 0
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "{") || !json.Valid([]byte(line)) {
+			return false
+		}
+		records++
+	}
+	return records >= 2
+}
+
+// formatXML 校验XML并重新缩进，保留命名空间前缀、注释和处理指令
+func formatXML(content string) (string, []Diagnostic) {
+	// 先用严格模式校验（标签匹配、实体等），RawToken 不检查这些
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return content, []Diagnostic{xmlDiagnostic(err)}
+		}
+	}
+
+	decoder = xml.NewDecoder(strings.NewReader(content))
+	var tokens []xml.Token
+	for {
+		tok, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return content, []Diagnostic{xmlDiagnostic(err)}
+		}
+		tokens = append(tokens, xml.CopyToken(tok))
+	}
+
+	var out strings.Builder
+	depth := 0
+	newline := func() {
+		if out.Len() > 0 {
+			out.WriteByte('\n')
+		}
+		out.WriteString(strings.Repeat(formatIndent, depth))
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		switch tok := tokens[i].(type) {
+		case xml.StartElement:
+			newline()
+			writeStartTag(&out, tok)
+			// 空元素写成自闭合标签，只有文本的元素保持在同一行
+			if i+1 < len(tokens) {
+				if _, ok := tokens[i+1].(xml.EndElement); ok {
+					out.WriteString("/>")
+					i++
+					continue
+				}
+			}
+			out.WriteByte('>')
+			if i+2 < len(tokens) {
+				text, isText := tokens[i+1].(xml.CharData)
+				end, isEnd := tokens[i+2].(xml.EndElement)
+				if isText && isEnd {
+					xml.EscapeText(&out, bytes.TrimSpace(text))
+					out.WriteString("</" + xmlName(end.Name) + ">")
+					i += 2
+					continue
+				}
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+			newline()
+			out.WriteString("</" + xmlName(tok.Name) + ">")
+		case xml.CharData:
+			text := bytes.TrimSpace(tok)
+			if len(text) == 0 {
+				continue
+			}
+			newline()
+			xml.EscapeText(&out, text)
+		case xml.Comment:
+			newline()
+			out.WriteString("<!--" + string(tok) + "-->")
+		case xml.ProcInst:
+			newline()
+			out.WriteString("<?" + tok.Target)
+			if len(tok.Inst) > 0 {
+				out.WriteString(" " + string(tok.Inst))
+			}
+			out.WriteString("?>")
+		case xml.Directive:
+			newline()
+			out.WriteString("<!" + string(tok) + ">")
+		}
+	}
+	return out.String(), nil
+}
+
+// writeStartTag 输出开始标签（不含结尾的 > ）
+func writeStartTag(out *strings.Builder, el xml.StartElement) {
+	out.WriteString("<" + xmlName(el.Name))
+	for _, attr := range el.Attr {
+		out.WriteString(" " + xmlName(attr.Name) + `="`)
+		xml.EscapeText(out, []byte(attr.Value))
+		out.WriteByte('"')
+	}
+}
+
+// xmlName RawToken 中 Space 为原始前缀
+func xmlName(name xml.Name) string {
+	if name.Space != "" {
+		return name.Space + ":" + name.Local
+	}
+	return name.Local
+}
+
+// xmlDiagnostic 转换XML解析错误
+func xmlDiagnostic(err error) Diagnostic {
+	var syntaxErr *xml.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return Diagnostic{Line: syntaxErr.Line, Message: syntaxErr.Msg}
+	}
+	return Diagnostic{Message: err.Error()}
+}
+
+// yamlLinePattern yaml.v3 错误信息中的行号
+var yamlLinePattern = regexp.MustCompile(`line (\d+)(?:, column (\d+))?: `)
+
+// formatYAML 校验YAML（支持多文档）并以统一缩进重新输出，注释会被保留
+func formatYAML(content string) (string, []Diagnostic) {
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	var docs []*yaml.Node
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return content, []Diagnostic{yamlDiagnostic(err)}
+		}
+		docs = append(docs, &doc)
+	}
+	if len(docs) == 0 {
+		return content, nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(len(formatIndent))
+	for _, doc := range docs {
+		if err := encoder.Encode(doc); err != nil {
+			return content, nil
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return content, nil
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// yamlDiagnostic 从错误信息中提取行列号
+func yamlDiagnostic(err error) Diagnostic {
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	m := yamlLinePattern.FindStringSubmatchIndex(msg)
+	if m == nil {
+		return Diagnostic{Message: msg}
+	}
+	diag := Diagnostic{Message: msg[:m[0]] + msg[m[1]:]}
+	diag.Line, _ = strconv.Atoi(msg[m[2]:m[3]])
+	if m[4] >= 0 {
+		diag.Column, _ = strconv.Atoi(msg[m[4]:m[5]])
+	}
+	return diag
+}
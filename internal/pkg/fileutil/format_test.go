@@ -0,0 +1,223 @@
+package fileutil
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFormatJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		diags   []Diagnostic
+	}{
+		{name: "缩进并保留键顺序", content: `{"b":1,"a":[true,null]}`, want: "{\n  \"b\": 1,\n  \"a\": [\n    true,\n    null\n  ]\n}"},
+		{
+			name:    "错误位置指向出错的字符",
+			content: "{\n\"a\":x}",
+			want:    "{\n\"a\":x}",
+			diags:   []Diagnostic{{Line: 2, Column: 5, Message: "invalid character 'x' looking for beginning of value"}},
+		},
+		{
+			name:    "第一个字符出错",
+			content: "x",
+			want:    "x",
+			diags:   []Diagnostic{{Line: 1, Column: 1, Message: "invalid character 'x' looking for beginning of value"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, diags := formatJSON(tt.content)
+			if got != tt.want {
+				t.Errorf("formatJSON() = %q, 期望 %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(diags, tt.diags) {
+				t.Errorf("诊断 = %+v, 期望 %+v", diags, tt.diags)
+			}
+		})
+	}
+}
+
+func TestFormatJSONLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		diags   []Diagnostic
+	}{
+		{
+			name:    "逐行缩进",
+			content: `{"a":1}` + "\n" + `{"b":[2]}`,
+			want:    "{\n  \"a\": 1\n}\n{\n  \"b\": [\n    2\n  ]\n}",
+		},
+		{
+			name:    "保留空行",
+			content: "{\"a\":1}\n\n{\"b\":2}\n",
+			want:    "{\n  \"a\": 1\n}\n\n{\n  \"b\": 2\n}\n",
+		},
+		{
+			name:    "无法解析的行原样保留，列号计入缩进",
+			content: "{\"a\":1}\n  {\"b\":}",
+			want:    "{\n  \"a\": 1\n}\n  {\"b\":}",
+			diags:   []Diagnostic{{Line: 2, Column: 8, Message: "invalid character '}' looking for beginning of value"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, diags := formatJSONLines(tt.content)
+			if got != tt.want {
+				t.Errorf("formatJSONLines() = %q, 期望 %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(diags, tt.diags) {
+				t.Errorf("诊断 = %+v, 期望 %+v", diags, tt.diags)
+			}
+		})
+	}
+}
+
+func TestIsJSONLines(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"{\"a\":1}\n{\"b\":2}\n", true},
+		{"{\"a\":1}\n\n  {\"b\":2}", true},
+		{"{\"a\":1}", false},
+		{"{\"a\":1}\n[1,2]", false},
+		{"{\"a\":1}\nnot json", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsJSONLines(tt.content); got != tt.want {
+			t.Errorf("IsJSONLines(%q) = %v, 期望 %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestFormatXML(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		line    int // 期望的诊断行号，0表示没有诊断
+	}{
+		{
+			name:    "缩进并保留声明、注释和命名空间前缀",
+			content: `<?xml version="1.0"?><!-- c --><ns:root xmlns:ns="urn:x"><ns:item id="1">a &amp; b</ns:item><empty></empty><p>t<b>x</b></p></ns:root>`,
+			want: strings.Join([]string{
+				`<?xml version="1.0"?>`,
+				`<!-- c -->`,
+				`<ns:root xmlns:ns="urn:x">`,
+				`  <ns:item id="1">a &amp; b</ns:item>`,
+				`  <empty/>`,
+				`  <p>`,
+				`    t`,
+				`    <b>x</b>`,
+				`  </p>`,
+				`</ns:root>`,
+			}, "\n"),
+		},
+		{
+			name:    "标签不匹配",
+			content: "<a>\n<b>\n</a>",
+			line:    3,
+		},
+		{
+			name:    "未闭合",
+			content: "<a>\n<b></b>",
+			line:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, diags := formatXML(tt.content)
+			if tt.line == 0 {
+				if len(diags) != 0 {
+					t.Fatalf("诊断 = %+v, 期望没有诊断", diags)
+				}
+				if got != tt.want {
+					t.Errorf("formatXML() =\n%s\n期望\n%s", got, tt.want)
+				}
+				return
+			}
+			if len(diags) != 1 || diags[0].Line != tt.line {
+				t.Errorf("诊断 = %+v, 期望第 %d 行", diags, tt.line)
+			}
+			if got != tt.content {
+				t.Errorf("出错时应返回原内容, 实际 %q", got)
+			}
+		})
+	}
+}
+
+func TestFormatYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		diag    Diagnostic // 期望的诊断行列号，Line为0表示没有诊断
+	}{
+		{
+			name:    "统一缩进并保留注释",
+			content: "a:\n    b: 1 # one\n    c:\n        - x\n",
+			want:    "a:\n  b: 1 # one\n  c:\n    - x",
+		},
+		{
+			name:    "多文档",
+			content: "a: 1\n---\nb: 2\n",
+			want:    "a: 1\n---\nb: 2",
+		},
+		{name: "空内容", content: "", want: ""},
+		{
+			name:    "缩进错误",
+			content: "a: 1\n b: 2\n",
+			diag:    Diagnostic{Line: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, diags := formatYAML(tt.content)
+			if tt.diag.Line == 0 {
+				if len(diags) != 0 {
+					t.Fatalf("诊断 = %+v, 期望没有诊断", diags)
+				}
+				if got != tt.want {
+					t.Errorf("formatYAML() = %q, 期望 %q", got, tt.want)
+				}
+				return
+			}
+			if len(diags) != 1 || diags[0].Line != tt.diag.Line {
+				t.Errorf("诊断 = %+v, 期望第 %d 行", diags, tt.diag.Line)
+			}
+			if len(diags) == 1 && strings.Contains(diags[0].Message, "line ") {
+				t.Errorf("诊断信息中仍包含行号: %q", diags[0].Message)
+			}
+			if got != tt.content {
+				t.Errorf("出错时应返回原内容, 实际 %q", got)
+			}
+		})
+	}
+}
+
+func TestYAMLDiagnostic(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want Diagnostic
+	}{
+		{"yaml: line 3: mapping values are not allowed in this context", Diagnostic{Line: 3, Message: "mapping values are not allowed in this context"}},
+		{"yaml: line 2, column 5: did not find expected key", Diagnostic{Line: 2, Column: 5, Message: "did not find expected key"}},
+		{"yaml: unknown anchor 'x' referenced", Diagnostic{Message: "unknown anchor 'x' referenced"}},
+	}
+	for _, tt := range tests {
+		if got := yamlDiagnostic(errors.New(tt.msg)); got != tt.want {
+			t.Errorf("yamlDiagnostic(%q) = %+v, 期望 %+v", tt.msg, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package fileutil
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxLineLength 单行最大长度，超出部分会被截断
+const MaxLineLength = 64 * 1024
+
+// sniffSize 内容嗅探读取的字节数
+const sniffSize = 8000
+
+// LineScanner 逐行读取器，超长行会被截断到 MaxLineLength
+type LineScanner struct {
+	reader *bufio.Reader
+	line   string
+	lineNo int
+	offset int64
+	err    error
+	buf    []byte
+}
+
+// NewLineScanner 创建逐行读取器
+func NewLineScanner(r io.Reader) *LineScanner {
+	return NewLineScannerAt(r, 0, 0)
+}
+
+// NewLineScannerAt 从文件中间继续逐行读取，r 已位于 offset 字节处，即第 lineNo 行末尾
+func NewLineScannerAt(r io.Reader, offset int64, lineNo int) *LineScanner {
+	return &LineScanner{reader: bufio.NewReaderSize(r, 64*1024), offset: offset, lineNo: lineNo}
+}
+
+// Next 读取下一行，没有更多行或出错时返回false
+func (s *LineScanner) Next() bool {
+	if s.err != nil {
+		return false
+	}
+	s.buf = s.buf[:0]
+
+	read := 0
+	for {
+		chunk, err := s.reader.ReadSlice('\n')
+		read += len(chunk)
+		s.offset += int64(len(chunk))
+		chunk = bytes.TrimSuffix(chunk, []byte{'\n'})
+		if len(chunk) > 0 && len(s.buf) < MaxLineLength {
+			remain := MaxLineLength - len(s.buf)
+			if len(chunk) > remain {
+				chunk = chunk[:remain]
+			}
+			s.buf = append(s.buf, chunk...)
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			s.err = err
+			// 文件末尾没有换行符的最后一行
+			if err == io.EOF && read > 0 {
+				break
+			}
+			return false
+		}
+		break
+	}
+
+	s.lineNo++
+	s.line = strings.TrimSuffix(string(s.buf), "\r")
+	return true
+}
+
+// Line 当前行内容
+func (s *LineScanner) Line() string {
+	return s.line
+}
+
+// LineNo 当前行号（从1开始）
+func (s *LineScanner) LineNo() int {
+	return s.lineNo
+}
+
+// Offset 当前行（含换行符）结束处的字节偏移
+func (s *LineScanner) Offset() int64 {
+	return s.offset
+}
+
+// Err 返回读取过程中的错误（正常结束时为nil）
+func (s *LineScanner) Err() error {
+	if s.err == io.EOF {
+		return nil
+	}
+	return s.err
+}
+
+// ForEachLine 逐行读取内容，回调中的行号从1开始，返回错误时停止读取
+func ForEachLine(r io.Reader, fn func(lineNo int, line string) error) error {
+	scanner := NewLineScanner(r)
+	for scanner.Next() {
+		if err := fn(scanner.LineNo(), scanner.Line()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// OpenFile 打开文件用于读取，所有按行读取的入口都经过这里
+// gzip、zstd、bzip2、xz 压缩的文件会透明解压，解压后的内容最多读取 MaxFileSize 字节
+func (f *FileUtil) OpenFile(filePath string) (io.ReadCloser, error) {
+	return openDecompressed(f.storage, filePath, f.cfg.Storage.MaxFileSize)
+}
+
+// ErrOffsetOutOfRange 读取位置超出文件内容（文件在生成位置后被替换或截短）
+var ErrOffsetOutOfRange = errors.New("读取位置超出文件内容")
+
+// OpenFileAt 打开文件并从原内容的 offset 字节处开始读取，offset 超出内容长度时返回 ErrOffsetOutOfRange
+// 未压缩和压缩保存的文件直接跳转，日志包中原本压缩的文件需要解压并丢弃之前的内容
+func (f *FileUtil) OpenFileAt(filePath string, offset int64) (io.ReadCloser, error) {
+	if offset <= 0 {
+		return f.OpenFile(filePath)
+	}
+	if f.Compression(filePath) == "" {
+		file, err := f.storage.Open(filePath)
+		if err != nil {
+			return nil, err
+		}
+		size, err := file.Seek(0, io.SeekEnd)
+		if err == nil && offset > size {
+			err = ErrOffsetOutOfRange
+		}
+		if err == nil {
+			_, err = file.Seek(offset, io.SeekStart)
+		}
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		return file, nil
+	}
+
+	reader, err := f.OpenFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.CopyN(io.Discard, reader, offset); err != nil {
+		reader.Close()
+		if err == io.EOF {
+			return nil, ErrOffsetOutOfRange
+		}
+		return nil, err
+	}
+	return reader, nil
+}
+
+// ForEachFileLine 逐行读取文件
+func (f *FileUtil) ForEachFileLine(filePath string, fn func(lineNo int, line string) error) error {
+	file, err := f.OpenFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return ForEachLine(file, fn)
+}
+
+// HashFile 计算文件内容的SHA-256
+// 压缩文件解压后超过 MaxFileSize 时截断的内容不能代表整个文件，改为计算文件原始字节（压缩数据）的SHA-256，
+// 两个结果相同时文件内容一定相同
+func (f *FileUtil) HashFile(filePath string) (string, error) {
+	file, err := f.OpenFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	if !IsTruncated(file) {
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	raw, err := f.storage.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer raw.Close()
+
+	h.Reset()
+	if _, err := io.Copy(h, raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// IsTextFile 通过文件头部内容判断是否为文本文件，压缩文件按解压后的内容判断
+func (f *FileUtil) IsTextFile(filePath string) bool {
+	head, err := f.readHead(filePath)
+	if err != nil {
+		return false
+	}
+	_, binary := DetectBinary(head)
+	return !binary
+}
+
+// readHead 读取文件头部用于内容嗅探
+func (f *FileUtil) readHead(filePath string) ([]byte, error) {
+	file, err := f.OpenFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	head := make([]byte, sniffSize)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	return head[:n], nil
+}
+
+// WalkFiles 遍历目录下的所有文件，回调参数为相对路径和绝对路径，压缩保存的文件按原文件名和原大小返回
+func (f *FileUtil) WalkFiles(rootPath string, fn func(relPath, fullPath string, info os.FileInfo) error) error {
+	return f.storage.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// 跳过无法访问的文件
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(rootPath, path)
+		if err != nil {
+			return nil
+		}
+		return fn(filepath.ToSlash(relPath), path, info)
+	})
+}
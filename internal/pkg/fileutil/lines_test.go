@@ -0,0 +1,184 @@
+package fileutil
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"logview-goversion/internal/config"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLineScannerOffset(t *testing.T) {
+	long := strings.Repeat("x", MaxLineLength+10)
+	tests := []struct {
+		name    string
+		content string
+		lines   []string
+		offsets []int64
+	}{
+		{name: "LF", content: "a\nbc\n", lines: []string{"a", "bc"}, offsets: []int64{2, 5}},
+		{name: "CRLF", content: "a\r\nbc\r\n", lines: []string{"a", "bc"}, offsets: []int64{3, 7}},
+		{name: "末尾没有换行符", content: "a\nbc", lines: []string{"a", "bc"}, offsets: []int64{2, 4}},
+		{name: "空行", content: "\n\na\n", lines: []string{"", "", "a"}, offsets: []int64{1, 2, 4}},
+		{
+			name:    "超长行截断但偏移计入全部字节",
+			content: long + "\nz\n",
+			lines:   []string{long[:MaxLineLength], "z"},
+			offsets: []int64{int64(len(long)) + 1, int64(len(long)) + 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lines []string
+			var offsets []int64
+			scanner := NewLineScanner(strings.NewReader(tt.content))
+			for scanner.Next() {
+				lines = append(lines, scanner.Line())
+				offsets = append(offsets, scanner.Offset())
+			}
+			if err := scanner.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(lines, tt.lines) {
+				t.Errorf("行 = %q, 期望 %q", lines, tt.lines)
+			}
+			if !reflect.DeepEqual(offsets, tt.offsets) {
+				t.Errorf("偏移 = %v, 期望 %v", offsets, tt.offsets)
+			}
+
+			// 从每一行末尾继续读取，行号和剩余内容与从头读取一致
+			for i, offset := range tt.offsets {
+				resumed := NewLineScannerAt(strings.NewReader(tt.content[offset:]), offset, i+1)
+				var rest []string
+				for resumed.Next() {
+					if resumed.LineNo() != i+2+len(rest) {
+						t.Errorf("从第 %d 行继续读取时行号 = %d", i+1, resumed.LineNo())
+					}
+					rest = append(rest, resumed.Line())
+				}
+				if want := tt.lines[i+1:]; len(rest) != len(want) || (len(want) > 0 && !reflect.DeepEqual(rest, want)) {
+					t.Errorf("从第 %d 行继续读取 = %q, 期望 %q", i+1, rest, want)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenFileAt(t *testing.T) {
+	content := "a\nbc\n"
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte(content))
+	w.Close()
+
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	cfg.Storage.MaxFileSize = 1 << 20
+	f := NewFileUtil(cfg)
+	files := map[string][]byte{"plain.log": []byte(content), "app.log.gz": gz.Bytes()}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		offset  int64
+		want    string
+		wantErr error
+	}{
+		{offset: 0, want: content},
+		{offset: 2, want: "bc\n"},
+		{offset: int64(len(content)), want: ""},
+		{offset: int64(len(content)) + 1, wantErr: ErrOffsetOutOfRange},
+	}
+
+	for name := range files {
+		for _, tt := range tests {
+			reader, err := f.OpenFileAt(filepath.Join(dir, name), tt.offset)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("%s: OpenFileAt(%d) 错误 = %v, 期望 %v", name, tt.offset, err, tt.wantErr)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("%s: OpenFileAt(%d) 错误 = %v", name, tt.offset, err)
+			}
+			data, _ := io.ReadAll(reader)
+			reader.Close()
+			if string(data) != tt.want {
+				t.Errorf("%s: OpenFileAt(%d) = %q, 期望 %q", name, tt.offset, data, tt.want)
+			}
+		}
+	}
+}
+
+func TestHashFileTruncated(t *testing.T) {
+	gzipData := func(content string) []byte {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		w.Write([]byte(content))
+		w.Close()
+		return buf.Bytes()
+	}
+
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	cfg.Storage.MaxFileSize = 16
+	f := NewFileUtil(cfg)
+	prefix := strings.Repeat("a", 16)
+	files := map[string][]byte{
+		"small.gz":  gzipData("short"),
+		"large1.gz": gzipData(prefix + "x"),
+		"large2.gz": gzipData(prefix + "y"),
+		"large3.gz": gzipData(prefix + "x"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hash := func(name string) string {
+		h, err := f.HashFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	// 未超过上限时是解压后内容的哈希
+	sum := sha256.Sum256([]byte("short"))
+	if got := hash("small.gz"); got != hex.EncodeToString(sum[:]) {
+		t.Errorf("HashFile(small.gz) = %s, 期望解压后内容的哈希", got)
+	}
+	if hash("large1.gz") == hash("large2.gz") {
+		t.Error("前 MaxFileSize 字节相同、之后不同的文件哈希相同")
+	}
+	if hash("large1.gz") != hash("large3.gz") {
+		t.Error("内容相同的文件哈希不同")
+	}
+
+	for name, want := range map[string]bool{"small.gz": false, "large1.gz": true} {
+		reader, err := f.OpenFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(reader)
+		reader.Close()
+		if IsTruncated(reader) != want {
+			t.Errorf("%s: IsTruncated() = %v, 期望 %v", name, !want, want)
+		}
+		if want && len(data) != int(cfg.Storage.MaxFileSize) {
+			t.Errorf("%s: 读取 %d 字节, 期望 %d", name, len(data), cfg.Storage.MaxFileSize)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package fileutil
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstd 可寻址格式（zstd contrib/seekable_format）：内容按块压缩为相互独立的帧，
+// 文件末尾的跳转帧（skippable frame）记录每帧压缩前后的大小，读取时只需解压所在的帧
+
+const (
+	// seekableFrameSize 每帧压缩前的大小，越小随机访问越快，越大压缩率越高
+	seekableFrameSize = 1 << 20
+
+	skippableMagic     = 0x184D2A5E
+	seekableMagic      = 0x8F92EAB1
+	seekTableFooter    = 9 // 帧数(4) + 描述符(1) + 魔数(4)
+	seekChecksumFlag   = 1 << 7
+	seekEntrySize      = 8 // 压缩后大小(4) + 压缩前大小(4)
+	seekEntryChecksum  = 12
+	skippableHeaderLen = 8
+)
+
+// errSeekTable 文件末尾没有有效的跳转表
+var errSeekTable = errors.New("无效的 zstd 可寻址格式跳转表")
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+// zstdCodec 共享的编码器和解码器，EncodeAll/DecodeAll 可并发调用
+func zstdCodec() (*zstd.Encoder, *zstd.Decoder, error) {
+	zstdOnce.Do(func() {
+		zstdEncoder, zstdErr = zstd.NewWriter(nil)
+		if zstdErr != nil {
+			zstdErr = fmt.Errorf("创建 zstd 编码器失败: %w", zstdErr)
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil)
+		if zstdErr != nil {
+			zstdErr = fmt.Errorf("创建 zstd 解码器失败: %w", zstdErr)
+		}
+	})
+	return zstdEncoder, zstdDecoder, zstdErr
+}
+
+// seekFrame 跳转表中的一帧，偏移量在读取时累加得到
+type seekFrame struct {
+	compressedOffset   int64
+	compressedSize     int64
+	decompressedOffset int64
+	decompressedSize   int64
+}
+
+// seekTable 跳转表
+type seekTable struct {
+	frames []seekFrame
+}
+
+// size 解压后的总大小
+func (t *seekTable) size() int64 {
+	if len(t.frames) == 0 {
+		return 0
+	}
+	last := t.frames[len(t.frames)-1]
+	return last.decompressedOffset + last.decompressedSize
+}
+
+// find 解压后偏移量所在的帧，超出末尾时返回帧数
+func (t *seekTable) find(offset int64) int {
+	return sort.Search(len(t.frames), func(i int) bool {
+		f := t.frames[i]
+		return f.decompressedOffset+f.decompressedSize > offset
+	})
+}
+
+// readSeekTable 从文件末尾读取跳转表，size 为文件大小
+func readSeekTable(r io.ReaderAt, size int64) (*seekTable, error) {
+	if size < skippableHeaderLen+seekTableFooter {
+		return nil, errSeekTable
+	}
+	footer := make([]byte, seekTableFooter)
+	if _, err := r.ReadAt(footer, size-seekTableFooter); err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(footer[5:]) != seekableMagic {
+		return nil, errSeekTable
+	}
+	count := int64(binary.LittleEndian.Uint32(footer[:4]))
+	entrySize := int64(seekEntrySize)
+	if footer[4]&seekChecksumFlag != 0 {
+		entrySize = seekEntryChecksum
+	}
+
+	tableSize := count*entrySize + seekTableFooter
+	start := size - tableSize - skippableHeaderLen
+	if start < 0 {
+		return nil, errSeekTable
+	}
+	data := make([]byte, tableSize+skippableHeaderLen)
+	if _, err := r.ReadAt(data, start); err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(data[:4]) != skippableMagic ||
+		int64(binary.LittleEndian.Uint32(data[4:8])) != tableSize {
+		return nil, errSeekTable
+	}
+
+	table := &seekTable{frames: make([]seekFrame, count)}
+	var compressedOffset, decompressedOffset int64
+	for i := range table.frames {
+		entry := data[skippableHeaderLen+int64(i)*entrySize:]
+		f := seekFrame{
+			compressedOffset:   compressedOffset,
+			compressedSize:     int64(binary.LittleEndian.Uint32(entry[:4])),
+			decompressedOffset: decompressedOffset,
+			decompressedSize:   int64(binary.LittleEndian.Uint32(entry[4:8])),
+		}
+		table.frames[i] = f
+		compressedOffset += f.compressedSize
+		decompressedOffset += f.decompressedSize
+	}
+	if compressedOffset != start {
+		return nil, errSeekTable
+	}
+	return table, nil
+}
+
+// seekableReader 读取可寻址格式的文件，缓存最近解压的一帧
+type seekableReader struct {
+	file    *os.File
+	decoder *zstd.Decoder
+	table   *seekTable
+	pos     int64
+	frame   int // buf 对应的帧，-1 表示没有
+	buf     []byte
+	packed  []byte
+}
+
+// newSeekableReader 读取跳转表并创建读取器，关闭时关闭 file
+func newSeekableReader(file *os.File) (*seekableReader, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	table, err := readSeekTable(file, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file.Name(), err)
+	}
+	_, decoder, err := zstdCodec()
+	if err != nil {
+		return nil, err
+	}
+	return &seekableReader{file: file, decoder: decoder, table: table, frame: -1}, nil
+}
+
+func (r *seekableReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	i := r.table.find(r.pos)
+	if i >= len(r.table.frames) {
+		return 0, io.EOF
+	}
+	if err := r.loadFrame(i); err != nil {
+		return 0, err
+	}
+	f := r.table.frames[i]
+	n := copy(p, r.buf[r.pos-f.decompressedOffset:])
+	r.pos += int64(n)
+	return n, nil
+}
+
+// loadFrame 解压第 i 帧到 buf
+func (r *seekableReader) loadFrame(i int) error {
+	if r.frame == i {
+		return nil
+	}
+	f := r.table.frames[i]
+	if int64(cap(r.packed)) < f.compressedSize {
+		r.packed = make([]byte, f.compressedSize)
+	}
+	r.packed = r.packed[:f.compressedSize]
+	if _, err := r.file.ReadAt(r.packed, f.compressedOffset); err != nil {
+		return err
+	}
+
+	buf, err := r.decoder.DecodeAll(r.packed, r.buf[:0])
+	if err != nil {
+		r.frame = -1
+		return err
+	}
+	if int64(len(buf)) != f.decompressedSize {
+		r.frame = -1
+		return fmt.Errorf("%s: 第 %d 帧解压后大小不符", r.file.Name(), i)
+	}
+	r.buf = buf
+	r.frame = i
+	return nil
+}
+
+func (r *seekableReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		offset += r.table.size()
+	default:
+		return 0, errors.New("无效的 whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("偏移量不能为负数")
+	}
+	r.pos = offset
+	return offset, nil
+}
+
+func (r *seekableReader) Close() error {
+	return r.file.Close()
+}
+
+// seekableWriter 按块压缩写入，关闭时写入跳转表（不关闭底层 writer）
+type seekableWriter struct {
+	w       io.Writer
+	encoder *zstd.Encoder
+	buf     []byte
+	packed  []byte
+	frames  []seekFrame
+	err     error
+}
+
+// newSeekableWriter 创建可寻址格式写入器
+func newSeekableWriter(w io.Writer) (*seekableWriter, error) {
+	encoder, _, err := zstdCodec()
+	if err != nil {
+		return nil, err
+	}
+	return &seekableWriter{w: w, encoder: encoder, buf: make([]byte, 0, seekableFrameSize)}, nil
+}
+
+func (w *seekableWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if w.err != nil {
+			return written, w.err
+		}
+		n := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+n]
+		p = p[n:]
+		written += n
+		if len(w.buf) == cap(w.buf) {
+			w.err = w.flush()
+		}
+	}
+	return written, w.err
+}
+
+// flush 将缓冲的内容压缩为一帧
+func (w *seekableWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	w.packed = w.encoder.EncodeAll(w.buf, w.packed[:0])
+	if _, err := w.w.Write(w.packed); err != nil {
+		return err
+	}
+	w.frames = append(w.frames, seekFrame{compressedSize: int64(len(w.packed)), decompressedSize: int64(len(w.buf))})
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// Close 写入最后一帧和跳转表
+func (w *seekableWriter) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if err := w.flush(); err != nil {
+		w.err = err
+		return err
+	}
+
+	tableSize := len(w.frames)*seekEntrySize + seekTableFooter
+	table := make([]byte, skippableHeaderLen, skippableHeaderLen+tableSize)
+	binary.LittleEndian.PutUint32(table[:4], skippableMagic)
+	binary.LittleEndian.PutUint32(table[4:8], uint32(tableSize))
+	for _, f := range w.frames {
+		table = binary.LittleEndian.AppendUint32(table, uint32(f.compressedSize))
+		table = binary.LittleEndian.AppendUint32(table, uint32(f.decompressedSize))
+	}
+	table = binary.LittleEndian.AppendUint32(table, uint32(len(w.frames)))
+	table = append(table, 0)
+	table = binary.LittleEndian.AppendUint32(table, seekableMagic)
+
+	_, err := w.w.Write(table)
+	w.err = errors.New("写入器已关闭")
+	return err
+}
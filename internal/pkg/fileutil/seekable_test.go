@@ -0,0 +1,220 @@
+package fileutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// buildSeekTable 按给定的每帧压缩前后大小构造跳转帧
+func buildSeekTable(frames [][2]uint32, checksum bool) []byte {
+	entrySize := seekEntrySize
+	descriptor := byte(0)
+	if checksum {
+		entrySize = seekEntryChecksum
+		descriptor = seekChecksumFlag
+	}
+	tableSize := len(frames)*entrySize + seekTableFooter
+
+	table := binary.LittleEndian.AppendUint32(nil, skippableMagic)
+	table = binary.LittleEndian.AppendUint32(table, uint32(tableSize))
+	for _, f := range frames {
+		table = binary.LittleEndian.AppendUint32(table, f[0])
+		table = binary.LittleEndian.AppendUint32(table, f[1])
+		if checksum {
+			table = binary.LittleEndian.AppendUint32(table, 0)
+		}
+	}
+	table = binary.LittleEndian.AppendUint32(table, uint32(len(frames)))
+	table = append(table, descriptor)
+	return binary.LittleEndian.AppendUint32(table, seekableMagic)
+}
+
+func TestReadSeekTable(t *testing.T) {
+	valid := append(make([]byte, 30), buildSeekTable([][2]uint32{{10, 100}, {20, 50}}, false)...)
+
+	badSkippable := append([]byte(nil), valid...)
+	binary.LittleEndian.PutUint32(badSkippable[30:], 0x184D2A50)
+
+	hugeCount := append([]byte(nil), valid...)
+	binary.LittleEndian.PutUint32(hugeCount[len(hugeCount)-seekTableFooter:], 0xFFFFFFFF)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		size    int64 // 解压后的总大小
+		frames  int
+		wantErr bool
+	}{
+		{name: "空文件", data: nil, wantErr: true},
+		{name: "小于跳转表最小长度", data: make([]byte, skippableHeaderLen+seekTableFooter-1), wantErr: true},
+		{name: "末尾不是魔数", data: make([]byte, 64), wantErr: true},
+		{name: "空跳转表", data: buildSeekTable(nil, false), size: 0, frames: 0},
+		{name: "两帧", data: valid, size: 150, frames: 2},
+		{name: "带校验和", data: append(make([]byte, 7), buildSeekTable([][2]uint32{{7, 9}}, true)...), size: 9, frames: 1},
+		{name: "帧数超出文件", data: hugeCount, wantErr: true},
+		{name: "跳转帧头错误", data: badSkippable, wantErr: true},
+		{name: "帧大小之和与数据长度不符", data: append(make([]byte, 29), buildSeekTable([][2]uint32{{10, 100}, {20, 50}}, false)...), wantErr: true},
+		{name: "缺少帧数据", data: buildSeekTable([][2]uint32{{10, 100}}, false), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := readSeekTable(bytes.NewReader(tt.data), int64(len(tt.data)))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("readSeekTable() 应返回错误，得到 %d 帧", len(table.frames))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readSeekTable() 错误: %v", err)
+			}
+			if len(table.frames) != tt.frames {
+				t.Errorf("帧数 = %d, 期望 %d", len(table.frames), tt.frames)
+			}
+			if table.size() != tt.size {
+				t.Errorf("size() = %d, 期望 %d", table.size(), tt.size)
+			}
+		})
+	}
+}
+
+func TestReadSeekTableNotSeekable(t *testing.T) {
+	_, err := readSeekTable(bytes.NewReader(make([]byte, 64)), 64)
+	if !errors.Is(err, errSeekTable) {
+		t.Errorf("readSeekTable() 错误 = %v, 期望 errSeekTable", err)
+	}
+}
+
+func TestSeekTableFind(t *testing.T) {
+	table := &seekTable{frames: []seekFrame{
+		{decompressedOffset: 0, decompressedSize: 10},
+		{decompressedOffset: 10, decompressedSize: 5},
+		{decompressedOffset: 15, decompressedSize: 10},
+	}}
+
+	tests := []struct {
+		offset int64
+		want   int
+	}{
+		{0, 0},
+		{9, 0},
+		{10, 1},
+		{14, 1},
+		{15, 2},
+		{24, 2},
+		{25, 3},
+		{100, 3},
+	}
+	for _, tt := range tests {
+		if got := table.find(tt.offset); got != tt.want {
+			t.Errorf("find(%d) = %d, 期望 %d", tt.offset, got, tt.want)
+		}
+	}
+}
+
+func TestSeekableRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	tests := []struct {
+		name   string
+		size   int
+		frames int
+	}{
+		{name: "空内容", size: 0, frames: 0},
+		{name: "一个字节", size: 1, frames: 1},
+		{name: "不足一帧", size: seekableFrameSize - 1, frames: 1},
+		{name: "正好一帧", size: seekableFrameSize, frames: 1},
+		{name: "多帧", size: 2*seekableFrameSize + 123, frames: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := make([]byte, tt.size)
+			for i := range content {
+				// 部分可压缩的内容
+				content[i] = byte('a' + rng.Intn(8))
+			}
+
+			path := filepath.Join(t.TempDir(), "data.lvz")
+			file, err := os.Create(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w, err := newSeekableWriter(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 分多次写入，跨越帧边界
+			for rest := content; len(rest) > 0; {
+				n := 1 + rng.Intn(300000)
+				if n > len(rest) {
+					n = len(rest)
+				}
+				if _, err := w.Write(rest[:n]); err != nil {
+					t.Fatal(err)
+				}
+				rest = rest[n:]
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			file.Close()
+
+			compressed, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := newSeekableReader(compressed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			if len(r.table.frames) != tt.frames {
+				t.Errorf("帧数 = %d, 期望 %d", len(r.table.frames), tt.frames)
+			}
+			if r.table.size() != int64(tt.size) {
+				t.Errorf("size() = %d, 期望 %d", r.table.size(), tt.size)
+			}
+
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Fatalf("读取的内容与写入的不同（长度 %d，期望 %d）", len(got), len(content))
+			}
+
+			// 随机位置读取，包括跨帧读取
+			for i := 0; i < 20 && tt.size > 0; i++ {
+				offset := rng.Int63n(int64(tt.size))
+				if _, err := r.Seek(offset, io.SeekStart); err != nil {
+					t.Fatal(err)
+				}
+				buf := make([]byte, 1+rng.Intn(4096))
+				n, err := io.ReadFull(r, buf)
+				if err != nil && err != io.ErrUnexpectedEOF {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(buf[:n], content[offset:offset+int64(n)]) {
+					t.Fatalf("偏移量 %d 处读取的内容不一致", offset)
+				}
+			}
+
+			end, err := r.Seek(0, io.SeekEnd)
+			if err != nil || end != int64(tt.size) {
+				t.Errorf("Seek(0, SeekEnd) = %d, %v, 期望 %d", end, err, tt.size)
+			}
+			if n, err := r.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+				t.Errorf("末尾读取 = %d, %v, 期望 0, EOF", n, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package fileutil
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 解压文件的存储方式
+const (
+	StoragePlain = ""     // 按原样保存
+	StorageZstd  = "zstd" // 每个文件保存为 zstd 可寻址格式，读取时按需解压
+)
+
+// CompressedSuffix 压缩保存的文件在磁盘上的后缀，列目录和读取时按去掉后缀的原文件名处理
+// 只有末尾带有效跳转表的文件才去掉后缀，日志包中原本就以 .lvz 结尾的文件按普通文件处理
+const CompressedSuffix = ".lvz"
+
+// File 存储中打开的文件，读取的是原文件内容，支持随机访问
+type File interface {
+	io.ReadSeekCloser
+}
+
+// Storage 解压文件的存储层，FileUtil 的所有读取都经过这里
+// 压缩和未压缩的文件可以共存：切换存储方式后，之前解压的日志仍能正常读取
+type Storage struct {
+	mode string
+}
+
+// NewStorage 创建存储层，mode 为 zstd 时新写入的文件压缩保存，其他值按原样保存
+func NewStorage(mode string) *Storage {
+	if strings.EqualFold(mode, StorageZstd) {
+		return &Storage{mode: StorageZstd}
+	}
+	return &Storage{mode: StoragePlain}
+}
+
+// Create 创建文件用于写入原文件内容，关闭后设置修改时间（为零时不设置）
+func (s *Storage) Create(name string, modTime time.Time) (io.WriteCloser, error) {
+	physical := name
+	if s.mode == StorageZstd {
+		physical += CompressedSuffix
+	}
+	file, err := os.Create(physical)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &storageWriter{Writer: file, file: file, path: physical, modTime: modTime}
+	if s.mode == StorageZstd {
+		enc, err := newSeekableWriter(file)
+		if err != nil {
+			file.Close()
+			os.Remove(physical)
+			return nil, err
+		}
+		w.enc = enc
+		w.Writer = enc
+	}
+	return w, nil
+}
+
+// storageWriter 关闭时先写完压缩数据再关闭文件，最后设置修改时间
+type storageWriter struct {
+	io.Writer
+	enc     *seekableWriter
+	file    *os.File
+	path    string
+	modTime time.Time
+}
+
+func (w *storageWriter) Close() error {
+	if w.enc != nil {
+		if err := w.enc.Close(); err != nil {
+			w.file.Close()
+			return err
+		}
+	}
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	if !w.modTime.IsZero() {
+		os.Chtimes(w.path, w.modTime, w.modTime)
+	}
+	return nil
+}
+
+// Open 打开文件读取原文件内容，压缩保存的文件按需解压
+func (s *Storage) Open(name string) (File, error) {
+	file, err := os.Open(name)
+	if err == nil {
+		return file, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	compressed, cerr := os.Open(name + CompressedSuffix)
+	if cerr != nil {
+		return nil, err
+	}
+	reader, cerr := newSeekableReader(compressed)
+	if cerr != nil {
+		compressed.Close()
+		// 不是压缩保存的文件，原文件不存在
+		if errors.Is(cerr, errSeekTable) {
+			return nil, err
+		}
+		return nil, cerr
+	}
+	return reader, nil
+}
+
+// ReadFile 读取文件的全部原内容
+func (s *Storage) ReadFile(name string) ([]byte, error) {
+	file, err := s.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// Stat 获取文件信息，压缩保存的文件返回原文件名和原大小
+func (s *Storage) Stat(name string) (fs.FileInfo, error) {
+	info, err := os.Stat(name)
+	if err == nil || !os.IsNotExist(err) {
+		return info, err
+	}
+
+	cinfo, cerr := os.Stat(name + CompressedSuffix)
+	if cerr != nil || cinfo.IsDir() {
+		return nil, err
+	}
+	info, cerr = compressedInfo(name+CompressedSuffix, cinfo)
+	if cerr != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// ReadDir 读取目录，压缩保存的文件按原文件名返回
+func (s *Storage) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := os.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+	for i, e := range entries {
+		if ce, ok := asCompressed(filepath.Join(name, e.Name()), e); ok {
+			entries[i] = ce
+		}
+	}
+	return entries, nil
+}
+
+// WalkDir 遍历目录，回调中的路径和名称均为原文件名
+func (s *Storage) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if d != nil {
+			if ce, ok := asCompressed(p, d); ok {
+				d = ce
+				p = strings.TrimSuffix(p, CompressedSuffix)
+			}
+		}
+		return fn(p, d, err)
+	})
+}
+
+// asCompressed 目录项是压缩保存的文件时，返回按原文件名和大小处理的目录项
+// 名称以 .lvz 结尾但没有有效跳转表的文件按普通文件处理
+func asCompressed(path string, d fs.DirEntry) (fs.DirEntry, bool) {
+	if !d.Type().IsRegular() || !strings.HasSuffix(d.Name(), CompressedSuffix) {
+		return d, false
+	}
+	info, err := d.Info()
+	if err != nil {
+		return d, false
+	}
+	cinfo, err := compressedInfo(path, info)
+	if err != nil {
+		return d, false
+	}
+	return compressedEntry{DirEntry: d, info: cinfo}, true
+}
+
+// DiskSize 目录下所有文件在磁盘上实际占用的大小，压缩保存的文件按压缩后的大小计算
+func (s *Storage) DiskSize(root string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// compressedEntry 压缩保存的目录项，名称和大小均为原文件的
+type compressedEntry struct {
+	fs.DirEntry
+	info fs.FileInfo
+}
+
+func (e compressedEntry) Name() string {
+	return e.info.Name()
+}
+
+func (e compressedEntry) Info() (fs.FileInfo, error) {
+	return e.info, nil
+}
+
+// compressedFileInfo 压缩保存文件的信息，Size 为解压后的大小
+type compressedFileInfo struct {
+	fs.FileInfo
+	size int64
+}
+
+func (i compressedFileInfo) Name() string {
+	return strings.TrimSuffix(i.FileInfo.Name(), CompressedSuffix)
+}
+
+func (i compressedFileInfo) Size() int64 {
+	return i.size
+}
+
+// compressedInfo 从可寻址格式末尾的跳转表读取原文件大小
+func compressedInfo(path string, info fs.FileInfo) (fs.FileInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	table, err := readSeekTable(file, info.Size())
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: path, Err: err}
+	}
+	return compressedFileInfo{FileInfo: info, size: table.size()}, nil
+}
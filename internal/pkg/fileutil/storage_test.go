@@ -0,0 +1,158 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestStorageCompressedSuffix(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  string
+		files map[string]string // 写入的原文件名和内容
+		plain map[string]string // 不经过存储层、直接写入磁盘的文件
+		want  map[string]string // 列目录看到的文件名和读取到的内容
+	}{
+		{
+			name:  "原样保存",
+			mode:  StoragePlain,
+			files: map[string]string{"a.log": "hello"},
+			want:  map[string]string{"a.log": "hello"},
+		},
+		{
+			name:  "压缩保存",
+			mode:  StorageZstd,
+			files: map[string]string{"a.log": "hello", "b.txt": ""},
+			want:  map[string]string{"a.log": "hello", "b.txt": ""},
+		},
+		{
+			name:  "压缩保存原本以lvz结尾的文件",
+			mode:  StorageZstd,
+			files: map[string]string{"data.lvz": "genuine"},
+			want:  map[string]string{"data.lvz": "genuine"},
+		},
+		{
+			name:  "原样保存时没有跳转表的lvz文件",
+			mode:  StoragePlain,
+			plain: map[string]string{"data.lvz": "genuine"},
+			want:  map[string]string{"data.lvz": "genuine"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			storage := NewStorage(tt.mode)
+			for name, content := range tt.files {
+				w, err := storage.Create(filepath.Join(dir, name), time.Time{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := w.Write([]byte(content)); err != nil {
+					t.Fatal(err)
+				}
+				if err := w.Close(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for name, content := range tt.plain {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			entries, err := storage.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, e := range entries {
+				names = append(names, e.Name())
+				info, err := e.Info()
+				if err != nil {
+					t.Fatalf("%s: Info() 错误: %v", e.Name(), err)
+				}
+				if want, ok := tt.want[e.Name()]; ok && info.Size() != int64(len(want)) {
+					t.Errorf("%s: 大小 = %d, 期望 %d", e.Name(), info.Size(), len(want))
+				}
+			}
+			var wantNames []string
+			for name := range tt.want {
+				wantNames = append(wantNames, name)
+			}
+			sort.Strings(names)
+			sort.Strings(wantNames)
+			if len(names) != len(wantNames) {
+				t.Fatalf("ReadDir() = %v, 期望 %v", names, wantNames)
+			}
+			for i := range names {
+				if names[i] != wantNames[i] {
+					t.Fatalf("ReadDir() = %v, 期望 %v", names, wantNames)
+				}
+			}
+
+			for name, want := range tt.want {
+				got, err := storage.ReadFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Fatalf("ReadFile(%s) 错误: %v", name, err)
+				}
+				if string(got) != want {
+					t.Errorf("ReadFile(%s) = %q, 期望 %q", name, got, want)
+				}
+			}
+
+			var walked []string
+			err = storage.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !d.IsDir() {
+					walked = append(walked, filepath.Base(p))
+					if filepath.Base(p) != d.Name() {
+						t.Errorf("WalkDir 路径 %s 与名称 %s 不一致", p, d.Name())
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			sort.Strings(walked)
+			if len(walked) != len(wantNames) {
+				t.Errorf("WalkDir() = %v, 期望 %v", walked, wantNames)
+			}
+		})
+	}
+}
+
+func TestStorageDiskSize(t *testing.T) {
+	dir := t.TempDir()
+	content := make([]byte, 256*1024) // 全零，压缩后远小于原大小
+	w, err := NewStorage(StorageZstd).Create(filepath.Join(dir, "zero.bin"), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(content)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	storage := NewStorage(StoragePlain)
+	info, err := storage.Stat(filepath.Join(dir, "zero.bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != int64(len(content)) {
+		t.Errorf("Stat().Size() = %d, 期望 %d", info.Size(), len(content))
+	}
+	size, err := storage.DiskSize(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size <= 0 || size >= int64(len(content)) {
+		t.Errorf("DiskSize() = %d, 期望大于0且小于 %d", size, len(content))
+	}
+}
@@ -0,0 +1,217 @@
+// Package gostack 解析Go程序的goroutine转储（panic、SIGQUIT、runtime.Stack 输出）
+package gostack
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Frame 栈帧
+type Frame struct {
+	Func string // 函数名，如 main.(*Server).handle
+	Args string // 参数（原样保留）
+	File string
+	Line int
+}
+
+// Goroutine 单个goroutine
+type Goroutine struct {
+	ID             int
+	State          string // 等待状态，如 running、chan receive、semacquire
+	WaitMinutes    int    // 已等待的分钟数（转储中未给出时为0）
+	LockedToThread bool
+	Frames         []Frame
+	Elided         bool   // 栈帧过多被省略
+	CreatedBy      *Frame // 创建者
+	CreatorID      int    // 创建者所在的goroutine（Go 1.21+）
+	Line           int    // 在文件中的起始行号
+}
+
+// Dump 一次完整的转储
+type Dump struct {
+	Line       int    // 起始行号
+	Panic      string // panic 或 fatal error 信息
+	Signal     string // 信号信息，如 [signal SIGSEGV: ...] 或 SIGQUIT: quit
+	PanicID    int    // 发生panic的goroutine，0表示未知
+	Goroutines []*Goroutine
+}
+
+var (
+	headerPattern  = regexp.MustCompile(`^goroutine (\d+)(?: gp=\S+ m=\S+(?: mp=\S+)?)? \[(.*)\]:\s*$`)
+	filePattern    = regexp.MustCompile(`^\s+(.+?):(\d+)(?: \+0x[0-9a-f]+)?\s*$`)
+	createdPattern = regexp.MustCompile(`^created by (.+?)(?: in goroutine (\d+))?\s*$`)
+	waitPattern    = regexp.MustCompile(`^(\d+) minutes?$`)
+	panicPattern   = regexp.MustCompile(`^(?:panic: |fatal error: )`)
+	signalPattern  = regexp.MustCompile(`^(?:\[signal |SIG[A-Z]+: )`)
+)
+
+// Parser 逐行解析转储，文件中的其他日志行会被忽略
+type Parser struct {
+	dumps   []*Dump
+	current *Dump
+	g       *Goroutine
+	// pendingFunc 等待文件行的函数行
+	pendingFunc *Frame
+	created     bool
+	// pendingPanic 尚未出现goroutine的panic信息，属于下一次转储
+	pendingPanic  string
+	pendingSignal string
+	panicLine     int
+	seen          map[int]bool
+}
+
+// NewParser 创建解析器
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Add 解析一行
+func (p *Parser) Add(lineNo int, line string) {
+	line = strings.TrimRight(line, "\r")
+
+	if m := headerPattern.FindStringSubmatch(line); m != nil {
+		p.endGoroutine()
+		id, _ := strconv.Atoi(m[1])
+		// 出现panic信息或goroutine ID重复时认为是新的一次转储
+		if p.current == nil || p.pendingPanic != "" || p.pendingSignal != "" || p.seen[id] {
+			p.startDump(lineNo)
+		}
+		p.seen[id] = true
+		p.g = &Goroutine{ID: id, Line: lineNo}
+		parseState(p.g, m[2])
+		p.current.Goroutines = append(p.current.Goroutines, p.g)
+		if p.current.Panic != "" && p.current.PanicID == 0 {
+			p.current.PanicID = id
+		}
+		return
+	}
+
+	if p.g != nil && p.addStackLine(line) {
+		return
+	}
+	p.endGoroutine()
+
+	switch {
+	case panicPattern.MatchString(line):
+		// 嵌套panic（[recovered]）只保留第一条
+		if p.pendingPanic == "" {
+			p.pendingPanic = line
+			p.panicLine = lineNo
+		}
+	case signalPattern.MatchString(line):
+		if p.pendingSignal == "" {
+			p.pendingSignal = line
+			if p.panicLine == 0 {
+				p.panicLine = lineNo
+			}
+		}
+	}
+}
+
+// addStackLine 处理goroutine内的栈帧行，不属于当前栈时返回false
+func (p *Parser) addStackLine(line string) bool {
+	if m := filePattern.FindStringSubmatch(line); m != nil && p.pendingFunc != nil {
+		p.pendingFunc.File = m[1]
+		p.pendingFunc.Line, _ = strconv.Atoi(m[2])
+		if p.created {
+			p.g.CreatedBy = p.pendingFunc
+		} else {
+			p.g.Frames = append(p.g.Frames, *p.pendingFunc)
+		}
+		p.pendingFunc = nil
+		return true
+	}
+
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || p.pendingFunc != nil || p.created {
+		return false
+	}
+	if strings.HasPrefix(trimmed, "...") {
+		p.g.Elided = true
+		return true
+	}
+	if m := createdPattern.FindStringSubmatch(trimmed); m != nil {
+		fn, args := splitCall(m[1])
+		p.pendingFunc = &Frame{Func: fn, Args: args}
+		p.g.CreatorID, _ = strconv.Atoi(m[2])
+		p.created = true
+		return true
+	}
+	// 函数行：pkg.fn(args)，函数名中不含空格
+	if !strings.HasSuffix(trimmed, ")") {
+		return false
+	}
+	if fn, args := splitCall(trimmed); fn != "" && !strings.ContainsAny(fn, " \t") {
+		p.pendingFunc = &Frame{Func: fn, Args: args}
+		return true
+	}
+	return false
+}
+
+// startDump 开始新的一次转储
+func (p *Parser) startDump(lineNo int) {
+	if p.panicLine > 0 {
+		lineNo = p.panicLine
+	}
+	p.current = &Dump{Line: lineNo, Panic: p.pendingPanic, Signal: p.pendingSignal}
+	p.dumps = append(p.dumps, p.current)
+	p.pendingPanic, p.pendingSignal, p.panicLine = "", "", 0
+	p.seen = make(map[int]bool)
+}
+
+// endGoroutine 结束当前goroutine
+func (p *Parser) endGoroutine() {
+	if p.g != nil && p.pendingFunc != nil && !p.created {
+		// 缺少文件行的栈帧（如截断的转储）
+		p.g.Frames = append(p.g.Frames, *p.pendingFunc)
+	}
+	p.g = nil
+	p.pendingFunc = nil
+	p.created = false
+}
+
+// Dumps 返回解析到的所有转储
+func (p *Parser) Dumps() []*Dump {
+	p.endGoroutine()
+	return p.dumps
+}
+
+// parseState 解析 [chan receive, 5 minutes, locked to thread]
+func parseState(g *Goroutine, state string) {
+	parts := strings.Split(state, ", ")
+	g.State = parts[0]
+	for _, part := range parts[1:] {
+		if m := waitPattern.FindStringSubmatch(part); m != nil {
+			g.WaitMinutes, _ = strconv.Atoi(m[1])
+		} else if part == "locked to thread" {
+			g.LockedToThread = true
+		} else {
+			// 其他附加说明保留在状态中
+			g.State += ", " + part
+		}
+	}
+}
+
+// splitCall 将 pkg.(*T).fn(0x1, {0x2, 0x3}) 拆分为函数名和参数
+func splitCall(s string) (string, string) {
+	if !strings.HasSuffix(s, ")") {
+		return s, ""
+	}
+	depth := 0
+	for i := len(s) - 1; i >= 0; i-- {
+		switch s[i] {
+		case ')':
+			depth++
+		case '(':
+			depth--
+			if depth == 0 {
+				if i == 0 {
+					return "", ""
+				}
+				return s[:i], s[i+1 : len(s)-1]
+			}
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,207 @@
+package gostack
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// parse 逐行解析文本，行号从1开始
+func parse(text string) []*Dump {
+	p := NewParser()
+	for i, line := range strings.Split(text, "\n") {
+		p.Add(i+1, line)
+	}
+	return p.Dumps()
+}
+
+const panicDump = `2024-01-01 10:00:00 INFO server started
+panic: runtime error: invalid memory address or nil pointer dereference
+[signal SIGSEGV: segmentation violation code=0x1 addr=0x0 pc=0x4a2b3c]
+
+goroutine 7 [running]:
+main.(*Server).handle(0xc000010000, {0x5e8f20, 0xc0000a0000})
+	/app/server.go:42 +0x1c
+main.main.func1()
+	/app/main.go:15 +0x25
+created by main.main in goroutine 1
+	/app/main.go:14 +0x6f
+
+goroutine 1 [chan receive, 5 minutes, locked to thread]:
+main.main()
+	/app/main.go:20 +0x85
+`
+
+func TestParserPanic(t *testing.T) {
+	dumps := parse(panicDump)
+	if len(dumps) != 1 {
+		t.Fatalf("转储数 = %d, 期望 1", len(dumps))
+	}
+	d := dumps[0]
+	if d.Line != 2 {
+		t.Errorf("Line = %d, 期望 2（panic 信息所在行）", d.Line)
+	}
+	if d.Panic != "panic: runtime error: invalid memory address or nil pointer dereference" {
+		t.Errorf("Panic = %q", d.Panic)
+	}
+	if !strings.HasPrefix(d.Signal, "[signal SIGSEGV") {
+		t.Errorf("Signal = %q", d.Signal)
+	}
+	if d.PanicID != 7 {
+		t.Errorf("PanicID = %d, 期望 7", d.PanicID)
+	}
+	if len(d.Goroutines) != 2 {
+		t.Fatalf("goroutine 数 = %d, 期望 2", len(d.Goroutines))
+	}
+
+	g := d.Goroutines[0]
+	wantFrames := []Frame{
+		{Func: "main.(*Server).handle", Args: "0xc000010000, {0x5e8f20, 0xc0000a0000}", File: "/app/server.go", Line: 42},
+		{Func: "main.main.func1", Args: "", File: "/app/main.go", Line: 15},
+	}
+	if g.ID != 7 || g.State != "running" || g.Line != 5 {
+		t.Errorf("goroutine = {ID:%d State:%q Line:%d}, 期望 {7 running 5}", g.ID, g.State, g.Line)
+	}
+	if !reflect.DeepEqual(g.Frames, wantFrames) {
+		t.Errorf("Frames = %+v, 期望 %+v", g.Frames, wantFrames)
+	}
+	wantCreated := &Frame{Func: "main.main", File: "/app/main.go", Line: 14}
+	if !reflect.DeepEqual(g.CreatedBy, wantCreated) || g.CreatorID != 1 {
+		t.Errorf("CreatedBy = %+v (goroutine %d), 期望 %+v (goroutine 1)", g.CreatedBy, g.CreatorID, wantCreated)
+	}
+
+	g = d.Goroutines[1]
+	if g.ID != 1 || g.State != "chan receive" || g.WaitMinutes != 5 || !g.LockedToThread {
+		t.Errorf("goroutine = %+v", g)
+	}
+	if len(g.Frames) != 1 || g.CreatedBy != nil {
+		t.Errorf("Frames = %+v, CreatedBy = %+v", g.Frames, g.CreatedBy)
+	}
+}
+
+func TestParserDumps(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		goroutines [][]int // 每次转储中的 goroutine ID
+		panics     []string
+	}{
+		{
+			name: "没有转储",
+			text: "INFO hello\nERROR world",
+		},
+		{
+			name:       "SIGQUIT 转储",
+			text:       "SIGQUIT: quit\nPC=0x46b1e1 m=0 sigcode=0\n\ngoroutine 1 [select]:\nmain.main()\n\t/app/main.go:9 +0x1\n",
+			goroutines: [][]int{{1}},
+			panics:     []string{""},
+		},
+		{
+			name: "重复的 goroutine ID 开始新的转储",
+			text: "goroutine 1 [running]:\nmain.main()\n\t/a.go:1\n\ngoroutine 2 [sleep]:\nmain.f()\n\t/a.go:2\n" +
+				"goroutine 1 [running]:\nmain.main()\n\t/a.go:1\n",
+			goroutines: [][]int{{1, 2}, {1}},
+			panics:     []string{"", ""},
+		},
+		{
+			name: "panic 信息开始新的转储",
+			text: "goroutine 1 [running]:\nmain.main()\n\t/a.go:1\n" +
+				"panic: boom\n\ngoroutine 3 [running]:\nmain.g()\n\t/a.go:3\n",
+			goroutines: [][]int{{1}, {3}},
+			panics:     []string{"", "panic: boom"},
+		},
+		{
+			name:       "嵌套 panic 只保留第一条",
+			text:       "panic: first [recovered]\n\tpanic: second\n\ngoroutine 5 [running]:\nmain.h()\n\t/a.go:5\n",
+			goroutines: [][]int{{5}},
+			panics:     []string{"panic: first [recovered]"},
+		},
+		{
+			name:       "Go 1.23 带 gp 和 m 的头部",
+			text:       "goroutine 9 gp=0xc000002380 m=3 mp=0xc000100008 [running]:\nmain.main()\n\t/a.go:1\n",
+			goroutines: [][]int{{9}},
+			panics:     []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dumps := parse(tt.text)
+			var goroutines [][]int
+			var panics []string
+			for _, d := range dumps {
+				var ids []int
+				for _, g := range d.Goroutines {
+					ids = append(ids, g.ID)
+				}
+				goroutines = append(goroutines, ids)
+				panics = append(panics, d.Panic)
+			}
+			if !reflect.DeepEqual(goroutines, tt.goroutines) {
+				t.Errorf("goroutine = %v, 期望 %v", goroutines, tt.goroutines)
+			}
+			if !reflect.DeepEqual(panics, tt.panics) {
+				t.Errorf("panic = %q, 期望 %q", panics, tt.panics)
+			}
+		})
+	}
+}
+
+func TestParserElidedAndTruncated(t *testing.T) {
+	text := "goroutine 4 [running]:\nmain.deep(...)\n\t/a.go:10\n...additional frames elided...\n" +
+		"goroutine 6 [IO wait]:\nmain.read()\n"
+	dumps := parse(text)
+	if len(dumps) != 1 || len(dumps[0].Goroutines) != 2 {
+		t.Fatalf("解析结果 = %+v", dumps)
+	}
+	if g := dumps[0].Goroutines[0]; !g.Elided || len(g.Frames) != 1 || g.Frames[0].Args != "..." {
+		t.Errorf("goroutine 4 = %+v", g)
+	}
+	// 缺少文件行的栈帧仍然保留
+	if g := dumps[0].Goroutines[1]; g.State != "IO wait" || len(g.Frames) != 1 || g.Frames[0].File != "" {
+		t.Errorf("goroutine 6 = %+v", g)
+	}
+}
+
+func TestParseState(t *testing.T) {
+	tests := []struct {
+		state   string
+		want    string
+		minutes int
+		locked  bool
+	}{
+		{"running", "running", 0, false},
+		{"chan receive, 1 minute", "chan receive", 1, false},
+		{"semacquire, 12 minutes, locked to thread", "semacquire", 12, true},
+		{"select (no cases)", "select (no cases)", 0, false},
+		{"sync.Mutex.Lock, durable", "sync.Mutex.Lock, durable", 0, false},
+	}
+	for _, tt := range tests {
+		g := &Goroutine{}
+		parseState(g, tt.state)
+		if g.State != tt.want || g.WaitMinutes != tt.minutes || g.LockedToThread != tt.locked {
+			t.Errorf("parseState(%q) = {%q %d %v}, 期望 {%q %d %v}",
+				tt.state, g.State, g.WaitMinutes, g.LockedToThread, tt.want, tt.minutes, tt.locked)
+		}
+	}
+}
+
+func TestSplitCall(t *testing.T) {
+	tests := []struct {
+		in, fn, args string
+	}{
+		{"main.main()", "main.main", ""},
+		{"main.(*Server).handle(0x1, {0x2, 0x3})", "main.(*Server).handle", "0x1, {0x2, 0x3}"},
+		{"net/http.(*conn).serve(0xc0001, {0x7f, 0xc00})", "net/http.(*conn).serve", "0xc0001, {0x7f, 0xc00}"},
+		{"main.f(...)", "main.f", "..."},
+		{"main.g", "main.g", ""},
+		{"(0x1)", "", ""},
+		{"main.h(0x1))", "", ""},
+	}
+	for _, tt := range tests {
+		fn, args := splitCall(tt.in)
+		if fn != tt.fn || args != tt.args {
+			t.Errorf("splitCall(%q) = (%q, %q), 期望 (%q, %q)", tt.in, fn, args, tt.fn, tt.args)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package logparser
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// 2009/11/10 23:00:00.000000 file.go:12: message（日期、微秒、文件名均由标志位控制）
+	goLogPattern = regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}(?:\.\d+)?) (?:([\w./-]+\.go:\d+): )?(.*)$`)
+
+	// zap console encoder：时间\t级别\t[logger\t][caller\t]消息[\t字段JSON]
+	zapLevelPattern = regexp.MustCompile(`^(?i:debug|info|warn|error|dpanic|panic|fatal)$`)
+	zapCallerRegexp = regexp.MustCompile(`^[\w./-]+\.go:\d+$`)
+
+	// logrus 文本格式（TTY）：INFO[0000] message  key=value
+	logrusPattern = regexp.MustCompile(`^(PANI|FATA|ERRO|WARN|INFO|DEBU|TRAC)\[([^\]]+)\] ?(.*)$`)
+)
+
+// GoLogParser Go标准库log包输出解析器
+type GoLogParser struct{}
+
+// Name 解析器名称
+func (GoLogParser) Name() string { return "golog" }
+
+// Parse 解析标准log格式
+func (GoLogParser) Parse(line string) (*Record, bool) {
+	m := goLogPattern.FindStringSubmatch(line)
+	if m == nil {
+		return nil, false
+	}
+	t, ok := ParseTime(m[1])
+	if !ok {
+		return nil, false
+	}
+
+	rec := &Record{
+		Format:  "golog",
+		Time:    t,
+		Message: m[3],
+		Level:   DetectLevel(m[3]),
+		PID:     detectPID(m[3]),
+	}
+	if m[2] != "" {
+		rec.Fields = map[string]string{"caller": m[2]}
+	}
+	return rec, true
+}
+
+// ZapParser zap console编码器输出解析器（JSON编码器由JSONParser处理）
+type ZapParser struct{}
+
+// Name 解析器名称
+func (ZapParser) Name() string { return "zap" }
+
+// Parse 解析tab分隔的zap console格式
+func (ZapParser) Parse(line string) (*Record, bool) {
+	parts := strings.Split(line, "\t")
+	if len(parts) < 3 || !zapLevelPattern.MatchString(parts[1]) {
+		return nil, false
+	}
+	t, ok := ParseTime(parts[0])
+	if !ok {
+		return nil, false
+	}
+
+	rec := &Record{
+		Format: "zap",
+		Time:   t,
+		Level:  NormalizeLevel(parts[1]),
+		Fields: map[string]string{},
+	}
+
+	rest := parts[2:]
+	// logger名称和caller是可选的，通过caller的 file.go:行号 形式区分
+	if len(rest) > 1 && !zapCallerRegexp.MatchString(rest[0]) && zapCallerRegexp.MatchString(rest[1]) {
+		rec.Component = rest[0]
+		rest = rest[1:]
+	}
+	if len(rest) > 1 && zapCallerRegexp.MatchString(rest[0]) {
+		rec.Fields["caller"] = rest[0]
+		rest = rest[1:]
+	}
+	rec.Message = rest[0]
+	if len(rest) > 1 {
+		rec.Fields["fields"] = strings.Join(rest[1:], "\t")
+	}
+	return rec, true
+}
+
+// LogrusParser logrus文本格式（TTY输出）解析器，非TTY输出为logfmt由LogfmtParser处理
+type LogrusParser struct{}
+
+// Name 解析器名称
+func (LogrusParser) Name() string { return "logrus" }
+
+// Parse 解析 LEVEL[时间] 消息 格式
+func (LogrusParser) Parse(line string) (*Record, bool) {
+	m := logrusPattern.FindStringSubmatch(line)
+	if m == nil {
+		return nil, false
+	}
+
+	rec := &Record{
+		Format:  "logrus",
+		Level:   NormalizeLevel(m[1]),
+		Message: strings.TrimSpace(m[3]),
+	}
+	// 开启 FullTimestamp 时括号内为完整时间，否则为启动后的秒数
+	if t, ok := ParseTime(m[2]); ok && strings.ContainsAny(m[2], "-:") {
+		rec.Time = t
+	} else {
+		rec.Fields = map[string]string{"elapsed": m[2]}
+	}
+	return rec, true
+}
@@ -0,0 +1,187 @@
+package logparser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// maxEventLines 单个事件的最大行数，超过后强制开始新事件，避免整个文件被合并为一个事件
+const maxEventLines = 1000
+
+// Event 多行事件，由首行和其后的续行（堆栈、panic、异常）组成
+type Event struct {
+	Record *Record  // 首行的解析结果
+	Lines  []string // 所有原始行（含首行）
+}
+
+// StartLine 首行行号
+func (e *Event) StartLine() int {
+	return e.Record.Line
+}
+
+// EndLine 最后一行行号
+func (e *Event) EndLine() int {
+	return e.Record.Line + len(e.Lines) - 1
+}
+
+// Text 事件的完整文本
+func (e *Event) Text() string {
+	return strings.Join(e.Lines, "\n")
+}
+
+// wrapperFormats 包装其他程序输出的格式，每行都能解析，续行需要根据消息正文判断
+var wrapperFormats = map[string]bool{
+	"docker":     true,
+	"syslog3164": true,
+	"syslog5424": true,
+}
+
+var (
+	// continuationPatterns 任何位置都视为续行的写法
+	continuationPatterns = []*regexp.Regexp{
+		// Go goroutine 堆栈头
+		regexp.MustCompile(`^goroutine \d+ \[`),
+		// Java/JavaScript 未缩进的栈帧
+		regexp.MustCompile(`^at \S`),
+		// Java 异常链和省略的栈帧
+		regexp.MustCompile(`^(?:Caused by|Suppressed): `),
+		regexp.MustCompile(`^\.\.\. \d+ (?:more|common frames omitted)`),
+		// Java 异常类名，如 java.lang.IllegalStateException: ...
+		regexp.MustCompile(`^(?:[a-zA-Z_$][\w$]*\.)+[A-Z][\w$]*(?:Exception|Error|Throwable)(?::|$)`),
+		// Python 堆栈头和异常链
+		regexp.MustCompile(`^Traceback \(most recent call last\):`),
+		regexp.MustCompile(`^(?:During handling of the above exception|The above exception was the direct cause)`),
+	}
+
+	// tracePatterns 只在堆栈中视为续行的写法
+	tracePatterns = []*regexp.Regexp{
+		// Go 栈帧函数名（如 main.main()）、goroutine 创建者、嵌套panic、信号和 go run 的退出码
+		regexp.MustCompile(`^[\w./*()\[\]{}-]+\(.*\)$`),
+		regexp.MustCompile(`^created by `),
+		regexp.MustCompile(`^(?:panic|fatal error): `),
+		regexp.MustCompile(`^\[signal `),
+		regexp.MustCompile(`^exit status \d+`),
+		// Python 堆栈的最后一行，如 ValueError: ...
+		regexp.MustCompile(`^[A-Za-z_][\w.]*(?:Error|Exception|Warning|Exit|Interrupt)\b`),
+	}
+
+	// traceStartPattern 开始一段堆栈的首行
+	traceStartPattern = regexp.MustCompile(`^(?:panic: |fatal error: |Traceback \(most recent call last\):|Exception in thread )`)
+
+	// pemBeginPattern、pemEndPattern PEM 块（证书、私钥）的边界，块内的行都属于同一事件，
+	// 按事件脱敏时才能遮盖整个块
+	pemBeginPattern = regexp.MustCompile(`-----BEGIN [A-Z0-9 ]+-----`)
+	pemEndPattern   = regexp.MustCompile(`-----END [A-Z0-9 ]+-----`)
+)
+
+// Grouper 将续行合并到前一条记录，组成多行事件
+// 续行判断依据缩进、goroutine N [、at ... 等堆栈特征，不符合的行（如带时间戳的新日志）开始新事件；
+// 结构化格式中能解析的行总是开始新事件，docker、syslog 等包装格式按消息正文判断
+type Grouper struct {
+	lp      *LineParser
+	current *Event
+	inTrace bool // 当前事件已包含堆栈
+	inPEM   bool // 当前事件中的 PEM 块尚未结束
+}
+
+// NewGrouper 创建多行事件分组器
+func (lp *LineParser) NewGrouper() *Grouper {
+	return &Grouper{lp: lp}
+}
+
+// Add 添加一行，该行开始新事件时返回已完成的上一个事件，否则返回nil
+func (g *Grouper) Add(lineNo int, line string) *Event {
+	rec, structured := g.lp.parse(lineNo, line)
+
+	if g.current != nil && len(g.current.Lines) < maxEventLines && g.isContinuation(rec, structured, line) {
+		g.current.Lines = append(g.current.Lines, line)
+		if strings.TrimSpace(line) != "" {
+			g.inTrace = true
+		}
+		g.updatePEM(g.messageText(rec, structured, line))
+		return nil
+	}
+
+	done := g.current
+	text := g.messageText(rec, structured, line)
+	g.current = &Event{Record: rec, Lines: []string{line}}
+	g.inTrace = traceStartPattern.MatchString(text)
+	g.inPEM = false
+	g.updatePEM(text)
+	return done
+}
+
+// updatePEM 根据新加入的行更新 PEM 块状态
+func (g *Grouper) updatePEM(text string) {
+	if g.inPEM {
+		g.inPEM = !pemEndPattern.MatchString(text)
+		return
+	}
+	if loc := pemBeginPattern.FindStringIndex(text); loc != nil {
+		g.inPEM = !pemEndPattern.MatchString(text[loc[1]:])
+	}
+}
+
+// Flush 返回最后一个事件，没有时返回nil
+func (g *Grouper) Flush() *Event {
+	done := g.current
+	g.current = nil
+	g.inTrace = false
+	g.inPEM = false
+	return done
+}
+
+// messageText 用于续行判断的文本：包装格式使用消息正文，其他格式使用原始行
+func (g *Grouper) messageText(rec *Record, structured bool, line string) string {
+	if structured && wrapperFormats[g.lp.Format()] {
+		return rec.Message
+	}
+	return line
+}
+
+// isContinuation 判断该行是否属于当前事件
+func (g *Grouper) isContinuation(rec *Record, structured bool, line string) bool {
+	if structured && !wrapperFormats[g.lp.Format()] {
+		return false
+	}
+	text := strings.TrimRight(g.messageText(rec, structured, line), "\r")
+
+	// PEM 块内的行（含单独一行的开始标记）都属于当前事件
+	if g.inPEM || strings.HasPrefix(text, "-----BEGIN ") && pemBeginPattern.MatchString(text) {
+		return true
+	}
+	if strings.TrimSpace(text) == "" {
+		return g.inTrace
+	}
+	if text[0] == ' ' || text[0] == '\t' {
+		return true
+	}
+	for _, p := range continuationPatterns {
+		if p.MatchString(text) {
+			return true
+		}
+	}
+	if g.inTrace {
+		for _, p := range tracePatterns {
+			if p.MatchString(text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GroupLines 将连续的行分组为事件，firstLine为第一行的行号
+func (lp *LineParser) GroupLines(firstLine int, lines []string) []*Event {
+	g := lp.NewGrouper()
+	var events []*Event
+	for i, line := range lines {
+		if ev := g.Add(firstLine+i, line); ev != nil {
+			events = append(events, ev)
+		}
+	}
+	if ev := g.Flush(); ev != nil {
+		events = append(events, ev)
+	}
+	return events
+}
@@ -0,0 +1,92 @@
+package logparser
+
+import (
+	"regexp"
+	"strconv"
+)
+
+var (
+	// combined格式：$remote_addr - $remote_user [$time_local] "$request" $status $body_bytes_sent "$http_referer" "$http_user_agent"
+	nginxAccessPattern = regexp.MustCompile(`^(\S+) \S+ (\S+) \[([^\]]+)\] "((?:[^"\\]|\\.)*)" (\d{3}) (\d+|-)(?: "((?:[^"\\]|\\.)*)" "((?:[^"\\]|\\.)*)")?`)
+
+	// 2024/01/02 15:04:05 [error] 1234#5678: *9 message
+	nginxErrorPattern = regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}) \[(\w+)\] (\d+)#(\d+): (?:\*(\d+) )?(.*)$`)
+)
+
+// NginxAccessParser nginx访问日志（common/combined）解析器
+type NginxAccessParser struct{}
+
+// Name 解析器名称
+func (NginxAccessParser) Name() string { return "nginx_access" }
+
+// Parse 解析访问日志，级别由状态码推导（5xx为ERROR，4xx为WARN）
+func (NginxAccessParser) Parse(line string) (*Record, bool) {
+	m := nginxAccessPattern.FindStringSubmatch(line)
+	if m == nil {
+		return nil, false
+	}
+
+	rec := &Record{
+		Format:  "nginx_access",
+		Message: m[4],
+		Fields: map[string]string{
+			"remote_addr": m[1],
+			"remote_user": m[2],
+			"status":      m[5],
+			"bytes":       m[6],
+		},
+	}
+	if m[7] != "" {
+		rec.Fields["referer"] = m[7]
+	}
+	if m[8] != "" {
+		rec.Fields["user_agent"] = m[8]
+	}
+	if t, ok := ParseTime(m[3]); ok {
+		rec.Time = t
+	}
+
+	status, _ := strconv.Atoi(m[5])
+	switch {
+	case status >= 500:
+		rec.Level = LevelError
+	case status >= 400:
+		rec.Level = LevelWarn
+	default:
+		rec.Level = LevelInfo
+	}
+	return rec, true
+}
+
+// NginxErrorParser nginx错误日志解析器
+type NginxErrorParser struct{}
+
+// Name 解析器名称
+func (NginxErrorParser) Name() string { return "nginx_error" }
+
+// Parse 解析错误日志
+func (NginxErrorParser) Parse(line string) (*Record, bool) {
+	m := nginxErrorPattern.FindStringSubmatch(line)
+	if m == nil {
+		return nil, false
+	}
+
+	rec := &Record{
+		Format:    "nginx_error",
+		Level:     NormalizeLevel(m[2]),
+		Component: "nginx",
+		Message:   m[6],
+		Fields:    map[string]string{"tid": m[4]},
+	}
+	if rec.Level == "" {
+		rec.Level = DetectLevel(m[2])
+	}
+	if t, ok := ParseTime(m[1]); ok {
+		rec.Time = t
+	}
+	rec.PID, _ = strconv.Atoi(m[3])
+	if m[5] != "" {
+		rec.Fields["connection"] = m[5]
+	}
+	return rec, true
+}
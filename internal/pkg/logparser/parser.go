@@ -0,0 +1,215 @@
+package logparser
+
+import (
+	"strings"
+	"time"
+)
+
+// 标准化后的日志级别
+const (
+	LevelFatal = "FATAL"
+	LevelError = "ERROR"
+	LevelWarn  = "WARN"
+	LevelInfo  = "INFO"
+	LevelDebug = "DEBUG"
+	LevelTrace = "TRACE"
+)
+
+// detectSampleSize 检测格式时采样的行数
+const detectSampleSize = 200
+
+// Record 解析后的日志记录
+type Record struct {
+	Line      int               // 行号（从1开始）
+	Time      time.Time         // 时间戳，零值表示未解析到
+	Level     string            // 标准化级别
+	Component string            // 组件/程序名/logger
+	PID       int               // 进程ID
+	Message   string            // 消息正文
+	Format    string            // 解析器名称
+	Raw       string            // 原始行
+	Fields    map[string]string // 其他结构化字段
+}
+
+// HasTime 是否解析到时间戳
+func (r *Record) HasTime() bool {
+	return !r.Time.IsZero()
+}
+
+// Parser 日志行解析器
+type Parser interface {
+	// Name 解析器名称
+	Name() string
+	// Parse 解析单行，格式不匹配时返回false
+	Parse(line string) (*Record, bool)
+}
+
+// Registry 解析器注册表
+type Registry struct {
+	parsers []Parser
+	byName  map[string]Parser
+	plain   Parser
+}
+
+// NewRegistry 创建解析器注册表，匹配时按注册顺序尝试
+func NewRegistry(parsers ...Parser) *Registry {
+	r := &Registry{
+		byName: make(map[string]Parser),
+		plain:  PlainParser{},
+	}
+	for _, p := range parsers {
+		r.Register(p)
+	}
+	return r
+}
+
+// Default 创建包含所有内置解析器的注册表
+func Default() *Registry {
+	return NewRegistry(
+		DockerParser{},
+		JSONParser{},
+		Syslog5424Parser{},
+		Syslog3164Parser{},
+		NginxAccessParser{},
+		NginxErrorParser{},
+		ZapParser{},
+		LogrusParser{},
+		LogfmtParser{},
+		GoLogParser{},
+	)
+}
+
+// Register 注册解析器，同名解析器会被替换
+func (r *Registry) Register(p Parser) {
+	if _, exists := r.byName[p.Name()]; exists {
+		for i, existing := range r.parsers {
+			if existing.Name() == p.Name() {
+				r.parsers[i] = p
+			}
+		}
+	} else {
+		r.parsers = append(r.parsers, p)
+	}
+	r.byName[p.Name()] = p
+}
+
+// Get 按名称获取解析器，"plain" 对应兜底的纯文本解析器
+func (r *Registry) Get(name string) (Parser, bool) {
+	if name == r.plain.Name() {
+		return r.plain, true
+	}
+	p, ok := r.byName[name]
+	return p, ok
+}
+
+// Names 返回所有解析器名称
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.parsers)+1)
+	for _, p := range r.parsers {
+		names = append(names, p.Name())
+	}
+	return append(names, r.plain.Name())
+}
+
+// Detect 根据样本行选出匹配率最高的解析器，都不合适时返回纯文本解析器
+func (r *Registry) Detect(sample []string) Parser {
+	counts := make([]int, len(r.parsers))
+	total := 0
+
+	for _, line := range sample {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		total++
+		if total > detectSampleSize {
+			break
+		}
+		for i, p := range r.parsers {
+			if _, ok := p.Parse(line); ok {
+				counts[i]++
+			}
+		}
+	}
+
+	best := -1
+	for i, count := range counts {
+		if best == -1 || count > counts[best] {
+			best = i
+		}
+	}
+
+	// 匹配率低于30%时认为不是结构化日志
+	if best == -1 || total == 0 || counts[best]*10 < total*3 {
+		return r.plain
+	}
+	return r.parsers[best]
+}
+
+// LineParser 绑定某种格式的行解析器，格式不匹配的行使用纯文本解析兜底
+type LineParser struct {
+	parser Parser
+	plain  Parser
+}
+
+// NewLineParser 创建行解析器
+func (r *Registry) NewLineParser(p Parser) *LineParser {
+	return &LineParser{parser: p, plain: r.plain}
+}
+
+// Format 当前使用的格式名称
+func (lp *LineParser) Format() string {
+	return lp.parser.Name()
+}
+
+// Parse 解析一行，总是返回记录
+func (lp *LineParser) Parse(lineNo int, line string) *Record {
+	rec, _ := lp.parse(lineNo, line)
+	return rec
+}
+
+// parse 解析一行，返回的bool表示是否由结构化格式（非纯文本）解析
+func (lp *LineParser) parse(lineNo int, line string) (*Record, bool) {
+	rec, ok := lp.parser.Parse(line)
+	structured := ok && lp.parser != lp.plain
+	if !ok {
+		rec, _ = lp.plain.Parse(line)
+	}
+	rec.Line = lineNo
+	rec.Raw = line
+	return rec, structured
+}
+
+// NormalizeLevel 将各种级别写法统一为标准级别，无法识别时返回空字符串
+func NormalizeLevel(level string) string {
+	switch strings.ToUpper(strings.TrimSpace(level)) {
+	case "FATAL", "FATA", "PANIC", "PANI", "DPANIC", "CRIT", "CRITICAL", "ALERT", "EMERG", "EMERGENCY", "F":
+		return LevelFatal
+	case "ERROR", "ERRO", "ERR", "SEVERE", "E":
+		return LevelError
+	case "WARN", "WARNING", "WARNI", "W":
+		return LevelWarn
+	case "INFO", "NOTICE", "INFORMATION", "I":
+		return LevelInfo
+	case "DEBUG", "DEBU", "DBG", "D":
+		return LevelDebug
+	case "TRACE", "TRAC", "T":
+		return LevelTrace
+	}
+	return ""
+}
+
+// syslogSeverityLevel 将syslog的severity（0-7）转换为标准级别
+func syslogSeverityLevel(severity int) string {
+	switch {
+	case severity <= 2:
+		return LevelFatal
+	case severity == 3:
+		return LevelError
+	case severity == 4:
+		return LevelWarn
+	case severity <= 6:
+		return LevelInfo
+	default:
+		return LevelDebug
+	}
+}
@@ -0,0 +1,281 @@
+package logparser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsers(t *testing.T) {
+	local := func(year int, month time.Month, day, hour, min, sec int) time.Time {
+		return time.Date(year, month, day, hour, min, sec, 0, time.Local)
+	}
+	utc := func(hour, min, sec int) time.Time {
+		return time.Date(2024, 1, 2, hour, min, sec, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name      string
+		parser    Parser
+		line      string
+		ok        bool
+		time      time.Time
+		level     string
+		component string
+		pid       int
+		message   string
+	}{
+		{
+			name:   "json",
+			parser: JSONParser{},
+			line:   `{"ts":"2024-01-02T15:04:05Z","level":"error","msg":"boom","logger":"api","pid":42,"user":"bob"}`,
+			ok:     true,
+			time:   utc(15, 4, 5),
+			level:  LevelError, component: "api", pid: 42, message: "boom",
+		},
+		{
+			name:   "json 数字级别和Unix时间戳",
+			parser: JSONParser{},
+			line:   `{"time":1704207845000,"level":40,"msg":"slow"}`,
+			ok:     true,
+			time:   utc(15, 4, 5),
+			level:  LevelWarn, message: "slow",
+		},
+		{name: "json 不是对象", parser: JSONParser{}, line: `["a"]`},
+		{
+			name:      "docker",
+			parser:    DockerParser{},
+			line:      `{"log":"ERROR failed to connect\n","stream":"stderr","time":"2024-01-02T15:04:05.123Z"}`,
+			ok:        true,
+			time:      utc(15, 4, 5).Add(123 * time.Millisecond),
+			level:     LevelError,
+			component: "stderr",
+			message:   "ERROR failed to connect",
+		},
+		{name: "docker 缺少stream", parser: DockerParser{}, line: `{"log":"x"}`},
+		{
+			name:      "syslog5424",
+			parser:    Syslog5424Parser{},
+			line:      `<11>1 2024-01-02T15:04:05Z host app 123 ID47 - disk failure`,
+			ok:        true,
+			time:      utc(15, 4, 5),
+			level:     LevelError,
+			component: "app",
+			pid:       123,
+			message:   "disk failure",
+		},
+		{
+			name:      "syslog3164",
+			parser:    Syslog3164Parser{},
+			line:      `<12>Jan  2 15:04:05 host sshd[99]: invalid user`,
+			ok:        true,
+			level:     LevelWarn,
+			component: "sshd",
+			pid:       99,
+			message:   "invalid user",
+		},
+		{
+			name:      "syslog3164 省略PRI时按关键字检测级别",
+			parser:    Syslog3164Parser{},
+			line:      `Jan  2 15:04:05 host kernel: error on sda`,
+			ok:        true,
+			level:     LevelError,
+			component: "kernel",
+			message:   "error on sda",
+		},
+		{
+			name:    "nginx_access",
+			parser:  NginxAccessParser{},
+			line:    `10.0.0.1 - - [02/Jan/2024:15:04:05 +0000] "GET /api HTTP/1.1" 502 12 "-" "curl/8"`,
+			ok:      true,
+			time:    utc(15, 4, 5),
+			level:   LevelError,
+			message: "GET /api HTTP/1.1",
+		},
+		{
+			name:    "nginx_access 4xx",
+			parser:  NginxAccessParser{},
+			line:    `10.0.0.1 - bob [02/Jan/2024:15:04:05 +0000] "GET / HTTP/1.1" 404 0`,
+			ok:      true,
+			time:    utc(15, 4, 5),
+			level:   LevelWarn,
+			message: "GET / HTTP/1.1",
+		},
+		{
+			name:      "nginx_error",
+			parser:    NginxErrorParser{},
+			line:      `2024/01/02 15:04:05 [crit] 1234#5678: *9 open() failed`,
+			ok:        true,
+			time:      local(2024, 1, 2, 15, 4, 5),
+			level:     LevelFatal,
+			component: "nginx",
+			pid:       1234,
+			message:   "open() failed",
+		},
+		{
+			name:      "zap console",
+			parser:    ZapParser{},
+			line:      "2024-01-02T15:04:05.000Z\tWARN\tserver\tmain.go:12\tslow request\t{\"ms\": 900}",
+			ok:        true,
+			time:      utc(15, 4, 5),
+			level:     LevelWarn,
+			component: "server",
+			message:   "slow request",
+		},
+		{name: "zap 级别无效", parser: ZapParser{}, line: "2024-01-02T15:04:05Z\tNOPE\tmsg"},
+		{
+			name:    "logrus 完整时间",
+			parser:  LogrusParser{},
+			line:    `ERRO[2024-01-02T15:04:05Z] write failed  path=/tmp`,
+			ok:      true,
+			time:    utc(15, 4, 5),
+			level:   LevelError,
+			message: "write failed  path=/tmp",
+		},
+		{
+			name:    "logrus 启动后秒数",
+			parser:  LogrusParser{},
+			line:    `INFO[0003] started`,
+			ok:      true,
+			level:   LevelInfo,
+			message: "started",
+		},
+		{
+			name:      "logfmt",
+			parser:    LogfmtParser{},
+			line:      `time=2024-01-02T15:04:05Z level=debug msg="cache miss" component=store`,
+			ok:        true,
+			time:      utc(15, 4, 5),
+			level:     LevelDebug,
+			component: "store",
+			message:   "cache miss",
+		},
+		{name: "logfmt 没有常见字段", parser: LogfmtParser{}, line: `a=1 b=2`},
+		{name: "logfmt 引号未闭合", parser: LogfmtParser{}, line: `level=info msg="oops`},
+		{
+			name:    "golog",
+			parser:  GoLogParser{},
+			line:    `2024/01/02 15:04:05 main.go:10: panic: nil map`,
+			ok:      true,
+			time:    local(2024, 1, 2, 15, 4, 5),
+			level:   LevelFatal,
+			message: "panic: nil map",
+		},
+		{
+			name:    "plain",
+			parser:  PlainParser{},
+			line:    `[2024-01-02 15:04:05] Warning: disk almost full pid=7`,
+			ok:      true,
+			time:    local(2024, 1, 2, 15, 4, 5),
+			level:   LevelWarn,
+			pid:     7,
+			message: `[2024-01-02 15:04:05] Warning: disk almost full pid=7`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, ok := tt.parser.Parse(tt.line)
+			if ok != tt.ok {
+				t.Fatalf("Parse() ok = %v, 期望 %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if rec.Format != tt.parser.Name() {
+				t.Errorf("Format = %q, 期望 %q", rec.Format, tt.parser.Name())
+			}
+			// RFC3164 没有年份，只比较月日时分秒
+			if _, isSyslog := tt.parser.(Syslog3164Parser); isSyslog {
+				if rec.Time.Month() != time.January || rec.Time.Day() != 2 || rec.Time.Hour() != 15 {
+					t.Errorf("Time = %v", rec.Time)
+				}
+			} else if !rec.Time.Equal(tt.time) {
+				t.Errorf("Time = %v, 期望 %v", rec.Time, tt.time)
+			}
+			if rec.Level != tt.level {
+				t.Errorf("Level = %q, 期望 %q", rec.Level, tt.level)
+			}
+			if rec.Component != tt.component {
+				t.Errorf("Component = %q, 期望 %q", rec.Component, tt.component)
+			}
+			if rec.PID != tt.pid {
+				t.Errorf("PID = %d, 期望 %d", rec.PID, tt.pid)
+			}
+			if rec.Message != tt.message {
+				t.Errorf("Message = %q, 期望 %q", rec.Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestNormalizeLevel(t *testing.T) {
+	tests := map[string]string{
+		"error": LevelError, "ERRO": LevelError, "E": LevelError,
+		"warning": LevelWarn, "notice": LevelInfo, "DBG": LevelDebug,
+		"crit": LevelFatal, "panic": LevelFatal, "trace": LevelTrace,
+		" Info ": LevelInfo, "verbose": "", "": "",
+	}
+	for in, want := range tests {
+		if got := NormalizeLevel(in); got != want {
+			t.Errorf("NormalizeLevel(%q) = %q, 期望 %q", in, got, want)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+		ok   bool
+	}{
+		{"2024-01-02T15:04:05.5Z", time.Date(2024, 1, 2, 15, 4, 5, 5e8, time.UTC), true},
+		{"2024-01-02T15:04:05+08:00", time.Date(2024, 1, 2, 7, 4, 5, 0, time.UTC), true},
+		{"2024-01-02 15:04:05,123", time.Date(2024, 1, 2, 15, 4, 5, 123e6, time.Local), true},
+		{"02/Jan/2024:15:04:05 -0700", time.Date(2024, 1, 2, 22, 4, 5, 0, time.UTC), true},
+		{"1704207845", time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC), true},
+		{"1704207845.25", time.Date(2024, 1, 2, 15, 4, 5, 25e7, time.UTC), true},
+		{"1704207845123", time.Date(2024, 1, 2, 15, 4, 5, 123e6, time.UTC), true},
+		{"not a time", time.Time{}, false},
+		{"", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseTime(tt.in)
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q) = %v, %v, 期望 %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample []string
+		want   string
+	}{
+		{
+			name:   "json",
+			sample: []string{`{"level":"info","msg":"a"}`, `{"level":"warn","msg":"b"}`, "not json"},
+			want:   "json",
+		},
+		{
+			name:   "nginx_access",
+			sample: []string{`1.2.3.4 - - [02/Jan/2024:15:04:05 +0000] "GET / HTTP/1.1" 200 1`},
+			want:   "nginx_access",
+		},
+		{
+			name:   "匹配率过低时为纯文本",
+			sample: []string{`{"level":"info"}`, "a", "b", "c", "d"},
+			want:   "plain",
+		},
+		{name: "空样本", sample: []string{"", "  "}, want: "plain"},
+	}
+
+	r := Default()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Detect(tt.sample).Name(); got != tt.want {
+				t.Errorf("Detect() = %q, 期望 %q", got, tt.want)
+			}
+		})
+	}
+}
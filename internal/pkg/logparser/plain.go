@@ -0,0 +1,68 @@
+package logparser
+
+import (
+	"regexp"
+	"strconv"
+)
+
+var (
+	// levelKeywordPatterns 按优先级排列的级别关键字，与前端 detectLogLevel 的判断顺序一致
+	levelKeywordPatterns = []struct {
+		level   string
+		pattern *regexp.Regexp
+	}{
+		{LevelFatal, regexp.MustCompile(`(?i)\b(?:fatal|panic|critical)\b`)},
+		{LevelError, regexp.MustCompile(`(?i)\b(?:error|severe)\b`)},
+		{LevelWarn, regexp.MustCompile(`(?i)\bwarn(?:ing)?\b`)},
+		{LevelInfo, regexp.MustCompile(`(?i)\binfo\b`)},
+		{LevelDebug, regexp.MustCompile(`(?i)\bdebug\b`)},
+		{LevelTrace, regexp.MustCompile(`(?i)\btrace\b`)},
+	}
+
+	// pidPattern 常见的进程ID写法：pid=123、pid:123、name[123]
+	pidPattern = regexp.MustCompile(`(?i)\bpid[=: ]\s*(\d+)|\w\[(\d+)\]`)
+)
+
+// DetectLevel 通过关键字检测行的日志级别
+func DetectLevel(line string) string {
+	for _, kw := range levelKeywordPatterns {
+		if kw.pattern.MatchString(line) {
+			return kw.level
+		}
+	}
+	return ""
+}
+
+// detectPID 从文本中提取进程ID
+func detectPID(line string) int {
+	m := pidPattern.FindStringSubmatch(line)
+	if m == nil {
+		return 0
+	}
+	value := m[1]
+	if value == "" {
+		value = m[2]
+	}
+	pid, _ := strconv.Atoi(value)
+	return pid
+}
+
+// PlainParser 纯文本兜底解析器，通过关键字和行内时间戳提取信息
+type PlainParser struct{}
+
+// Name 解析器名称
+func (PlainParser) Name() string { return "plain" }
+
+// Parse 总是成功
+func (PlainParser) Parse(line string) (*Record, bool) {
+	rec := &Record{
+		Format:  "plain",
+		Level:   DetectLevel(line),
+		PID:     detectPID(line),
+		Message: line,
+	}
+	if t, ok := FindTime(line); ok {
+		rec.Time = t
+	}
+	return rec, true
+}
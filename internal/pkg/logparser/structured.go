@@ -0,0 +1,285 @@
+package logparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 结构化日志中常见的字段名
+var (
+	timeKeys      = []string{"time", "ts", "timestamp", "@timestamp", "t", "date", "datetime"}
+	levelKeys     = []string{"level", "lvl", "severity", "loglevel", "log_level", "levelname"}
+	messageKeys   = []string{"msg", "message", "log", "text"}
+	componentKeys = []string{"logger", "component", "module", "name", "service", "caller", "source"}
+	pidKeys       = []string{"pid", "process_id", "processId"}
+)
+
+// JSONParser JSON Lines解析器（zap/logrus/bunyan等JSON输出）
+type JSONParser struct{}
+
+// Name 解析器名称
+func (JSONParser) Name() string { return "json" }
+
+// Parse 解析单行JSON对象
+func (JSONParser) Parse(line string) (*Record, bool) {
+	obj, ok := decodeJSONObject(line)
+	if !ok {
+		return nil, false
+	}
+
+	rec := &Record{Format: "json", Fields: map[string]string{}}
+	used := map[string]bool{}
+
+	if key, value := firstKey(obj, timeKeys); key != "" {
+		used[key] = true
+		if t, ok := ParseTime(value); ok {
+			rec.Time = t
+		}
+	}
+	if key, value := firstKey(obj, levelKeys); key != "" {
+		used[key] = true
+		rec.Level = NormalizeLevel(value)
+		if rec.Level == "" {
+			// bunyan等使用数字级别
+			if n, err := strconv.Atoi(value); err == nil {
+				rec.Level = numericLevel(n)
+			}
+		}
+	}
+	if key, value := firstKey(obj, messageKeys); key != "" {
+		used[key] = true
+		rec.Message = value
+	}
+	if key, value := firstKey(obj, componentKeys); key != "" {
+		used[key] = true
+		rec.Component = value
+	}
+	if key, value := firstKey(obj, pidKeys); key != "" {
+		used[key] = true
+		rec.PID, _ = strconv.Atoi(value)
+	}
+
+	for key, value := range obj {
+		if !used[key] {
+			rec.Fields[key] = stringify(value)
+		}
+	}
+	if rec.Level == "" {
+		rec.Level = DetectLevel(rec.Message)
+	}
+	return rec, true
+}
+
+// DockerParser Docker json-file日志驱动解析器：{"log":"...","stream":"stdout","time":"..."}
+type DockerParser struct{}
+
+// Name 解析器名称
+func (DockerParser) Name() string { return "docker" }
+
+// Parse 解析Docker json-file格式，内层日志行再用纯文本规则提取级别
+func (DockerParser) Parse(line string) (*Record, bool) {
+	obj, ok := decodeJSONObject(line)
+	if !ok {
+		return nil, false
+	}
+	logLine, hasLog := obj["log"].(string)
+	stream, hasStream := obj["stream"].(string)
+	if !hasLog || !hasStream {
+		return nil, false
+	}
+
+	message := strings.TrimRight(logLine, "\r\n")
+	rec := &Record{
+		Format:    "docker",
+		Component: stream,
+		Message:   message,
+		Level:     DetectLevel(message),
+		PID:       detectPID(message),
+	}
+	if ts, ok := obj["time"].(string); ok {
+		if t, ok := ParseTime(ts); ok {
+			rec.Time = t
+		}
+	}
+	return rec, true
+}
+
+// LogfmtParser logfmt解析器（key=value key2="quoted value"）
+type LogfmtParser struct{}
+
+// Name 解析器名称
+func (LogfmtParser) Name() string { return "logfmt" }
+
+// Parse 解析logfmt格式，至少需要两个键值对且包含常见字段
+func (LogfmtParser) Parse(line string) (*Record, bool) {
+	pairs, ok := parseLogfmt(line)
+	if !ok || len(pairs) < 2 {
+		return nil, false
+	}
+
+	values := make(map[string]interface{}, len(pairs))
+	for _, kv := range pairs {
+		values[kv[0]] = kv[1]
+	}
+
+	rec := &Record{Format: "logfmt", Fields: map[string]string{}}
+	used := map[string]bool{}
+	known := false
+
+	if key, value := firstKey(values, timeKeys); key != "" {
+		used[key], known = true, true
+		if t, ok := ParseTime(value); ok {
+			rec.Time = t
+		}
+	}
+	if key, value := firstKey(values, levelKeys); key != "" {
+		used[key], known = true, true
+		rec.Level = NormalizeLevel(value)
+	}
+	if key, value := firstKey(values, messageKeys); key != "" {
+		used[key], known = true, true
+		rec.Message = value
+	}
+	if !known {
+		return nil, false
+	}
+	if key, value := firstKey(values, componentKeys); key != "" {
+		used[key] = true
+		rec.Component = value
+	}
+	if key, value := firstKey(values, pidKeys); key != "" {
+		used[key] = true
+		rec.PID, _ = strconv.Atoi(value)
+	}
+
+	for _, kv := range pairs {
+		if !used[kv[0]] {
+			rec.Fields[kv[0]] = kv[1]
+		}
+	}
+	return rec, true
+}
+
+// parseLogfmt 将logfmt行拆分为有序的键值对
+func parseLogfmt(line string) ([][2]string, bool) {
+	var pairs [][2]string
+	i := 0
+	n := len(line)
+
+	for i < n {
+		for i < n && line[i] == ' ' {
+			i++
+		}
+		if i >= n {
+			break
+		}
+
+		start := i
+		for i < n && line[i] != '=' && line[i] != ' ' {
+			i++
+		}
+		key := line[start:i]
+		if key == "" || i >= n || line[i] != '=' {
+			return nil, false
+		}
+		i++
+
+		var value string
+		if i < n && line[i] == '"' {
+			i++
+			var sb strings.Builder
+			closed := false
+			for i < n {
+				c := line[i]
+				if c == '\\' && i+1 < n {
+					sb.WriteByte(line[i+1])
+					i += 2
+					continue
+				}
+				if c == '"' {
+					closed = true
+					i++
+					break
+				}
+				sb.WriteByte(c)
+				i++
+			}
+			if !closed {
+				return nil, false
+			}
+			value = sb.String()
+		} else {
+			start = i
+			for i < n && line[i] != ' ' {
+				i++
+			}
+			value = line[start:i]
+		}
+		pairs = append(pairs, [2]string{key, value})
+	}
+
+	return pairs, true
+}
+
+// decodeJSONObject 快速判断并解析单行JSON对象
+func decodeJSONObject(line string) (map[string]interface{}, bool) {
+	line = strings.TrimSpace(line)
+	if len(line) < 2 || line[0] != '{' || line[len(line)-1] != '}' {
+		return nil, false
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &obj); err != nil {
+		return nil, false
+	}
+	return obj, true
+}
+
+// firstKey 返回第一个存在的候选字段及其字符串值
+func firstKey(obj map[string]interface{}, keys []string) (string, string) {
+	for _, key := range keys {
+		if value, ok := obj[key]; ok && value != nil {
+			return key, stringify(value)
+		}
+	}
+	return "", ""
+}
+
+// stringify 将JSON值转换为字符串
+func stringify(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return ""
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	}
+}
+
+// numericLevel 转换bunyan/pino风格的数字级别
+func numericLevel(n int) string {
+	switch {
+	case n >= 60:
+		return LevelFatal
+	case n >= 50:
+		return LevelError
+	case n >= 40:
+		return LevelWarn
+	case n >= 30:
+		return LevelInfo
+	case n >= 20:
+		return LevelDebug
+	default:
+		return LevelTrace
+	}
+}
@@ -0,0 +1,91 @@
+package logparser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+	syslog5424Pattern = regexp.MustCompile(`^<(\d{1,3})>1 (\S+) (\S+) (\S+) (\S+) (\S+) (-|(?:\[(?:[^\]\\]|\\.)*\])+)(?: (.*))?$`)
+
+	// [<PRI>]Mmm dd hh:mm:ss HOSTNAME TAG[PID]: MSG
+	syslog3164Pattern = regexp.MustCompile(`^(?:<(\d{1,3})>)?([A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2}(?:\.\d+)?) (\S+) ([^\s\[:]+)(?:\[(\d+)\])?: ?(.*)$`)
+)
+
+// Syslog5424Parser RFC5424 syslog解析器
+type Syslog5424Parser struct{}
+
+// Name 解析器名称
+func (Syslog5424Parser) Name() string { return "syslog5424" }
+
+// Parse 解析RFC5424格式
+func (Syslog5424Parser) Parse(line string) (*Record, bool) {
+	m := syslog5424Pattern.FindStringSubmatch(line)
+	if m == nil {
+		return nil, false
+	}
+
+	rec := &Record{
+		Format:  "syslog5424",
+		Message: strings.TrimPrefix(m[8], "\ufeff"),
+		Fields:  map[string]string{"host": m[3]},
+	}
+	if pri, err := strconv.Atoi(m[1]); err == nil {
+		rec.Level = syslogSeverityLevel(pri % 8)
+		rec.Fields["facility"] = strconv.Itoa(pri / 8)
+	}
+	if t, ok := ParseTime(m[2]); ok {
+		rec.Time = t
+	}
+	if m[4] != "-" {
+		rec.Component = m[4]
+	}
+	if pid, err := strconv.Atoi(m[5]); err == nil {
+		rec.PID = pid
+	}
+	if m[6] != "-" {
+		rec.Fields["msgid"] = m[6]
+	}
+	if m[7] != "-" {
+		rec.Fields["structured_data"] = m[7]
+	}
+	return rec, true
+}
+
+// Syslog3164Parser RFC3164（BSD）syslog解析器，PRI可省略（如 /var/log/messages）
+type Syslog3164Parser struct{}
+
+// Name 解析器名称
+func (Syslog3164Parser) Name() string { return "syslog3164" }
+
+// Parse 解析RFC3164格式
+func (Syslog3164Parser) Parse(line string) (*Record, bool) {
+	m := syslog3164Pattern.FindStringSubmatch(line)
+	if m == nil {
+		return nil, false
+	}
+
+	t, ok := ParseTime(m[2])
+	if !ok {
+		return nil, false
+	}
+
+	rec := &Record{
+		Format:    "syslog3164",
+		Time:      t,
+		Component: m[4],
+		Message:   m[6],
+		Fields:    map[string]string{"host": m[3]},
+	}
+	if pri, err := strconv.Atoi(m[1]); err == nil {
+		rec.Level = syslogSeverityLevel(pri % 8)
+	} else {
+		rec.Level = DetectLevel(m[6])
+	}
+	if pid, err := strconv.Atoi(m[5]); err == nil {
+		rec.PID = pid
+	}
+	return rec, true
+}
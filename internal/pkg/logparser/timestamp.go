@@ -0,0 +1,94 @@
+package logparser
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeLayouts 常见时间戳格式，按常用程度排列
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999 -0700",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05,999999999",
+	"2006/01/02 15:04:05.999999999",
+	"2006/01/02 15:04:05",
+	"02/Jan/2006:15:04:05 -0700",
+	"Jan _2 15:04:05.999999999",
+	"Jan _2 15:04:05",
+	"Mon Jan _2 15:04:05 2006",
+	"Mon Jan _2 15:04:05 MST 2006",
+	"2006-01-02T15:04:05.999999999-0700",
+	"20060102 15:04:05.999999999",
+}
+
+// timestampPattern 在任意文本行中定位时间戳
+var timestampPattern = regexp.MustCompile(
+	`\d{4}[-/]\d{2}[-/]\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?` +
+		`|\d{2}/[A-Z][a-z]{2}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}` +
+		`|[A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2}(?:\.\d+)?`)
+
+// ParseTime 解析时间戳字符串，支持常见文本格式和Unix时间戳（秒/毫秒）
+func ParseTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return fillYear(t), true
+		}
+	}
+
+	// Unix时间戳（zap等JSON日志常用浮点秒）
+	if f, err := strconv.ParseFloat(value, 64); err == nil && f > 0 {
+		return unixTime(f), true
+	}
+
+	return time.Time{}, false
+}
+
+// FindTime 在行中查找第一个可识别的时间戳
+func FindTime(line string) (time.Time, bool) {
+	loc := timestampPattern.FindStringIndex(line)
+	if loc == nil {
+		return time.Time{}, false
+	}
+	return ParseTime(line[loc[0]:loc[1]])
+}
+
+// TimestampPattern 返回行内时间戳匹配正则，供归一化等场景复用
+func TimestampPattern() *regexp.Regexp {
+	return timestampPattern
+}
+
+// unixTime 将秒或毫秒级Unix时间戳转换为时间
+func unixTime(f float64) time.Time {
+	// 大于 1e12 视为毫秒
+	if f > 1e12 {
+		f /= 1000
+	}
+	sec := int64(f)
+	// 浮点秒只保留到微秒，避免精度误差
+	usec := int64(math.Round((f - float64(sec)) * 1e6))
+	return time.Unix(sec, usec*int64(time.Microsecond))
+}
+
+// fillYear 为不带年份的时间（如RFC3164）补上年份，跨年时取上一年
+func fillYear(t time.Time) time.Time {
+	if t.Year() != 0 {
+		return t
+	}
+	now := time.Now()
+	t = t.AddDate(now.Year(), 0, 0)
+	if t.After(now.Add(24 * time.Hour)) {
+		t = t.AddDate(-1, 0, 0)
+	}
+	return t
+}
@@ -0,0 +1,274 @@
+// Package redact 对日志文本中的密钥、口令和个人信息做脱敏
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Rule 脱敏规则，Pattern 和 Keys 二选一
+//   - Pattern: 正则表达式，默认替换整个匹配；包含命名分组 (?P<secret>...) 时只替换该分组
+//   - Keys: 键名列表，替换 key=value、key: value、"key": "value" 等写法中的值（不区分大小写）
+//   - End: 设置后为块规则，从 Pattern 的匹配处一直替换到 End 的匹配结束，可以跨行；没有结束标记时替换到文本末尾
+type Rule struct {
+	Name        string   `json:"name"`
+	Pattern     string   `json:"pattern,omitempty"`
+	Keys        []string `json:"keys,omitempty"`
+	End         string   `json:"end,omitempty"`
+	Replacement string   `json:"replacement,omitempty"` // 默认为 [REDACTED:规则名]
+}
+
+// secretGroup 正则中需要替换的命名分组
+const secretGroup = "secret"
+
+// keyValueTemplate 键值对规则的正则，%s 为键名
+// 值到空白、引号、逗号、分号、& 或 } 为止
+const keyValueTemplate = `(?i)["']?\b(?:%s)["']?\s*[:=]\s*["']?(?P<secret>[^\s"',;&}]+)`
+
+// BuiltinRules 内置规则：常见令牌格式、口令类键名、邮箱和IPv4地址
+func BuiltinRules() []Rule {
+	return []Rule{
+		{Name: "private-key", Pattern: `-----BEGIN [A-Z ]*PRIVATE KEY-----`, End: `-----END [A-Z ]*PRIVATE KEY-----`},
+		{Name: "jwt", Pattern: `\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`},
+		{Name: "bearer", Pattern: `(?i)\bbearer\s+(?P<secret>[A-Za-z0-9._~+/=-]{16,})`},
+		{Name: "basic-auth", Pattern: `(?i)\bbasic\s+(?P<secret>[A-Za-z0-9+/]{12,}={0,2})`},
+		{Name: "url-password", Pattern: `\b[a-zA-Z][a-zA-Z0-9+.-]*://[^\s:/@]+:(?P<secret>[^\s/@]+)@`},
+		{Name: "aws-access-key", Pattern: `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`},
+		{Name: "github-token", Pattern: `\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{40,})\b`},
+		{Name: "slack-token", Pattern: `\bxox[abposr]-[A-Za-z0-9-]{10,}`},
+		{Name: "google-api-key", Pattern: `\bAIza[0-9A-Za-z_-]{35}\b`},
+		{Name: "stripe-key", Pattern: `\b[sr]k_(?:live|test)_[0-9A-Za-z]{16,}\b`},
+		{Name: "secret", Keys: []string{
+			"password", "passwd", "pwd", "passphrase", "secret", "client_secret", "secret_key",
+			"token", "access_token", "refresh_token", "auth_token", "api_key", "apikey", "access_key",
+			"private_key", "credential", "credentials", "session_id", "cookie",
+		}},
+		{Name: "email", Pattern: `\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`},
+		{Name: "ipv4", Pattern: `\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`},
+	}
+}
+
+// LoadRules 从JSON文件读取规则列表
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取脱敏规则失败: %w", err)
+	}
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("解析脱敏规则失败: %w", err)
+	}
+	return rules, nil
+}
+
+// compiledRule 编译后的规则
+type compiledRule struct {
+	re          *regexp.Regexp
+	group       int            // 需要替换的分组，0为整个匹配
+	end         *regexp.Regexp // 块规则的结束标记，普通规则为nil
+	replacement string
+}
+
+// Redactor 按顺序应用脱敏规则
+type Redactor struct {
+	rules []compiledRule
+}
+
+// New 编译规则
+func New(rules []Rule) (*Redactor, error) {
+	r := &Redactor{}
+	for _, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("脱敏规则缺少名称")
+		}
+
+		pattern := rule.Pattern
+		if len(rule.Keys) > 0 {
+			if pattern != "" {
+				return nil, fmt.Errorf("脱敏规则 %s 不能同时设置 pattern 和 keys", rule.Name)
+			}
+			keys := make([]string, len(rule.Keys))
+			for i, key := range rule.Keys {
+				keys[i] = regexp.QuoteMeta(key)
+			}
+			pattern = fmt.Sprintf(keyValueTemplate, strings.Join(keys, "|"))
+		}
+		if pattern == "" {
+			return nil, fmt.Errorf("脱敏规则 %s 缺少 pattern 或 keys", rule.Name)
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("脱敏规则 %s 的正则无效: %w", rule.Name, err)
+		}
+		replacement := rule.Replacement
+		if replacement == "" {
+			replacement = "[REDACTED:" + rule.Name + "]"
+		}
+		group := re.SubexpIndex(secretGroup)
+		if group < 0 {
+			group = 0
+		}
+		compiled := compiledRule{re: re, group: group, replacement: replacement}
+
+		if rule.End != "" {
+			if len(rule.Keys) > 0 {
+				return nil, fmt.Errorf("脱敏规则 %s 不能同时设置 keys 和 end", rule.Name)
+			}
+			end, err := regexp.Compile(rule.End)
+			if err != nil {
+				return nil, fmt.Errorf("脱敏规则 %s 的结束正则无效: %w", rule.Name, err)
+			}
+			// 空匹配的标记会让逐行状态无法前进
+			if re.MatchString("") || end.MatchString("") {
+				return nil, fmt.Errorf("脱敏规则 %s 的开始和结束标记不能匹配空字符串", rule.Name)
+			}
+			compiled.group = 0
+			compiled.end = end
+		}
+		r.rules = append(r.rules, compiled)
+	}
+	return r, nil
+}
+
+// Redact 返回脱敏后的文本，没有匹配时返回原字符串
+func (r *Redactor) Redact(text string) string {
+	for _, rule := range r.rules {
+		text = rule.apply(text)
+	}
+	return text
+}
+
+// Spans 返回原文中会被替换的字节区间 [start, end)，每条规则都在原文上匹配，区间可能重叠
+// 用于无法替换文本、只能遮盖原位置的场景（如十六进制视图）
+func (r *Redactor) Spans(text string) [][2]int {
+	var spans [][2]int
+	for _, rule := range r.rules {
+		if rule.end != nil {
+			spans = append(spans, rule.blockSpans(text)...)
+			continue
+		}
+		for _, m := range rule.re.FindAllStringSubmatchIndex(text, -1) {
+			start, end := m[2*rule.group], m[2*rule.group+1]
+			if start >= 0 && end > start {
+				spans = append(spans, [2]int{start, end})
+			}
+		}
+	}
+	return spans
+}
+
+// apply 替换所有匹配（或匹配中的指定分组）
+func (c compiledRule) apply(text string) string {
+	var matches [][]int
+	if c.end != nil {
+		for _, span := range c.blockSpans(text) {
+			matches = append(matches, span[:])
+		}
+	} else {
+		matches = c.re.FindAllStringSubmatchIndex(text, -1)
+	}
+	if len(matches) == 0 {
+		return text
+	}
+
+	var out strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[2*c.group], m[2*c.group+1]
+		if start < 0 {
+			continue
+		}
+		out.WriteString(text[last:start])
+		out.WriteString(c.replacement)
+		last = end
+	}
+	out.WriteString(text[last:])
+	return out.String()
+}
+
+// blockSpans 块规则在文本中的区间，从开始标记到结束标记（没有时到文本末尾）
+func (c compiledRule) blockSpans(text string) [][2]int {
+	var spans [][2]int
+	for pos := 0; pos < len(text); {
+		begin := c.re.FindStringIndex(text[pos:])
+		if begin == nil {
+			break
+		}
+		start, end := pos+begin[0], len(text)
+		if m := c.end.FindStringIndex(text[pos+begin[1]:]); m != nil {
+			end = pos + begin[1] + m[1]
+		}
+		spans = append(spans, [2]int{start, end})
+		pos = end
+	}
+	return spans
+}
+
+// Stream 逐行脱敏连续的文本（文件、多行事件），记录块规则跨行的状态
+// 块内的行整行替换；nil Stream 原样返回每一行
+type Stream struct {
+	r    *Redactor
+	open *compiledRule // 尚未遇到结束标记的块规则
+}
+
+// NewStream 创建逐行脱敏器，每个文件或事件使用单独的实例
+func (r *Redactor) NewStream() *Stream {
+	return &Stream{r: r}
+}
+
+// Redact 脱敏下一行
+func (s *Stream) Redact(line string) string {
+	if s == nil {
+		return line
+	}
+	return s.r.Redact(s.Blocks(line))
+}
+
+// Blocks 只替换下一行中属于块的部分并更新状态，不应用其他规则
+// 跳过不需要输出的行时也要调用，保证之后的行状态正确
+func (s *Stream) Blocks(line string) string {
+	if s == nil {
+		return line
+	}
+	var out strings.Builder
+	rest := line
+	for {
+		if s.open != nil {
+			out.WriteString(s.open.replacement)
+			m := s.open.end.FindStringIndex(rest)
+			if m == nil {
+				return out.String()
+			}
+			rest = rest[m[1]:]
+			s.open = nil
+			continue
+		}
+
+		// 找最先开始的块
+		var next *compiledRule
+		var begin []int
+		for i := range s.r.rules {
+			rule := &s.r.rules[i]
+			if rule.end == nil {
+				continue
+			}
+			if m := rule.re.FindStringIndex(rest); m != nil && (begin == nil || m[0] < begin[0]) {
+				next, begin = rule, m
+			}
+		}
+		if next == nil {
+			if out.Len() == 0 {
+				return line
+			}
+			out.WriteString(rest)
+			return out.String()
+		}
+		out.WriteString(rest[:begin[0]])
+		rest = rest[begin[1]:]
+		s.open = next
+	}
+}
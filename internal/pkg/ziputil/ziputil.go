@@ -4,17 +4,20 @@ import (
 	"archive/zip"
 	"fmt"
 	"io"
+	"logview-goversion/internal/pkg/fileutil"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
 // ZipUtil ZIP工具
-type ZipUtil struct{}
+type ZipUtil struct {
+	storage *fileutil.Storage // 解压的文件通过存储层写入
+}
 
 // NewZipUtil 创建ZIP工具
-func NewZipUtil() *ZipUtil {
-	return &ZipUtil{}
+func NewZipUtil(storage *fileutil.Storage) *ZipUtil {
+	return &ZipUtil{storage: storage}
 }
 
 // IsValid 检查是否为有效的ZIP文件
@@ -88,12 +91,15 @@ func (z *ZipUtil) extractFile(file *zip.File, destPath string) error {
 	}
 	defer src.Close()
 
-	dst, err := os.Create(destPath)
+	// 保留压缩包中记录的修改时间
+	dst, err := z.storage.Create(destPath, file.Modified)
 	if err != nil {
 		return err
 	}
-	defer dst.Close()
 
-	_, err = io.Copy(dst, src)
-	return err
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
 }
\ No newline at end of file
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// AnalysisRepository 分析结果缓存数据访问层
+// 日志包解压后内容不再变化，统计、报告等耗时的分析结果以JSON形式按 (log_id, kind, cache_key) 缓存
+type AnalysisRepository struct {
+	db *sql.DB
+}
+
+// NewAnalysisRepository 创建分析结果缓存数据访问层
+func NewAnalysisRepository(db *sql.DB) (*AnalysisRepository, error) {
+	repo := &AnalysisRepository{db: db}
+	if err := repo.initializeDB(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// initializeDB 创建缓存表
+func (r *AnalysisRepository) initializeDB() error {
+	_, err := r.db.Exec(`
+	CREATE TABLE IF NOT EXISTS analysis_cache (
+		log_id TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		cache_key TEXT NOT NULL DEFAULT '',
+		data TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (log_id, kind, cache_key)
+	);`)
+	if err != nil {
+		return fmt.Errorf("创建分析缓存表失败: %w", err)
+	}
+	return nil
+}
+
+// Get 读取缓存的分析结果，不存在时返回nil
+func (r *AnalysisRepository) Get(logID, kind, key string) ([]byte, error) {
+	var data string
+	err := r.db.QueryRow(
+		"SELECT data FROM analysis_cache WHERE log_id = ? AND kind = ? AND cache_key = ?",
+		logID, kind, key,
+	).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(data), nil
+}
+
+// Put 写入分析结果（已存在时覆盖）
+func (r *AnalysisRepository) Put(logID, kind, key string, data []byte) error {
+	_, err := r.db.Exec(
+		"INSERT OR REPLACE INTO analysis_cache (log_id, kind, cache_key, data, created_at) VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)",
+		logID, kind, key, string(data),
+	)
+	return err
+}
+
+// DeleteByLogID 删除指定日志的所有缓存
+func (r *AnalysisRepository) DeleteByLogID(logID string) error {
+	_, err := r.db.Exec("DELETE FROM analysis_cache WHERE log_id = ?", logID)
+	return err
+}
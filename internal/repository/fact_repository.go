@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"logview-goversion/internal/models"
+	"time"
+)
+
+// FactRepository 日志元数据数据访问层
+type FactRepository struct {
+	db *sql.DB
+}
+
+// NewFactRepository 创建元数据数据访问层
+func NewFactRepository(db *sql.DB) (*FactRepository, error) {
+	repo := &FactRepository{db: db}
+	if err := repo.initializeDB(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// initializeDB 创建元数据表，每个日志的每个字段只保存一个值
+func (r *FactRepository) initializeDB() error {
+	_, err := r.db.Exec(`
+	CREATE TABLE IF NOT EXISTS facts (
+		log_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		value TEXT NOT NULL,
+		file_path TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (log_id, name)
+	);
+	CREATE INDEX IF NOT EXISTS idx_facts_name_value ON facts(name, value);`)
+	if err != nil {
+		return fmt.Errorf("创建元数据表失败: %w", err)
+	}
+	return nil
+}
+
+// ReplaceFacts 用新提取的元数据替换日志的旧元数据
+func (r *FactRepository) ReplaceFacts(logID string, facts []models.Fact) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM facts WHERE log_id = ?", logID); err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("INSERT INTO facts (log_id, name, value, file_path) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, f := range facts {
+		if _, err := stmt.Exec(logID, f.Name, f.Value, f.FilePath); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListFacts 获取日志的元数据，按字段名排序
+func (r *FactRepository) ListFacts(logID string) ([]models.Fact, error) {
+	rows, err := r.db.Query(
+		"SELECT name, value, file_path, datetime(created_at, 'localtime') FROM facts WHERE log_id = ? ORDER BY name", logID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	facts := []models.Fact{}
+	for rows.Next() {
+		var f models.Fact
+		var createdAt string
+		if err := rows.Scan(&f.Name, &f.Value, &f.FilePath, &createdAt); err != nil {
+			return nil, err
+		}
+		f.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+		facts = append(facts, f)
+	}
+	return facts, rows.Err()
+}
+
+// AllValues 获取所有日志的元数据，log_id -> 字段名 -> 值
+func (r *FactRepository) AllValues() (map[string]map[string]string, error) {
+	rows, err := r.db.Query("SELECT log_id, name, value FROM facts")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make(map[string]map[string]string)
+	for rows.Next() {
+		var logID, name, value string
+		if err := rows.Scan(&logID, &name, &value); err != nil {
+			return nil, err
+		}
+		if values[logID] == nil {
+			values[logID] = make(map[string]string)
+		}
+		values[logID][name] = value
+	}
+	return values, rows.Err()
+}
+
+// DeleteByLogID 删除日志的所有元数据
+func (r *FactRepository) DeleteByLogID(logID string) error {
+	_, err := r.db.Exec("DELETE FROM facts WHERE log_id = ?", logID)
+	return err
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"logview-goversion/internal/models"
+)
+
+// FileIndexRepository 文件索引数据访问层
+type FileIndexRepository struct {
+	db *sql.DB
+}
+
+// NewFileIndexRepository 创建文件索引数据访问层
+func NewFileIndexRepository(db *sql.DB) (*FileIndexRepository, error) {
+	repo := &FileIndexRepository{db: db}
+	if err := repo.initializeDB(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// initializeDB 创建文件索引表
+func (r *FileIndexRepository) initializeDB() error {
+	_, err := r.db.Exec(`
+	CREATE TABLE IF NOT EXISTS file_index (
+		log_id TEXT NOT NULL,
+		path TEXT NOT NULL,
+		content_type TEXT NOT NULL,
+		encoding TEXT NOT NULL,
+		compression TEXT NOT NULL,
+		lines INTEGER NOT NULL,
+		errors INTEGER NOT NULL,
+		warnings INTEGER NOT NULL,
+		hash TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (log_id, path)
+	);`)
+	if err != nil {
+		return fmt.Errorf("创建文件索引表失败: %w", err)
+	}
+
+	var count int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('file_index') WHERE name = 'hash'").Scan(&count); err != nil {
+		return fmt.Errorf("检查文件索引表失败: %w", err)
+	}
+	if count == 0 {
+		if _, err := r.db.Exec("ALTER TABLE file_index ADD COLUMN hash TEXT NOT NULL DEFAULT ''"); err != nil {
+			return fmt.Errorf("升级文件索引表失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReplaceIndex 用新的索引替换日志的旧索引
+func (r *FileIndexRepository) ReplaceIndex(logID string, entries []models.FileIndex) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM file_index WHERE log_id = ?", logID); err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(`INSERT INTO file_index (log_id, path, content_type, encoding, compression, lines, errors, warnings)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range entries {
+		if _, err := stmt.Exec(logID, e.Path, e.ContentType, e.Encoding, e.Compression, e.Lines, e.Errors, e.Warnings); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetIndex 获取日志的文件索引，按相对路径索引
+func (r *FileIndexRepository) GetIndex(logID string) (map[string]models.FileIndex, error) {
+	rows, err := r.db.Query(
+		"SELECT path, content_type, encoding, compression, lines, errors, warnings FROM file_index WHERE log_id = ?", logID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	index := make(map[string]models.FileIndex)
+	for rows.Next() {
+		var e models.FileIndex
+		if err := rows.Scan(&e.Path, &e.ContentType, &e.Encoding, &e.Compression, &e.Lines, &e.Errors, &e.Warnings); err != nil {
+			return nil, err
+		}
+		index[e.Path] = e
+	}
+	return index, rows.Err()
+}
+
+// GetHash 获取缓存的文件内容哈希，没有索引或尚未计算时返回空字符串
+func (r *FileIndexRepository) GetHash(logID, path string) (string, error) {
+	var hash string
+	err := r.db.QueryRow("SELECT hash FROM file_index WHERE log_id = ? AND path = ?", logID, path).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return hash, err
+}
+
+// SetHash 缓存文件内容哈希，文件没有索引时不保存
+func (r *FileIndexRepository) SetHash(logID, path, hash string) error {
+	_, err := r.db.Exec("UPDATE file_index SET hash = ? WHERE log_id = ? AND path = ?", hash, logID, path)
+	return err
+}
+
+// DeleteByLogID 删除日志的文件索引
+func (r *FileIndexRepository) DeleteByLogID(logID string) error {
+	_, err := r.db.Exec("DELETE FROM file_index WHERE log_id = ?", logID)
+	return err
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"logview-goversion/internal/models"
+	"strings"
+	"time"
+)
+
+// FileTreeRepository 文件树数据访问层，导入时计算的文件树按节点逐行保存
+type FileTreeRepository struct {
+	db *sql.DB
+}
+
+// NewFileTreeRepository 创建文件树数据访问层
+func NewFileTreeRepository(db *sql.DB) (*FileTreeRepository, error) {
+	repo := &FileTreeRepository{db: db}
+	if err := repo.initializeDB(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// initializeDB 创建文件树表，根目录的 path 为空、parent 为 NULL
+func (r *FileTreeRepository) initializeDB() error {
+	_, err := r.db.Exec(`
+	CREATE TABLE IF NOT EXISTS file_tree (
+		log_id TEXT NOT NULL,
+		path TEXT NOT NULL,
+		parent TEXT,
+		name TEXT NOT NULL,
+		type TEXT NOT NULL,
+		size INTEGER NOT NULL,
+		binary INTEGER NOT NULL,
+		mod_time INTEGER NOT NULL,
+		child_count INTEGER NOT NULL,
+		file_count INTEGER NOT NULL,
+		PRIMARY KEY (log_id, path)
+	);`)
+	if err != nil {
+		return fmt.Errorf("创建文件树表失败: %w", err)
+	}
+
+	// 加入 parent 之前保存的文件树没有父目录，清空后在下次访问时重新计算
+	var count int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('file_tree') WHERE name = 'parent'").Scan(&count); err != nil {
+		return fmt.Errorf("检查文件树表失败: %w", err)
+	}
+	if count == 0 {
+		if _, err := r.db.Exec("ALTER TABLE file_tree ADD COLUMN parent TEXT"); err != nil {
+			return fmt.Errorf("升级文件树表失败: %w", err)
+		}
+		if _, err := r.db.Exec("DELETE FROM file_tree"); err != nil {
+			return fmt.Errorf("升级文件树表失败: %w", err)
+		}
+	}
+
+	// 按目录分页列出子项（目录优先，然后按名称）
+	_, err = r.db.Exec(`CREATE INDEX IF NOT EXISTS idx_file_tree_parent
+		ON file_tree (log_id, parent, type != 'directory', name)`)
+	if err != nil {
+		return fmt.Errorf("创建文件树索引失败: %w", err)
+	}
+	return nil
+}
+
+// ReplaceTree 用新的节点替换日志的旧文件树
+func (r *FileTreeRepository) ReplaceTree(logID string, nodes []models.FileNode) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM file_tree WHERE log_id = ?", logID); err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(`INSERT INTO file_tree (log_id, path, parent, name, type, size, binary, mod_time, child_count, file_count)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, n := range nodes {
+		if _, err := stmt.Exec(logID, n.Path, treeParent(n.Path), n.Name, n.Type, n.Size, n.Binary, n.ModTime.UnixNano(), n.ChildCount, n.FileCount); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// treeParent 节点的父目录路径，根目录返回 NULL
+func treeParent(p string) interface{} {
+	if p == "" {
+		return nil
+	}
+	if i := strings.LastIndex(p, "/"); i >= 0 {
+		return p[:i]
+	}
+	return ""
+}
+
+// ListChildren 按目录优先、名称排序分页获取目录的直接子项，afterDir/afterName 为上一页的最后一项（afterName 为空时从头开始）
+func (r *FileTreeRepository) ListChildren(logID, parent string, afterDir bool, afterName string, limit int) ([]models.FileNode, error) {
+	query := `SELECT path, name, type, size, binary, mod_time, child_count, file_count
+		FROM file_tree WHERE log_id = ? AND parent = ?`
+	args := []interface{}{logID, parent}
+	if afterName != "" {
+		query += ` AND (type != 'directory', name) > (?, ?)`
+		args = append(args, !afterDir, afterName)
+	}
+	query += ` ORDER BY type != 'directory', name LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nodes []models.FileNode
+	for rows.Next() {
+		n, err := scanTreeNode(rows)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, rows.Err()
+}
+
+// FindNodes 按路径顺序遍历目录下（递归，不含目录本身）名称包含 query 的节点，query 为空时遍历所有节点，不区分大小写
+func (r *FileTreeRepository) FindNodes(logID, dir, query string, fn func(models.FileNode) error) error {
+	sqlQuery := `SELECT path, name, type, size, binary, mod_time, child_count, file_count
+		FROM file_tree WHERE log_id = ? AND path != ''`
+	args := []interface{}{logID}
+	if dir != "" {
+		// 以 "dir/" 开头的路径都在 ["dir/", "dir0") 之间（'0' 紧跟在 '/' 之后），可以使用主键索引
+		sqlQuery += ` AND path >= ? AND path < ?`
+		args = append(args, dir+"/", dir+"0")
+	}
+	if query != "" {
+		sqlQuery += ` AND instr(lower(name), ?) > 0`
+		args = append(args, strings.ToLower(query))
+	}
+	sqlQuery += ` ORDER BY path`
+
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		n, err := scanTreeNode(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(n); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// GetTree 获取日志的所有节点，按路径排序；没有保存过文件树时返回空
+func (r *FileTreeRepository) GetTree(logID string) ([]models.FileNode, error) {
+	rows, err := r.db.Query(`SELECT path, name, type, size, binary, mod_time, child_count, file_count
+		FROM file_tree WHERE log_id = ? ORDER BY path`, logID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nodes []models.FileNode
+	for rows.Next() {
+		n, err := scanTreeNode(rows)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, rows.Err()
+}
+
+// GetNodes 获取指定路径的节点，按相对路径索引
+func (r *FileTreeRepository) GetNodes(logID string, paths []string) (map[string]models.FileNode, error) {
+	nodes := make(map[string]models.FileNode)
+	if len(paths) == 0 {
+		return nodes, nil
+	}
+
+	args := make([]interface{}, 0, len(paths)+1)
+	args = append(args, logID)
+	for _, p := range paths {
+		args = append(args, p)
+	}
+	rows, err := r.db.Query(`SELECT path, name, type, size, binary, mod_time, child_count, file_count
+		FROM file_tree WHERE log_id = ? AND path IN (?`+strings.Repeat(", ?", len(paths)-1)+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		n, err := scanTreeNode(rows)
+		if err != nil {
+			return nil, err
+		}
+		nodes[n.Path] = n
+	}
+	return nodes, rows.Err()
+}
+
+// DeleteByLogID 删除日志的文件树
+func (r *FileTreeRepository) DeleteByLogID(logID string) error {
+	_, err := r.db.Exec("DELETE FROM file_tree WHERE log_id = ?", logID)
+	return err
+}
+
+// scanTreeNode 扫描一行文件树节点
+func scanTreeNode(row rowScanner) (models.FileNode, error) {
+	var n models.FileNode
+	var modTime int64
+	if err := row.Scan(&n.Path, &n.Name, &n.Type, &n.Size, &n.Binary, &modTime, &n.ChildCount, &n.FileCount); err != nil {
+		return n, err
+	}
+	n.ModTime = time.Unix(0, modTime)
+	return n, nil
+}
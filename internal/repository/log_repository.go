@@ -46,16 +46,21 @@ func NewLogRepository(dbPath string) (*LogRepository, error) {
 	return repo, nil
 }
 
+// DB 返回底层数据库连接，供其他数据访问层共享
+func (r *LogRepository) DB() *sql.DB {
+	return r.db
+}
+
 // Close 关闭数据库连接
 func (r *LogRepository) Close() error {
 	return r.db.Close()
 }
 
-// Create 创建日志
+// Create 创建日志，导入处理状态为等待处理
 func (r *LogRepository) Create(logID, filePath, extractPath string) error {
 	_, err := r.db.Exec(
-		"INSERT OR REPLACE INTO logs (log_id, file_path, extract_path) VALUES (?, ?, ?)",
-		logID, filePath, extractPath)
+		"INSERT OR REPLACE INTO logs (log_id, file_path, extract_path, status) VALUES (?, ?, ?, ?)",
+		logID, filePath, extractPath, models.ImportPending)
 	return err
 }
 
@@ -64,7 +69,7 @@ func (r *LogRepository) GetAll() ([]models.Log, error) {
 	rows, err := r.db.Query(`
 		SELECT id, log_id, file_path, extract_path,
 			   datetime(download_time, 'localtime') as download_time,
-			   tags, notes
+			   tags, notes, IFNULL(size, 0), IFNULL(disk_size, 0), status, status_error
 		FROM logs ORDER BY download_time DESC`)
 	if err != nil {
 		return nil, err
@@ -75,7 +80,7 @@ func (r *LogRepository) GetAll() ([]models.Log, error) {
 	for rows.Next() {
 		var log models.Log
 		var downloadTimeStr string
-		err := rows.Scan(&log.ID, &log.LogID, &log.FilePath, &log.ExtractPath, &downloadTimeStr, &log.Tags, &log.Notes)
+		err := rows.Scan(&log.ID, &log.LogID, &log.FilePath, &log.ExtractPath, &downloadTimeStr, &log.Tags, &log.Notes, &log.Size, &log.DiskSize, &log.Status, &log.StatusError)
 		if err != nil {
 			return nil, err
 		}
@@ -94,8 +99,8 @@ func (r *LogRepository) GetByID(logID string) (*models.Log, error) {
 	var log models.Log
 	var downloadTimeStr string
 	err := r.db.QueryRow(
-		"SELECT id, log_id, file_path, extract_path, datetime(download_time, 'localtime') as download_time, tags, notes FROM logs WHERE log_id = ?",
-		logID).Scan(&log.ID, &log.LogID, &log.FilePath, &log.ExtractPath, &downloadTimeStr, &log.Tags, &log.Notes)
+		"SELECT id, log_id, file_path, extract_path, datetime(download_time, 'localtime') as download_time, tags, notes, IFNULL(size, 0), IFNULL(disk_size, 0), status, status_error FROM logs WHERE log_id = ?",
+		logID).Scan(&log.ID, &log.LogID, &log.FilePath, &log.ExtractPath, &downloadTimeStr, &log.Tags, &log.Notes, &log.Size, &log.DiskSize, &log.Status, &log.StatusError)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -142,6 +147,63 @@ func (r *LogRepository) UpdateTagsAndNotes(logID, tags, notes string) error {
 	return err
 }
 
+// UpdateSize 更新日志解压后的总大小
+func (r *LogRepository) UpdateSize(logID string, size, diskSize int64) error {
+	_, err := r.db.Exec("UPDATE logs SET size = ?, disk_size = ? WHERE log_id = ?", size, diskSize, logID)
+	return err
+}
+
+// UpdateStatus 更新导入处理状态，errMsg 为处理失败的原因
+func (r *LogRepository) UpdateStatus(logID, status, errMsg string) error {
+	_, err := r.db.Exec("UPDATE logs SET status = ?, status_error = ? WHERE log_id = ?", status, errMsg, logID)
+	return err
+}
+
+// GetIDsByStatus 获取处于指定导入处理状态的日志ID，按下载时间排序
+func (r *LogRepository) GetIDsByStatus(statuses ...string) ([]string, error) {
+	if len(statuses) == 0 {
+		return nil, nil
+	}
+	args := make([]interface{}, len(statuses))
+	for i, status := range statuses {
+		args[i] = status
+	}
+	rows, err := r.db.Query("SELECT log_id FROM logs WHERE status IN (?"+strings.Repeat(", ?", len(statuses)-1)+") ORDER BY download_time", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetIDsWithoutSize 获取还没有记录大小的日志ID（大小统计加入之前导入的日志）
+func (r *LogRepository) GetIDsWithoutSize() ([]string, error) {
+	rows, err := r.db.Query("SELECT log_id FROM logs WHERE size IS NULL OR disk_size IS NULL")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 // initializeDB 初始化数据库（创建表和索引）
 func (r *LogRepository) initializeDB(db *sql.DB) error {
 	// 创建主表
@@ -172,7 +234,11 @@ func (r *LogRepository) createTables(db *sql.DB) error {
 		extract_path TEXT NOT NULL,
 		download_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 		tags TEXT DEFAULT '',
-		notes TEXT DEFAULT ''
+		notes TEXT DEFAULT '',
+		size INTEGER,
+		disk_size INTEGER,
+		status TEXT NOT NULL DEFAULT 'ready',
+		status_error TEXT NOT NULL DEFAULT ''
 	);`
 
 	_, err := db.Exec(createTableSQL)
@@ -213,6 +279,24 @@ func (r *LogRepository) runMigrations(db *sql.DB) error {
 			"ALTER TABLE logs ADD COLUMN notes TEXT DEFAULT ''",
 			"SELECT COUNT(*) FROM pragma_table_info('logs') WHERE name = 'notes'",
 		},
+		{
+			"ALTER TABLE logs ADD COLUMN size INTEGER",
+			"SELECT COUNT(*) FROM pragma_table_info('logs') WHERE name = 'size'",
+		},
+		{
+			// 为空的由服务启动时补记
+			"ALTER TABLE logs ADD COLUMN disk_size INTEGER",
+			"SELECT COUNT(*) FROM pragma_table_info('logs') WHERE name = 'disk_size'",
+		},
+		{
+			// 导入处理状态加入之前的日志都已处理完成
+			"ALTER TABLE logs ADD COLUMN status TEXT NOT NULL DEFAULT 'ready'",
+			"SELECT COUNT(*) FROM pragma_table_info('logs') WHERE name = 'status'",
+		},
+		{
+			"ALTER TABLE logs ADD COLUMN status_error TEXT NOT NULL DEFAULT ''",
+			"SELECT COUNT(*) FROM pragma_table_info('logs') WHERE name = 'status_error'",
+		},
 	}
 
 	for _, m := range migrations {
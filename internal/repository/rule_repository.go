@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"logview-goversion/internal/models"
+	"time"
+)
+
+// RuleRepository 已知问题规则和匹配结果数据访问层
+type RuleRepository struct {
+	db *sql.DB
+}
+
+// NewRuleRepository 创建规则数据访问层
+func NewRuleRepository(db *sql.DB) (*RuleRepository, error) {
+	repo := &RuleRepository{db: db}
+	if err := repo.initializeDB(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// initializeDB 创建规则表和匹配结果表
+func (r *RuleRepository) initializeDB() error {
+	_, err := r.db.Exec(`
+	CREATE TABLE IF NOT EXISTS rules (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		file_glob TEXT NOT NULL DEFAULT '',
+		pattern TEXT NOT NULL DEFAULT '',
+		conditions TEXT NOT NULL DEFAULT '[]',
+		match_mode TEXT NOT NULL DEFAULT 'all',
+		severity TEXT NOT NULL DEFAULT 'medium',
+		remediation TEXT NOT NULL DEFAULT '',
+		enabled INTEGER NOT NULL DEFAULT 1,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);`)
+	if err != nil {
+		return fmt.Errorf("创建规则表失败: %w", err)
+	}
+
+	_, err = r.db.Exec(`
+	CREATE TABLE IF NOT EXISTS findings (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		log_id TEXT NOT NULL,
+		rule_id INTEGER NOT NULL,
+		file_path TEXT NOT NULL,
+		line INTEGER NOT NULL,
+		content TEXT NOT NULL,
+		match_count INTEGER NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_findings_log_id ON findings(log_id);
+	CREATE INDEX IF NOT EXISTS idx_findings_rule_id ON findings(rule_id);`)
+	if err != nil {
+		return fmt.Errorf("创建规则匹配结果表失败: %w", err)
+	}
+	return nil
+}
+
+// ruleColumns 规则查询列，时间按本地时区输出
+const ruleColumns = "id, name, file_glob, pattern, conditions, match_mode, severity, remediation, enabled, datetime(created_at, 'localtime'), datetime(updated_at, 'localtime')"
+
+// CreateRule 创建规则，返回新规则ID
+func (r *RuleRepository) CreateRule(rule *models.Rule) (int64, error) {
+	conditions, err := json.Marshal(rule.Conditions)
+	if err != nil {
+		return 0, err
+	}
+	result, err := r.db.Exec(
+		"INSERT INTO rules (name, file_glob, pattern, conditions, match_mode, severity, remediation, enabled) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		rule.Name, rule.FileGlob, rule.Pattern, string(conditions), rule.Match, rule.Severity, rule.Remediation, rule.Enabled,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// ListRules 获取所有规则，enabledOnly 为 true 时只返回启用的规则
+func (r *RuleRepository) ListRules(enabledOnly bool) ([]models.Rule, error) {
+	query := "SELECT " + ruleColumns + " FROM rules"
+	if enabledOnly {
+		query += " WHERE enabled = 1"
+	}
+	query += " ORDER BY id"
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.Rule{}
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, rows.Err()
+}
+
+// GetRule 获取规则，不存在时返回nil
+func (r *RuleRepository) GetRule(id int64) (*models.Rule, error) {
+	rule, err := scanRule(r.db.QueryRow("SELECT "+ruleColumns+" FROM rules WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateRule 更新规则，返回规则是否存在
+func (r *RuleRepository) UpdateRule(rule *models.Rule) (bool, error) {
+	conditions, err := json.Marshal(rule.Conditions)
+	if err != nil {
+		return false, err
+	}
+	result, err := r.db.Exec(
+		"UPDATE rules SET name = ?, file_glob = ?, pattern = ?, conditions = ?, match_mode = ?, severity = ?, remediation = ?, enabled = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+		rule.Name, rule.FileGlob, rule.Pattern, string(conditions), rule.Match, rule.Severity, rule.Remediation, rule.Enabled, rule.ID,
+	)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// DeleteRule 删除规则及其匹配结果
+func (r *RuleRepository) DeleteRule(id int64) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM rules WHERE id = ?", id)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec("DELETE FROM findings WHERE rule_id = ?", id); err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, tx.Commit()
+}
+
+// ReplaceFindings 用新的匹配结果替换日志的旧结果
+func (r *RuleRepository) ReplaceFindings(logID string, findings []models.Finding) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM findings WHERE log_id = ?", logID); err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("INSERT INTO findings (log_id, rule_id, file_path, line, content, match_count) VALUES (?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, f := range findings {
+		if _, err := stmt.Exec(logID, f.RuleID, f.FilePath, f.Line, f.Content, f.Count); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListFindings 获取日志的匹配结果（附带规则的名称、严重程度和处理建议），按严重程度排序
+func (r *RuleRepository) ListFindings(logID string) ([]models.Finding, error) {
+	rows, err := r.db.Query(`
+		SELECT f.id, f.log_id, f.rule_id, r.name, r.severity, r.remediation, f.file_path, f.line, f.content, f.match_count,
+			   datetime(f.created_at, 'localtime')
+		FROM findings f JOIN rules r ON r.id = f.rule_id
+		WHERE f.log_id = ?
+		ORDER BY CASE r.severity
+			WHEN 'critical' THEN 0 WHEN 'high' THEN 1 WHEN 'medium' THEN 2 WHEN 'low' THEN 3 ELSE 4 END,
+			f.rule_id, f.file_path`, logID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	findings := []models.Finding{}
+	for rows.Next() {
+		var f models.Finding
+		var createdAt string
+		if err := rows.Scan(&f.ID, &f.LogID, &f.RuleID, &f.RuleName, &f.Severity, &f.Remediation,
+			&f.FilePath, &f.Line, &f.Content, &f.Count, &createdAt); err != nil {
+			return nil, err
+		}
+		f.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+		findings = append(findings, f)
+	}
+	return findings, rows.Err()
+}
+
+// DeleteFindingsByLogID 删除日志的所有匹配结果
+func (r *RuleRepository) DeleteFindingsByLogID(logID string) error {
+	_, err := r.db.Exec("DELETE FROM findings WHERE log_id = ?", logID)
+	return err
+}
+
+// scanRule 扫描一行规则
+func scanRule(row rowScanner) (*models.Rule, error) {
+	var rule models.Rule
+	var conditions, createdAt, updatedAt string
+	if err := row.Scan(&rule.ID, &rule.Name, &rule.FileGlob, &rule.Pattern, &conditions, &rule.Match,
+		&rule.Severity, &rule.Remediation, &rule.Enabled, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	rule.Conditions = []models.RuleCondition{}
+	if err := json.Unmarshal([]byte(conditions), &rule.Conditions); err != nil {
+		return nil, fmt.Errorf("规则 %d 的条件格式错误: %w", rule.ID, err)
+	}
+	rule.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+	rule.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+	return &rule, nil
+}
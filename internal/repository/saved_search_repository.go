@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"logview-goversion/internal/models"
+	"strings"
+	"time"
+)
+
+// SavedSearchRepository 保存的搜索数据访问层
+type SavedSearchRepository struct {
+	db *sql.DB
+}
+
+// NewSavedSearchRepository 创建保存的搜索数据访问层
+func NewSavedSearchRepository(db *sql.DB) (*SavedSearchRepository, error) {
+	repo := &SavedSearchRepository{db: db}
+	if err := repo.initializeDB(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// initializeDB 创建保存的搜索表
+func (r *SavedSearchRepository) initializeDB() error {
+	_, err := r.db.Exec(`
+	CREATE TABLE IF NOT EXISTS saved_searches (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		pattern TEXT NOT NULL,
+		levels TEXT NOT NULL DEFAULT '',
+		file_glob TEXT NOT NULL DEFAULT '',
+		owner TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_saved_searches_owner ON saved_searches(owner);`)
+	if err != nil {
+		return fmt.Errorf("创建保存的搜索表失败: %w", err)
+	}
+	return nil
+}
+
+// savedSearchColumns 查询列，时间按本地时区输出
+const savedSearchColumns = "id, name, pattern, levels, file_glob, owner, datetime(created_at, 'localtime'), datetime(updated_at, 'localtime')"
+
+// Create 创建保存的搜索，返回新记录ID
+func (r *SavedSearchRepository) Create(search *models.SavedSearch) (int64, error) {
+	result, err := r.db.Exec(
+		"INSERT INTO saved_searches (name, pattern, levels, file_glob, owner) VALUES (?, ?, ?, ?, ?)",
+		search.Name, search.Pattern, strings.Join(search.Levels, ","), search.FileGlob, search.Owner,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// List 获取保存的搜索，owner不为空时只返回该用户的
+func (r *SavedSearchRepository) List(owner string) ([]models.SavedSearch, error) {
+	query := "SELECT " + savedSearchColumns + " FROM saved_searches"
+	var args []interface{}
+	if owner != "" {
+		query += " WHERE owner = ?"
+		args = append(args, owner)
+	}
+	query += " ORDER BY name, id"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	searches := []models.SavedSearch{}
+	for rows.Next() {
+		search, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, err
+		}
+		searches = append(searches, *search)
+	}
+	return searches, rows.Err()
+}
+
+// GetByID 获取保存的搜索，不存在时返回nil
+func (r *SavedSearchRepository) GetByID(id int64) (*models.SavedSearch, error) {
+	row := r.db.QueryRow("SELECT "+savedSearchColumns+" FROM saved_searches WHERE id = ?", id)
+	search, err := scanSavedSearch(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return search, nil
+}
+
+// Update 更新保存的搜索，返回记录是否存在
+func (r *SavedSearchRepository) Update(search *models.SavedSearch) (bool, error) {
+	result, err := r.db.Exec(
+		"UPDATE saved_searches SET name = ?, pattern = ?, levels = ?, file_glob = ?, owner = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+		search.Name, search.Pattern, strings.Join(search.Levels, ","), search.FileGlob, search.Owner, search.ID,
+	)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// Delete 删除保存的搜索
+func (r *SavedSearchRepository) Delete(id int64) (bool, error) {
+	result, err := r.db.Exec("DELETE FROM saved_searches WHERE id = ?", id)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// rowScanner sql.Row 和 sql.Rows 共有的扫描方法
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSavedSearch 扫描一行保存的搜索
+func scanSavedSearch(row rowScanner) (*models.SavedSearch, error) {
+	var search models.SavedSearch
+	var levels, createdAt, updatedAt string
+	if err := row.Scan(&search.ID, &search.Name, &search.Pattern, &levels, &search.FileGlob, &search.Owner, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	search.Levels = []string{}
+	if levels != "" {
+		search.Levels = strings.Split(levels, ",")
+	}
+	search.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+	search.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+	return &search, nil
+}
@@ -0,0 +1,373 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"logview-goversion/internal/models"
+	"strings"
+)
+
+// lineIDBits 行号在 rowid 中占用的位数，rowid = file_id<<lineIDBits | line_no
+const lineIDBits = 32
+
+// maskedToken 脱敏后内容有变化的行在 masked 列中的值
+const maskedToken = "1"
+
+// 搜索摘要中高亮的起止标记，使用日志中不会出现的控制字符，由服务层转义正文后替换为 HTML 标签
+const (
+	SnippetMarkStart = "\x02"
+	SnippetMarkEnd   = "\x03"
+)
+
+// rulesFingerprintKey 建立索引时使用的脱敏规则指纹在 search_meta 表中的键
+const rulesFingerprintKey = "rules_fingerprint"
+
+// SearchRepository 全文索引数据访问层（SQLite FTS5）
+// 每行保存原始内容 content；脱敏后有变化的行另存脱敏后的内容 redacted 并在 masked 列中标记，
+// 不查看原始内容的搜索只在脱敏后的文本上匹配，命中数不会暴露被隐藏的值
+type SearchRepository struct {
+	db         *sql.DB
+	ftsEnabled bool
+	staleLogs  []string // 旧版索引表中已索引、升级后需要重建索引的日志
+}
+
+// NewSearchRepository 创建全文索引数据访问层
+func NewSearchRepository(db *sql.DB) (*SearchRepository, error) {
+	repo := &SearchRepository{db: db}
+	if err := repo.initializeDB(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// Enabled 当前构建是否支持FTS5
+func (r *SearchRepository) Enabled() bool {
+	return r.ftsEnabled
+}
+
+// StaleLogs 索引表升级时被清除、需要重建索引的日志
+func (r *SearchRepository) StaleLogs() []string {
+	return r.staleLogs
+}
+
+// initializeDB 创建索引表
+func (r *SearchRepository) initializeDB() error {
+	_, err := r.db.Exec(`
+	CREATE TABLE IF NOT EXISTS search_files (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		log_id TEXT NOT NULL,
+		file_path TEXT NOT NULL,
+		line_count INTEGER DEFAULT 0,
+		UNIQUE(log_id, file_path)
+	);`)
+	if err != nil {
+		return fmt.Errorf("创建索引文件表失败: %w", err)
+	}
+
+	if _, err := r.db.Exec("CREATE INDEX IF NOT EXISTS idx_search_files_log_id ON search_files(log_id)"); err != nil {
+		return fmt.Errorf("创建索引失败: %w", err)
+	}
+
+	_, err = r.db.Exec(`
+	CREATE TABLE IF NOT EXISTS search_meta (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	);`)
+	if err != nil {
+		return fmt.Errorf("创建索引元数据表失败: %w", err)
+	}
+
+	// 行号编码在 rowid 中，按日志删除时可以走 rowid 范围而不是全表扫描
+	_, err = r.db.Exec(`
+	CREATE VIRTUAL TABLE IF NOT EXISTS search_lines USING fts5(
+		content,
+		redacted,
+		masked,
+		tokenize = 'unicode61'
+	);`)
+	if err != nil {
+		// 未使用 sqlite_fts5 构建标签时 FTS5 模块不存在，搜索功能降级为不可用
+		if strings.Contains(err.Error(), "no such module") {
+			return nil
+		}
+		return fmt.Errorf("创建全文索引表失败: %w", err)
+	}
+	if err := r.upgradeLinesTable(); err != nil {
+		return fmt.Errorf("升级全文索引表失败: %w", err)
+	}
+
+	r.ftsEnabled = true
+	return nil
+}
+
+// upgradeLinesTable 旧版索引表只有 content 列，删除后重建，之前已索引的日志记录在 staleLogs 中
+func (r *SearchRepository) upgradeLinesTable() error {
+	rows, err := r.db.Query("SELECT * FROM search_lines LIMIT 0")
+	if err != nil {
+		return err
+	}
+	columns, err := rows.Columns()
+	rows.Close()
+	if err != nil {
+		return err
+	}
+	if len(columns) != 1 {
+		return nil
+	}
+
+	stale, err := r.IndexedLogs()
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		"DROP TABLE search_lines",
+		"DELETE FROM search_files",
+		"CREATE VIRTUAL TABLE search_lines USING fts5(content, redacted, masked, tokenize = 'unicode61')",
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	r.staleLogs = stale
+	return nil
+}
+
+// RulesFingerprint 已有索引的脱敏列所使用的脱敏规则指纹，从未记录时返回空字符串
+func (r *SearchRepository) RulesFingerprint() (string, error) {
+	var value string
+	err := r.db.QueryRow("SELECT value FROM search_meta WHERE key = ?", rulesFingerprintKey).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return value, err
+}
+
+// SetRulesFingerprint 记录已有索引的脱敏列所使用的脱敏规则指纹
+func (r *SearchRepository) SetRulesFingerprint(fingerprint string) error {
+	_, err := r.db.Exec(`INSERT INTO search_meta (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value`, rulesFingerprintKey, fingerprint)
+	return err
+}
+
+// IndexedLogs 已建立索引的日志
+func (r *SearchRepository) IndexedLogs() ([]string, error) {
+	rows, err := r.db.Query("SELECT DISTINCT log_id FROM search_files ORDER BY log_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logIDs []string
+	for rows.Next() {
+		var logID string
+		if err := rows.Scan(&logID); err != nil {
+			return nil, err
+		}
+		logIDs = append(logIDs, logID)
+	}
+	return logIDs, rows.Err()
+}
+
+// IndexFile 索引单个文件的所有行（已存在的索引会被替换），返回行数
+// forEach 逐行读取文件并对每行调用 add，文件内容不需要全部读入内存；forEach 返回错误时索引保持不变
+// redacted 为该行脱敏后的内容，与原始内容相同时只保存原始内容
+func (r *SearchRepository) IndexFile(logID, filePath string, forEach func(add func(lineNo int, line, redacted string) error) error) (int, error) {
+	if !r.ftsEnabled {
+		return 0, fmt.Errorf(models.ErrSearchUnavailable)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var fileID int64
+	err = tx.QueryRow("SELECT id FROM search_files WHERE log_id = ? AND file_path = ?", logID, filePath).Scan(&fileID)
+	switch {
+	case err == sql.ErrNoRows:
+		result, err := tx.Exec("INSERT INTO search_files (log_id, file_path, line_count) VALUES (?, ?, 0)",
+			logID, filePath)
+		if err != nil {
+			return 0, err
+		}
+		if fileID, err = result.LastInsertId(); err != nil {
+			return 0, err
+		}
+	case err != nil:
+		return 0, err
+	default:
+		if err := deleteFileLines(tx, fileID); err != nil {
+			return 0, err
+		}
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO search_lines (rowid, content, redacted, masked) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	base := fileID << lineIDBits
+	lineCount := 0
+	err = forEach(func(lineNo int, line, redacted string) error {
+		lineCount = lineNo
+		if strings.TrimSpace(line) == "" {
+			return nil
+		}
+		masked := ""
+		if redacted == line {
+			redacted = ""
+		} else {
+			masked = maskedToken
+		}
+		_, err := stmt.Exec(base|int64(lineNo), line, redacted, masked)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec("UPDATE search_files SET line_count = ? WHERE id = ?", lineCount, fileID); err != nil {
+		return 0, err
+	}
+	return lineCount, tx.Commit()
+}
+
+// DeleteByLogID 删除指定日志的所有索引
+func (r *SearchRepository) DeleteByLogID(logID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT id FROM search_files WHERE log_id = ?", logID)
+	if err != nil {
+		return err
+	}
+	var fileIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		fileIDs = append(fileIDs, id)
+	}
+	rows.Close()
+
+	if r.ftsEnabled {
+		for _, id := range fileIDs {
+			if err := deleteFileLines(tx, id); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := tx.Exec("DELETE FROM search_files WHERE log_id = ?", logID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deleteFileLines 按 rowid 范围删除单个文件的索引行
+func deleteFileLines(tx *sql.Tx, fileID int64) error {
+	base := fileID << lineIDBits
+	_, err := tx.Exec("DELETE FROM search_lines WHERE rowid BETWEEN ? AND ?", base, base|(1<<lineIDBits-1))
+	return err
+}
+
+// Search 全文搜索，logID为空时搜索所有日志，摘要中的命中部分用 SnippetMarkStart 和 SnippetMarkEnd 标记
+// raw为false时只在脱敏后的文本上匹配：脱敏后有变化的行匹配 redacted 列，其他行匹配 content 列
+func (r *SearchRepository) Search(query, logID string, offset, limit int, raw bool) (*models.SearchResult, error) {
+	if !r.ftsEnabled {
+		return nil, fmt.Errorf(models.ErrSearchUnavailable)
+	}
+
+	phrase := ftsPhrase(query)
+	match := "content : " + phrase
+	if !raw {
+		match = fmt.Sprintf("redacted : %s OR (content : %s NOT masked : %s)", phrase, phrase, maskedToken)
+	}
+	result := &models.SearchResult{
+		Query:   query,
+		Offset:  offset,
+		Limit:   limit,
+		Bundles: []models.SearchBundle{},
+		Hits:    []models.SearchHit{},
+	}
+
+	where := "search_lines MATCH ?"
+	args := []interface{}{match}
+	if logID != "" {
+		where += " AND f.log_id = ?"
+		args = append(args, logID)
+	}
+
+	// 按日志包汇总命中数
+	bundleRows, err := r.db.Query(fmt.Sprintf(`
+		SELECT f.log_id, COUNT(*), COUNT(DISTINCT f.id)
+		FROM search_lines
+		JOIN search_files f ON f.id = (search_lines.rowid >> %d)
+		WHERE %s
+		GROUP BY f.log_id
+		ORDER BY COUNT(*) DESC`, lineIDBits, where), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer bundleRows.Close()
+
+	for bundleRows.Next() {
+		var b models.SearchBundle
+		if err := bundleRows.Scan(&b.LogID, &b.Hits, &b.Files); err != nil {
+			return nil, err
+		}
+		result.Total += b.Hits
+		result.Bundles = append(result.Bundles, b)
+	}
+	if err := bundleRows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 分页返回命中行
+	hitRows, err := r.db.Query(fmt.Sprintf(`
+		SELECT f.log_id, f.file_path, search_lines.rowid & %d,
+			   CASE WHEN ? AND search_lines.masked != '' THEN snippet(search_lines, 1, ?, ?, '...', 24)
+					ELSE snippet(search_lines, 0, ?, ?, '...', 24) END
+		FROM search_lines
+		JOIN search_files f ON f.id = (search_lines.rowid >> %d)
+		WHERE %s
+		ORDER BY f.log_id, f.file_path, search_lines.rowid
+		LIMIT ? OFFSET ?`, int64(1<<lineIDBits-1), lineIDBits, where), append(append([]interface{}{!raw, SnippetMarkStart, SnippetMarkEnd, SnippetMarkStart, SnippetMarkEnd}, args...), limit, offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer hitRows.Close()
+
+	for hitRows.Next() {
+		var hit models.SearchHit
+		if err := hitRows.Scan(&hit.LogID, &hit.FilePath, &hit.Line, &hit.Snippet); err != nil {
+			return nil, err
+		}
+		result.Hits = append(result.Hits, hit)
+	}
+
+	return result, hitRows.Err()
+}
+
+// ftsPhrase 将用户输入转换为FTS5短语查询，避免特殊字符被解析为查询语法
+func ftsPhrase(query string) string {
+	return `"` + strings.ReplaceAll(query, `"`, `""`) + `"`
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"encoding/json"
+	"logview-goversion/internal/repository"
+)
+
+// AnalysisCache 分析结果缓存，按日志保存JSON序列化后的结果
+// 日志重新导入或删除时清空该日志的所有缓存
+type AnalysisCache struct {
+	repo *repository.AnalysisRepository
+}
+
+// NewAnalysisCache 创建分析结果缓存
+func NewAnalysisCache(repo *repository.AnalysisRepository) *AnalysisCache {
+	return &AnalysisCache{repo: repo}
+}
+
+// Load 读取缓存到v中，返回是否命中
+func (c *AnalysisCache) Load(logID, kind, key string, v interface{}) (bool, error) {
+	data, err := c.repo.Get(logID, kind, key)
+	if err != nil || data == nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		// 结构变化导致无法解析时视为未命中，重新计算
+		return false, nil
+	}
+	return true, nil
+}
+
+// Store 写入缓存
+func (c *AnalysisCache) Store(logID, kind, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.repo.Put(logID, kind, key, data)
+}
+
+// OnLogImported 重新导入后内容可能变化，清空旧缓存
+func (c *AnalysisCache) OnLogImported(logID string) error {
+	return c.repo.DeleteByLogID(logID)
+}
+
+// OnLogDeleted 删除日志后清理缓存
+func (c *AnalysisCache) OnLogDeleted(logID string) error {
+	return c.repo.DeleteByLogID(logID)
+}
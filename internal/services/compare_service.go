@@ -0,0 +1,108 @@
+package services
+
+import (
+	"logview-goversion/internal/models"
+	"path/filepath"
+	"sort"
+)
+
+// CompareService 日志包文件树比较服务
+type CompareService struct {
+	fileService *FileService
+	diffService *DiffService
+}
+
+// NewCompareService 创建日志包比较服务
+func NewCompareService(fileService *FileService, diffService *DiffService) *CompareService {
+	return &CompareService{
+		fileService: fileService,
+		diffService: diffService,
+	}
+}
+
+// CompareLogs 比较两个日志包的文件树，按大小和内容哈希找出新增、删除和修改的文件
+func (s *CompareService) CompareLogs(logA, logB string) (*models.CompareResult, error) {
+	filesA, err := s.listFiles(logA)
+	if err != nil {
+		return nil, err
+	}
+	filesB, err := s.listFiles(logB)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.CompareResult{
+		A:     logA,
+		B:     logB,
+		Files: []models.CompareFile{},
+	}
+
+	for path, sizeA := range filesA {
+		sizeB, ok := filesB[path]
+		if !ok {
+			result.Files = append(result.Files, models.CompareFile{Path: path, Status: models.CompareRemoved, SizeA: sizeA})
+			result.Summary.Removed++
+			continue
+		}
+
+		file := models.CompareFile{Path: path, Status: models.CompareChanged, SizeA: sizeA, SizeB: sizeB}
+		if sizeA == sizeB {
+			// 大小相同时比较内容哈希
+			if file.HashA, err = s.fileService.HashFile(logA, path); err != nil {
+				return nil, err
+			}
+			if file.HashB, err = s.fileService.HashFile(logB, path); err != nil {
+				return nil, err
+			}
+			if file.HashA == file.HashB {
+				result.Summary.Unchanged++
+				continue
+			}
+		}
+		result.Files = append(result.Files, file)
+		result.Summary.Changed++
+	}
+
+	for path, sizeB := range filesB {
+		if _, ok := filesA[path]; !ok {
+			result.Files = append(result.Files, models.CompareFile{Path: path, Status: models.CompareAdded, SizeB: sizeB})
+			result.Summary.Added++
+		}
+	}
+
+	sort.Slice(result.Files, func(i, j int) bool {
+		return result.Files[i].Path < result.Files[j].Path
+	})
+	return result, nil
+}
+
+// CompareFile 比较两个日志包中同一路径的文件内容
+func (s *CompareService) CompareFile(logA, logB, path string, opts models.DiffOptions) (*models.DiffResult, error) {
+	return s.diffService.DiffFiles(
+		models.FileRef{LogID: logA, Path: path},
+		models.FileRef{LogID: logB, Path: path},
+		opts,
+	)
+}
+
+// listFiles 从文件树中收集所有文件的相对路径和大小
+func (s *CompareService) listFiles(logID string) (map[string]int64, error) {
+	root, err := s.fileService.GetFileStructure(logID)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]int64)
+	var walk func(node *models.FileNode)
+	walk = func(node *models.FileNode) {
+		if node.Type == "file" {
+			files[filepath.ToSlash(node.Path)] = node.Size
+			return
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(root)
+	return files, nil
+}
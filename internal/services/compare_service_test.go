@@ -0,0 +1,152 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"logview-goversion/internal/config"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/repository"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newCompareTestService 在临时目录中写入两个日志包（log1、log2）的文件并创建比较服务
+func newCompareTestService(t *testing.T, maxFileSize int64, filesA, filesB map[string][]byte) *CompareService {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	cfg.Storage.ZipDir = filepath.Join(dir, "zip")
+	cfg.Storage.ExtractDir = filepath.Join(dir, "extracted")
+	cfg.Storage.MaxFileSize = maxFileSize
+
+	logRepo, err := repository.NewLogRepository(filepath.Join(dir, "logs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { logRepo.Close() })
+	indexRepo, err := repository.NewFileIndexRepository(logRepo.DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeRepo, err := repository.NewFileTreeRepository(logRepo.DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for logID, files := range map[string]map[string][]byte{"log1": filesA, "log2": filesB} {
+		for name, data := range files {
+			path := filepath.Join(cfg.Storage.ExtractDir, logID, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	fileService := NewFileService(cfg, nil, indexRepo, treeRepo)
+	return NewCompareService(fileService, NewDiffService(fileService, nil))
+}
+
+// gzipBytes 压缩内容
+func gzipBytes(t *testing.T, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// 解压后超过大小限制的文件不能只比较前面部分
+func TestCompareLogsTruncatedFiles(t *testing.T) {
+	prefix := strings.Repeat("2024-01-01T00:00:00Z INFO same line\n", 10)
+	a := gzipBytes(t, prefix+"tail a\n")
+	b := gzipBytes(t, prefix+"tail b\n")
+	if len(a) != len(b) {
+		t.Fatalf("压缩后大小不同 %d/%d，无法覆盖按哈希比较的路径", len(a), len(b))
+	}
+
+	s := newCompareTestService(t, int64(len(prefix)),
+		map[string][]byte{"app.log.gz": a, "same.log.gz": a},
+		map[string][]byte{"app.log.gz": b, "same.log.gz": a})
+	result, err := s.CompareLogs("log1", "log2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Summary.Changed != 1 || result.Summary.Unchanged != 1 {
+		t.Fatalf("Summary = %+v, 期望 1 个修改、1 个未变", result.Summary)
+	}
+	if got := result.Files[0].Path; got != "app.log.gz" {
+		t.Errorf("修改的文件 = %s, 期望 app.log.gz", got)
+	}
+}
+
+func TestCompareLogs(t *testing.T) {
+	s := newCompareTestService(t, 1<<20,
+		map[string][]byte{
+			"same.log":      []byte("same\n"),
+			"sub/same.log":  []byte("nested\n"),
+			"removed.log":   []byte("gone\n"),
+			"resized.log":   []byte("short\n"),
+			"same-size.log": []byte("aaaa\n"),
+		},
+		map[string][]byte{
+			"same.log":      []byte("same\n"),
+			"sub/same.log":  []byte("nested\n"),
+			"added.log":     []byte("new\n"),
+			"resized.log":   []byte("much longer\n"),
+			"same-size.log": []byte("bbbb\n"),
+		})
+
+	result, err := s.CompareLogs("log1", "log2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.CompareSummary{Added: 1, Removed: 1, Changed: 2, Unchanged: 2}
+	if result.Summary != want {
+		t.Errorf("Summary = %+v, 期望 %+v", result.Summary, want)
+	}
+
+	type file struct {
+		path, status string
+		hashed       bool
+	}
+	var got []file
+	for _, f := range result.Files {
+		got = append(got, file{f.Path, f.Status, f.HashA != "" && f.HashB != ""})
+	}
+	wantFiles := []file{
+		{"added.log", models.CompareAdded, false},
+		{"removed.log", models.CompareRemoved, false},
+		// 大小不同时不计算哈希
+		{"resized.log", models.CompareChanged, false},
+		{"same-size.log", models.CompareChanged, true},
+	}
+	if !reflect.DeepEqual(got, wantFiles) {
+		t.Errorf("Files = %v, 期望 %v", got, wantFiles)
+	}
+
+	// 修改的文件可以继续查看内容差异
+	diff, err := s.CompareFile("log1", "log2", "same-size.log", models.DiffOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(diff)
+	if !strings.Contains(string(data), "aaaa") || !strings.Contains(string(data), "bbbb") {
+		t.Errorf("CompareFile() = %s, 期望包含两边的内容", data)
+	}
+
+	if _, err := s.CompareLogs("log1", "missing"); err == nil {
+		t.Error("日志不存在时应返回错误")
+	}
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"fmt"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/pkg/diffutil"
+	"logview-goversion/internal/pkg/logparser"
+	"strings"
+)
+
+// 比较模式
+const (
+	DiffModeUnified    = "unified"
+	DiffModeSideBySide = "side-by-side"
+)
+
+const (
+	// maxDiffLines 每个文件参与比较的最大行数，超出部分不比较，结果标记为截断
+	maxDiffLines = 20000
+	// defaultDiffContext 默认上下文行数
+	defaultDiffContext = 3
+)
+
+// DiffService 文件比较服务
+type DiffService struct {
+	fileService *FileService
+	redaction   *RedactionService
+}
+
+// NewDiffService 创建文件比较服务
+func NewDiffService(fileService *FileService, redaction *RedactionService) *DiffService {
+	return &DiffService{
+		fileService: fileService,
+		redaction:   redaction,
+	}
+}
+
+// ParseFileRef 解析 log_id:path 形式的文件引用
+func ParseFileRef(ref string) (models.FileRef, error) {
+	idx := strings.Index(ref, ":")
+	if idx <= 0 || idx == len(ref)-1 {
+		return models.FileRef{}, fmt.Errorf(models.ErrInvalidFileRef)
+	}
+	return models.FileRef{LogID: ref[:idx], Path: ref[idx+1:]}, nil
+}
+
+// DiffFiles 比较两个文件（可以在同一个或不同的日志包中）
+func (s *DiffService) DiffFiles(a, b models.FileRef, opts models.DiffOptions) (*models.DiffResult, error) {
+	if opts.Mode == "" {
+		opts.Mode = DiffModeUnified
+	}
+	if opts.Mode != DiffModeUnified && opts.Mode != DiffModeSideBySide {
+		return nil, fmt.Errorf(models.ErrInvalidDiffMode)
+	}
+	if opts.Context < 0 {
+		opts.Context = defaultDiffContext
+	}
+
+	aLines, aTruncated, err := s.fileService.ReadLines(a.LogID, a.Path, maxDiffLines)
+	if err != nil {
+		return nil, fmt.Errorf("读取 %s:%s 失败: %w", a.LogID, a.Path, err)
+	}
+	bLines, bTruncated, err := s.fileService.ReadLines(b.LogID, b.Path, maxDiffLines)
+	if err != nil {
+		return nil, fmt.Errorf("读取 %s:%s 失败: %w", b.LogID, b.Path, err)
+	}
+
+	diffOpts := diffutil.Options{IgnoreWhitespace: opts.IgnoreWhitespace}
+	if opts.IgnoreTimestamps {
+		diffOpts.Normalize = normalizeTimestamps
+	}
+	ops := diffutil.Lines(aLines, bLines, diffOpts)
+
+	// 在原始内容上比较，只对输出的行脱敏，敏感内容变化时仍能看到差异
+	// 跨行的块（如私钥）需要按顺序读完整个文件才能确定，先替换块再逐行应用其他规则
+	aShown := s.redaction.MaskBlocks(aLines, opts.Raw)
+	bShown := s.redaction.MaskBlocks(bLines, opts.Raw)
+	redact := func(line string) string {
+		return s.redaction.Redact(line, opts.Raw)
+	}
+
+	result := &models.DiffResult{
+		A:         a,
+		B:         b,
+		Mode:      opts.Mode,
+		Truncated: aTruncated || bTruncated,
+		Hunks:     []models.DiffHunk{},
+	}
+	for _, op := range ops {
+		switch op.Kind {
+		case diffutil.Equal:
+			result.Stats.Unchanged++
+		case diffutil.Delete:
+			result.Stats.Removed++
+		case diffutil.Insert:
+			result.Stats.Added++
+		}
+	}
+	result.Identical = result.Stats.Added == 0 && result.Stats.Removed == 0
+
+	var patch strings.Builder
+	if opts.Mode == DiffModeUnified && !result.Identical {
+		fmt.Fprintf(&patch, "--- %s:%s\n+++ %s:%s\n", a.LogID, a.Path, b.LogID, b.Path)
+	}
+
+	for _, h := range diffutil.Hunks(ops, opts.Context) {
+		hunk := models.DiffHunk{
+			Header: fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.AStart, h.ALines, h.BStart, h.BLines),
+			AStart: h.AStart,
+			ALines: h.ALines,
+			BStart: h.BStart,
+			BLines: h.BLines,
+		}
+		if opts.Mode == DiffModeUnified {
+			hunk.Lines = unifiedLines(h.Ops, aShown, bShown, redact)
+			writePatchHunk(&patch, hunk)
+		} else {
+			hunk.Rows = sideBySideRows(h.Ops, aShown, bShown, redact)
+		}
+		result.Hunks = append(result.Hunks, hunk)
+	}
+	result.Patch = patch.String()
+
+	return result, nil
+}
+
+// normalizeTimestamps 将行内时间戳替换为占位符
+func normalizeTimestamps(line string) string {
+	return logparser.TimestampPattern().ReplaceAllString(line, "<TIMESTAMP>")
+}
+
+// unifiedLines 生成unified模式的行，输出的文本经过 redact 处理
+func unifiedLines(ops []diffutil.Op, a, b []string, redact func(string) string) []models.DiffLine {
+	lines := make([]models.DiffLine, 0, len(ops))
+	for _, op := range ops {
+		switch op.Kind {
+		case diffutil.Equal:
+			lines = append(lines, models.DiffLine{Type: "context", ALine: op.AIndex + 1, BLine: op.BIndex + 1, Text: redact(a[op.AIndex])})
+		case diffutil.Delete:
+			lines = append(lines, models.DiffLine{Type: "delete", ALine: op.AIndex + 1, Text: redact(a[op.AIndex])})
+		case diffutil.Insert:
+			lines = append(lines, models.DiffLine{Type: "add", BLine: op.BIndex + 1, Text: redact(b[op.BIndex])})
+		}
+	}
+	return lines
+}
+
+// writePatchHunk 输出补丁文本中的一个差异块
+func writePatchHunk(sb *strings.Builder, hunk models.DiffHunk) {
+	sb.WriteString(hunk.Header)
+	sb.WriteByte('\n')
+	for _, line := range hunk.Lines {
+		switch line.Type {
+		case "delete":
+			sb.WriteByte('-')
+		case "add":
+			sb.WriteByte('+')
+		default:
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(line.Text)
+		sb.WriteByte('\n')
+	}
+}
+
+// sideBySideRows 生成并排模式的行，相邻的删除和新增配对为修改，输出的文本经过 redact 处理
+func sideBySideRows(ops []diffutil.Op, a, b []string, redact func(string) string) []models.DiffRow {
+	var rows []models.DiffRow
+	var deleted, inserted []diffutil.Op
+
+	flush := func() {
+		n := len(deleted)
+		if len(inserted) > n {
+			n = len(inserted)
+		}
+		for i := 0; i < n; i++ {
+			row := models.DiffRow{}
+			if i < len(deleted) {
+				row.Left = &models.DiffCell{Line: deleted[i].AIndex + 1, Text: redact(a[deleted[i].AIndex])}
+			}
+			if i < len(inserted) {
+				row.Right = &models.DiffCell{Line: inserted[i].BIndex + 1, Text: redact(b[inserted[i].BIndex])}
+			}
+			switch {
+			case row.Left != nil && row.Right != nil:
+				row.Type = "change"
+			case row.Left != nil:
+				row.Type = "delete"
+			default:
+				row.Type = "add"
+			}
+			rows = append(rows, row)
+		}
+		deleted, inserted = deleted[:0], inserted[:0]
+	}
+
+	for _, op := range ops {
+		switch op.Kind {
+		case diffutil.Delete:
+			deleted = append(deleted, op)
+		case diffutil.Insert:
+			inserted = append(inserted, op)
+		default:
+			flush()
+			rows = append(rows, models.DiffRow{
+				Type:  "equal",
+				Left:  &models.DiffCell{Line: op.AIndex + 1, Text: redact(a[op.AIndex])},
+				Right: &models.DiffCell{Line: op.BIndex + 1, Text: redact(b[op.BIndex])},
+			})
+		}
+	}
+	flush()
+	return rows
+}
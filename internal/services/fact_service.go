@@ -0,0 +1,172 @@
+package services
+
+import (
+	"fmt"
+	"logview-goversion/internal/config"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/pkg/extract"
+	"logview-goversion/internal/repository"
+	"path"
+	"strings"
+)
+
+// maxFactFileLines 提取元数据时每个文件最多读取的行数，已知文件通常很小
+const maxFactFileLines = 10000
+
+// FactService 日志元数据服务，导入时从已知文件（系统版本、固件版本、磁盘和网络配置等）提取
+type FactService struct {
+	factRepo    *repository.FactRepository
+	fileService *FileService
+	extractors  *extract.Set
+	redaction   *RedactionService
+}
+
+// NewFactService 创建元数据服务，自定义规则在内置规则之前应用
+func NewFactService(cfg *config.Config, factRepo *repository.FactRepository, fileService *FileService, redaction *RedactionService) (*FactService, error) {
+	extractors := []extract.Extractor{}
+	if cfg.Facts.ExtractorsFile != "" {
+		custom, err := extract.LoadExtractors(cfg.Facts.ExtractorsFile)
+		if err != nil {
+			return nil, err
+		}
+		extractors = append(extractors, custom...)
+	}
+	extractors = append(extractors, extract.BuiltinExtractors()...)
+
+	set, err := extract.New(extractors)
+	if err != nil {
+		return nil, err
+	}
+	return &FactService{
+		factRepo:    factRepo,
+		fileService: fileService,
+		extractors:  set,
+		redaction:   redaction,
+	}, nil
+}
+
+// OnLogImported 导入完成后提取元数据
+func (s *FactService) OnLogImported(logID string) error {
+	_, err := s.ExtractFacts(logID, true)
+	return err
+}
+
+// OnLogDeleted 删除日志的元数据
+func (s *FactService) OnLogDeleted(logID string) error {
+	return s.factRepo.DeleteByLogID(logID)
+}
+
+// Extractors 获取生效的提取规则
+func (s *FactService) Extractors() []extract.Extractor {
+	return s.extractors.Extractors()
+}
+
+// ExtractFacts 对日志中的文本文件运行提取规则并替换旧的元数据，同一字段以按路径排序的第一个文件为准
+// 保存原始值，返回的值按 raw 脱敏
+func (s *FactService) ExtractFacts(logID string, raw bool) ([]models.Fact, error) {
+	var files []string
+	err := s.fileService.WalkTextFiles(logID, func(relPath string, size int64) error {
+		if s.extractors.Match(relPath) {
+			files = append(files, relPath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	facts := []models.Fact{}
+	seen := make(map[string]bool)
+	for _, file := range files {
+		lines, _, err := s.fileService.ReadLines(logID, file, maxFactFileLines)
+		if err != nil {
+			return nil, fmt.Errorf("读取文件 %s 失败: %w", file, err)
+		}
+		for _, f := range s.extractors.Extract(file, strings.Join(lines, "\n")) {
+			if seen[f.Field] {
+				continue
+			}
+			seen[f.Field] = true
+			facts = append(facts, models.Fact{Name: f.Field, Value: f.Value, FilePath: file})
+		}
+	}
+
+	if err := s.factRepo.ReplaceFacts(logID, facts); err != nil {
+		return nil, fmt.Errorf("保存元数据失败: %w", err)
+	}
+	return s.listFacts(logID, raw)
+}
+
+// GetFacts 获取日志的元数据，值按 raw 脱敏
+func (s *FactService) GetFacts(logID string, raw bool) (*models.FactResult, error) {
+	facts, err := s.listFacts(logID, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &models.FactResult{LogID: logID, Facts: facts}, nil
+}
+
+// listFacts 读取日志的元数据并脱敏
+func (s *FactService) listFacts(logID string, raw bool) ([]models.Fact, error) {
+	facts, err := s.factRepo.ListFacts(logID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range facts {
+		facts[i].Value = s.redaction.RedactField(facts[i].Name, facts[i].Value, raw)
+	}
+	return facts, nil
+}
+
+// ParseFactFilter 解析 name 或 name=value 形式的过滤条件
+func ParseFactFilter(value string) (models.FactFilter, error) {
+	name, val, _ := strings.Cut(value, "=")
+	filter := models.FactFilter{Name: strings.TrimSpace(name), Value: strings.TrimSpace(val)}
+	if filter.Name == "" {
+		return filter, fmt.Errorf(models.ErrInvalidFact)
+	}
+	if _, err := path.Match(filter.Value, ""); err != nil {
+		return filter, fmt.Errorf("%s: %s", models.ErrInvalidFact, value)
+	}
+	return filter, nil
+}
+
+// FilterLogs 为日志附加元数据，并只保留满足所有过滤条件的日志
+// 元数据值按 raw 脱敏，过滤条件只和脱敏后的值比较，避免无权查看原始内容时通过过滤结果推测被隐藏的值
+func (s *FactService) FilterLogs(logs []models.Log, filters []models.FactFilter, raw bool) ([]models.Log, error) {
+	values, err := s.factRepo.AllValues()
+	if err != nil {
+		return nil, err
+	}
+	for _, facts := range values {
+		for name, value := range facts {
+			facts[name] = s.redaction.RedactField(name, value, raw)
+		}
+	}
+
+	filtered := make([]models.Log, 0, len(logs))
+	for _, log := range logs {
+		log.Facts = values[log.LogID]
+		if matchFacts(log.Facts, filters) {
+			filtered = append(filtered, log)
+		}
+	}
+	return filtered, nil
+}
+
+// matchFacts 元数据是否满足所有过滤条件，值比较不区分大小写
+func matchFacts(facts map[string]string, filters []models.FactFilter) bool {
+	for _, f := range filters {
+		value, ok := facts[f.Name]
+		if !ok {
+			return false
+		}
+		if f.Value == "" {
+			continue
+		}
+		if matched, _ := path.Match(strings.ToLower(f.Value), strings.ToLower(value)); !matched {
+			return false
+		}
+	}
+	return true
+}
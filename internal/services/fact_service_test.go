@@ -0,0 +1,192 @@
+package services
+
+import (
+	"logview-goversion/internal/config"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/repository"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFactFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    models.FactFilter
+		wantErr bool
+	}{
+		{name: "只有名称", value: "hostname", want: models.FactFilter{Name: "hostname"}},
+		{name: "名称和值", value: " os_name = Ubuntu ", want: models.FactFilter{Name: "os_name", Value: "Ubuntu"}},
+		{name: "值中的等号", value: "args=a=b", want: models.FactFilter{Name: "args", Value: "a=b"}},
+		{name: "通配符", value: "os_version=22.*", want: models.FactFilter{Name: "os_version", Value: "22.*"}},
+		{name: "缺少名称", value: "=x", wantErr: true},
+		{name: "无效的通配符", value: "os_name=[", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFactFilter(tt.value)
+			if tt.wantErr {
+				if err == nil || !strings.HasPrefix(err.Error(), models.ErrInvalidFact) {
+					t.Errorf("ParseFactFilter() 错误 = %v, 期望 %s", err, models.ErrInvalidFact)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseFactFilter() = %+v, %v, 期望 %+v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractFactsAndFilterLogs(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	cfg.Storage.ZipDir = filepath.Join(dir, "zip")
+	cfg.Storage.ExtractDir = filepath.Join(dir, "extracted")
+	cfg.Storage.MaxFileSize = 1 << 20
+	cfg.Storage.MaxPreview = 1 << 20
+	cfg.Redaction.Enabled = true
+	cfg.Facts.ExtractorsFile = filepath.Join(dir, "extractors.json")
+	if err := os.WriteFile(cfg.Facts.ExtractorsFile, []byte(`[{"field": "os_name", "path": "os-release", "pattern": "PRETTY_NAME=\"([^\"]+)"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]map[string]string{
+		"log1": {
+			"a/os-release": "NAME=\"Ubuntu\"\nVERSION_ID=\"22.04\"\n",
+			"b/os-release": "NAME=\"Debian GNU/Linux\"\nVERSION_ID=\"12\"\n",
+			"net/ip-route": "default via 10.0.0.1 dev eth0\n",
+		},
+		"log2": {
+			"etc/os-release": "PRETTY_NAME=\"Appliance OS 12\"\nNAME=\"Debian GNU/Linux\"\nVERSION_ID=\"12\"\n",
+		},
+		"log3": {"app.log": "2024-01-01T00:00:01Z INFO started\n"},
+	}
+	for logID, logFiles := range files {
+		for name, content := range logFiles {
+			path := filepath.Join(cfg.Storage.ExtractDir, logID, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	logRepo, err := repository.NewLogRepository(filepath.Join(dir, "logs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logRepo.Close()
+	factRepo, err := repository.NewFactRepository(logRepo.DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	redaction, err := NewRedactionService(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewFactService(cfg, factRepo, NewFileService(cfg, redaction, nil, nil), redaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for logID := range files {
+		if err := s.OnLogImported(logID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 同一字段以按路径排序的第一个文件为准
+	result, err := s.GetFacts("log1", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string)
+	for _, f := range result.Facts {
+		got[f.Name] = f.Value + " @ " + f.FilePath
+	}
+	want := map[string]string{
+		"os_name":         "Ubuntu @ a/os-release",
+		"os_version":      "22.04 @ a/os-release",
+		"default_gateway": "10.0.0.1 @ net/ip-route",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetFacts(log1) = %v, 期望 %v", got, want)
+	}
+
+	// 自定义规则在内置规则之前应用
+	if result, err := s.GetFacts("log2", true); err != nil || len(result.Facts) != 2 {
+		t.Errorf("GetFacts(log2) = %+v, %v", result, err)
+	} else {
+		for _, f := range result.Facts {
+			if f.Name == "os_name" && f.Value != "Appliance OS 12" {
+				t.Errorf("os_name = %s, 期望自定义规则的值 Appliance OS 12", f.Value)
+			}
+		}
+	}
+
+	// 没有原始内容权限时返回脱敏后的值
+	if result, err := s.GetFacts("log1", false); err != nil {
+		t.Fatal(err)
+	} else {
+		for _, f := range result.Facts {
+			if f.Name == "default_gateway" && strings.Contains(f.Value, "10.0.0.1") {
+				t.Errorf("default_gateway 未脱敏: %s", f.Value)
+			}
+		}
+	}
+
+	logs := []models.Log{{LogID: "log1"}, {LogID: "log2"}, {LogID: "log3"}}
+	tests := []struct {
+		name    string
+		filters []string
+		raw     bool
+		want    []string
+	}{
+		{name: "不过滤时保留所有日志", want: []string{"log1", "log2", "log3"}},
+		{name: "存在元数据", filters: []string{"os_version"}, want: []string{"log1", "log2"}},
+		{name: "值不区分大小写", filters: []string{"os_name=ubuntu"}, want: []string{"log1"}},
+		{name: "通配符", filters: []string{"os_name=*os*"}, want: []string{"log2"}},
+		{name: "多个条件同时满足", filters: []string{"os_version=12", "os_name=Appliance*"}, want: []string{"log2"}},
+		{name: "脱敏的值不能按原始值过滤", filters: []string{"default_gateway=10.*"}, want: []string{}},
+		{name: "有原始内容权限时按原始值过滤", filters: []string{"default_gateway=10.*"}, raw: true, want: []string{"log1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filters []models.FactFilter
+			for _, value := range tt.filters {
+				filter, err := ParseFactFilter(value)
+				if err != nil {
+					t.Fatal(err)
+				}
+				filters = append(filters, filter)
+			}
+			filtered, err := s.FilterLogs(logs, filters, tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := []string{}
+			for _, log := range filtered {
+				ids = append(ids, log.LogID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("FilterLogs() = %v, 期望 %v", ids, tt.want)
+			}
+		})
+	}
+
+	// 删除日志后元数据一并删除
+	if err := s.OnLogDeleted("log1"); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := s.GetFacts("log1", true); err != nil || len(result.Facts) != 0 {
+		t.Errorf("删除后 GetFacts() = %+v, %v, 期望为空", result, err)
+	}
+}
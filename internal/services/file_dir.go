@@ -0,0 +1,154 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"logview-goversion/internal/models"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultDirLimit = 500
+	maxDirLimit     = 5000
+)
+
+// dirEntry 目录项的名称和类型，用作分页游标
+type dirEntry struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Dir  bool   `json:"dir,omitempty"`
+}
+
+// ValidateDirOptions 检查目录列表的匹配模式和分页游标
+func ValidateDirOptions(opts models.DirOptions) error {
+	if err := validateFileGlob(opts.Glob); err != nil {
+		return err
+	}
+	_, err := decodeDirCursor(opts.Cursor)
+	return err
+}
+
+// ListDir 分页列出日志中一个目录的直接子项（目录优先，然后按名称），
+// 指定 Query 或 Glob 时改为在该目录下递归查找名称匹配的文件和目录，按路径排序。
+// 目录结构和文件信息都取自导入时保存的文件树，不访问磁盘
+func (s *FileService) ListDir(logID string, opts models.DirOptions) (*models.DirListing, error) {
+	if err := ValidateDirOptions(opts); err != nil {
+		return nil, err
+	}
+	after, _ := decodeDirCursor(opts.Cursor)
+
+	dirPath := strings.Trim(filepath.ToSlash(filepath.Clean("/"+opts.Path)), "/")
+	dir, err := s.treeDir(logID, dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultDirLimit
+	}
+	if limit > maxDirLimit {
+		limit = maxDirLimit
+	}
+
+	result := &models.DirListing{
+		Path:    dirPath,
+		Entries: []*models.FileNode{},
+	}
+	var nodes []models.FileNode
+	query := strings.TrimSpace(opts.Query)
+	if query != "" || opts.Glob != "" {
+		// 游标之后的匹配项只取一页，总数需要遍历所有匹配项
+		err = s.treeRepo.FindNodes(logID, dirPath, query, func(n models.FileNode) error {
+			if !matchFileGlob(opts.Glob, n.Path) {
+				return nil
+			}
+			result.Total++
+			if (after == nil || n.Path > after.Path) && len(nodes) <= limit {
+				nodes = append(nodes, n)
+			}
+			return nil
+		})
+	} else {
+		// 多取一项用于判断是否还有下一页
+		result.Total = dir.ChildCount
+		afterDir, afterName := false, ""
+		if after != nil {
+			afterDir, afterName = after.Dir, after.Name
+		}
+		nodes, err = s.treeRepo.ListChildren(logID, dirPath, afterDir, afterName, limit+1)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodes) > limit {
+		nodes = nodes[:limit]
+		result.HasMore = true
+		last := nodes[limit-1]
+		result.NextCursor = encodeDirCursor(dirEntry{Name: last.Name, Path: last.Path, Dir: last.Type == "directory"})
+	}
+
+	index, err := s.indexRepo.GetIndex(logID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range nodes {
+		node := &nodes[i]
+		if entry, ok := index[node.Path]; ok && node.Type == "file" {
+			node.ContentType = entry.ContentType
+			node.Encoding = entry.Encoding
+			node.Compression = entry.Compression
+			node.Lines = entry.Lines
+			node.Errors = entry.Errors
+			node.Warnings = entry.Warnings
+		}
+		result.Entries = append(result.Entries, node)
+	}
+	return result, nil
+}
+
+// treeDir 从保存的文件树获取目录节点，没有保存过文件树时计算并保存
+func (s *FileService) treeDir(logID, dirPath string) (*models.FileNode, error) {
+	nodes, err := s.treeRepo.GetNodes(logID, []string{"", dirPath})
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := nodes[""]; !ok {
+		if _, err := s.SaveFileTree(logID); err != nil {
+			return nil, err
+		}
+		if nodes, err = s.treeRepo.GetNodes(logID, []string{dirPath}); err != nil {
+			return nil, err
+		}
+	}
+	dir, ok := nodes[dirPath]
+	if !ok || dir.Type != "directory" {
+		return nil, fmt.Errorf(models.ErrFileNotFound)
+	}
+	return &dir, nil
+}
+
+// encodeDirCursor 将本页最后一项编码为游标
+func encodeDirCursor(last dirEntry) string {
+	data, _ := json.Marshal(last)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeDirCursor 解析游标，空游标返回 nil
+func decodeDirCursor(cursor string) (*dirEntry, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf(models.ErrInvalidCursor)
+	}
+	var last dirEntry
+	if err := json.Unmarshal(data, &last); err != nil || last.Name == "" {
+		return nil, fmt.Errorf(models.ErrInvalidCursor)
+	}
+	return &last, nil
+}
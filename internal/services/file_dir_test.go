@@ -0,0 +1,118 @@
+package services
+
+import (
+	"logview-goversion/internal/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestListDir(t *testing.T) {
+	s, _ := newIndexedFileTestService(t, 1<<20, map[string]string{
+		"b.log":             "2024-01-01T00:00:01Z ERROR b\n",
+		"a.log":             "a\n",
+		"C.txt":             "c\n",
+		"var/log/syslog":    "s\n",
+		"var/log/app.log":   "x\n",
+		"var/log/old/app.1": "y\n",
+		"etc/app.conf":      "z\n",
+	})
+	if err := s.OnLogImported("log1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// listAll 按游标翻页取出所有项
+	listAll := func(opts models.DirOptions) ([]string, int) {
+		var paths []string
+		total := 0
+		for page := 0; ; page++ {
+			result, err := s.ListDir("log1", opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if opts.Limit > 0 && len(result.Entries) > opts.Limit {
+				t.Fatalf("第 %d 页有 %d 项, 超过 %d", page, len(result.Entries), opts.Limit)
+			}
+			for _, e := range result.Entries {
+				paths = append(paths, e.Path)
+			}
+			total = result.Total
+			if !result.HasMore {
+				if result.NextCursor != "" {
+					t.Errorf("最后一页 NextCursor = %q", result.NextCursor)
+				}
+				return paths, total
+			}
+			if page > 10 {
+				t.Fatal("翻页没有结束")
+			}
+			opts.Cursor = result.NextCursor
+		}
+	}
+
+	tests := []struct {
+		name  string
+		opts  models.DirOptions
+		want  []string
+		total int
+	}{
+		{name: "根目录：目录优先，然后按名称", opts: models.DirOptions{}, want: []string{"etc", "var", "C.txt", "a.log", "b.log"}, total: 5},
+		{name: "分页", opts: models.DirOptions{Limit: 2}, want: []string{"etc", "var", "C.txt", "a.log", "b.log"}, total: 5},
+		{name: "子目录", opts: models.DirOptions{Path: "/var/log/", Limit: 1}, want: []string{"var/log/old", "var/log/app.log", "var/log/syslog"}, total: 3},
+		{name: "路径不能超出日志目录", opts: models.DirOptions{Path: "../../etc"}, want: []string{"etc/app.conf"}, total: 1},
+		{name: "按名称递归查找，不区分大小写", opts: models.DirOptions{Query: "APP", Limit: 2}, want: []string{"etc/app.conf", "var/log/app.log", "var/log/old/app.1"}, total: 3},
+		{name: "在子目录中查找", opts: models.DirOptions{Path: "var", Query: "app"}, want: []string{"var/log/app.log", "var/log/old/app.1"}, total: 2},
+		{name: "按文件名匹配", opts: models.DirOptions{Glob: "*.log"}, want: []string{"a.log", "b.log", "var/log/app.log"}, total: 3},
+		{name: "按相对路径匹配", opts: models.DirOptions{Glob: "var/log/*"}, want: []string{"var/log/app.log", "var/log/old", "var/log/syslog"}, total: 3},
+		{name: "没有匹配项", opts: models.DirOptions{Query: "missing"}, want: nil, total: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total := listAll(tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListDir() = %v, 期望 %v", got, tt.want)
+			}
+			if total != tt.total {
+				t.Errorf("Total = %d, 期望 %d", total, tt.total)
+			}
+		})
+	}
+
+	// 目录带子项数和文件数，文件带索引信息
+	result, err := s.ListDir("log1", models.DirOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range result.Entries {
+		switch e.Path {
+		case "var":
+			if e.ChildCount != 1 || e.FileCount != 3 {
+				t.Errorf("var ChildCount/FileCount = %d/%d, 期望 1/3", e.ChildCount, e.FileCount)
+			}
+		case "b.log":
+			if e.ContentType != "text" || e.Lines != 1 || e.Errors != 1 {
+				t.Errorf("b.log = %+v, 期望带索引信息", e)
+			}
+		}
+	}
+
+	errTests := []struct {
+		name string
+		opts models.DirOptions
+		want string
+	}{
+		{name: "不存在的目录", opts: models.DirOptions{Path: "missing"}, want: models.ErrFileNotFound},
+		{name: "路径是文件", opts: models.DirOptions{Path: "a.log"}, want: models.ErrFileNotFound},
+		{name: "无效的游标", opts: models.DirOptions{Cursor: "not-a-cursor"}, want: models.ErrInvalidCursor},
+		{name: "无效的匹配模式", opts: models.DirOptions{Glob: "["}, want: models.ErrInvalidGlob},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.ListDir("log1", tt.opts)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("ListDir() 错误 = %v, 期望 %s", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"fmt"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/pkg/logparser"
+	"os"
+	"path/filepath"
+)
+
+// OnLogImported 导入（或重新解压）完成后保存文件树、建立文件索引，并使旧的文件树缓存失效
+func (s *FileService) OnLogImported(logID string) error {
+	defer s.InvalidateTreeCache(logID)
+	if _, err := s.SaveFileTree(logID); err != nil {
+		return err
+	}
+	return s.IndexFiles(logID)
+}
+
+// OnLogDeleted 删除保存的文件树、文件索引和文件树缓存
+func (s *FileService) OnLogDeleted(logID string) error {
+	s.InvalidateTreeCache(logID)
+	if err := s.treeRepo.DeleteByLogID(logID); err != nil {
+		return err
+	}
+	return s.indexRepo.DeleteByLogID(logID)
+}
+
+// IndexFiles 识别日志中每个文件的内容类型和编码，并统计文本文件的行数和错误/警告行数
+// 超过大小限制的文件只识别类型，不统计行数
+func (s *FileService) IndexFiles(logID string) error {
+	extractPath := filepath.Join(s.cfg.Storage.ExtractDir, logID)
+	if _, err := os.Stat(extractPath); os.IsNotExist(err) {
+		return fmt.Errorf(models.ErrLogNotFound)
+	}
+
+	var entries []models.FileIndex
+	err := s.fileUtil.WalkFiles(extractPath, func(relPath, fullPath string, info os.FileInfo) error {
+		content, err := s.fileUtil.DetectContent(fullPath)
+		if err != nil {
+			// 无法读取的文件不建立索引
+			return nil
+		}
+		entry := models.FileIndex{
+			Path:        relPath,
+			ContentType: content.Type,
+			Encoding:    content.Encoding,
+			Compression: content.Compression,
+		}
+		if content.Type != "binary" && info.Size() <= s.cfg.Storage.MaxFileSize {
+			if err := s.countLines(logID, &entry); err != nil {
+				return fmt.Errorf("索引文件 %s 失败: %w", relPath, err)
+			}
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.indexRepo.ReplaceIndex(logID, entries); err != nil {
+		return fmt.Errorf("保存文件索引失败: %w", err)
+	}
+	return nil
+}
+
+// countLines 统计文件的行数和错误/警告行数
+func (s *FileService) countLines(logID string, entry *models.FileIndex) error {
+	sample, err := s.HeadLines(logID, entry.Path, detectSampleLines)
+	if err != nil {
+		return err
+	}
+	lineParser, err := s.NewLineParser("", sample)
+	if err != nil {
+		return err
+	}
+
+	return s.ForEachLine(logID, entry.Path, func(lineNo int, line string) error {
+		entry.Lines++
+		switch lineParser.Parse(lineNo, line).Level {
+		case logparser.LevelError, logparser.LevelFatal:
+			entry.Errors++
+		case logparser.LevelWarn:
+			entry.Warnings++
+		}
+		return nil
+	})
+}
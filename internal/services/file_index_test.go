@@ -0,0 +1,137 @@
+package services
+
+import (
+	"logview-goversion/internal/config"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/repository"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newIndexedFileTestService 在临时目录中写入日志 log1 的文件，创建带文件索引和文件树存储的文件服务，并返回日志目录
+func newIndexedFileTestService(t *testing.T, maxFileSize int64, files map[string]string) (*FileService, string) {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	cfg.Storage.ZipDir = filepath.Join(dir, "zip")
+	cfg.Storage.ExtractDir = filepath.Join(dir, "extracted")
+	cfg.Storage.MaxFileSize = maxFileSize
+	cfg.Storage.MaxPreview = maxFileSize
+
+	logDir := filepath.Join(cfg.Storage.ExtractDir, "log1")
+	for name, content := range files {
+		path := filepath.Join(logDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	logRepo, err := repository.NewLogRepository(filepath.Join(dir, "logs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { logRepo.Close() })
+	indexRepo, err := repository.NewFileIndexRepository(logRepo.DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeRepo, err := repository.NewFileTreeRepository(logRepo.DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewFileService(cfg, nil, indexRepo, treeRepo), logDir
+}
+
+// findNode 按路径查找文件树中的节点
+func findNode(node *models.FileNode, path string) *models.FileNode {
+	if node.Path == path {
+		return node
+	}
+	for _, child := range node.Children {
+		if found := findNode(child, path); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func TestIndexFiles(t *testing.T) {
+	s, logDir := newIndexedFileTestService(t, 512, map[string]string{
+		"app.log": strings.Join([]string{
+			"2024-01-01T00:00:01Z INFO started",
+			"2024-01-01T00:00:02Z ERROR connection refused",
+			"2024-01-01T00:00:03Z FATAL giving up",
+			"2024-01-01T00:00:04Z WARN retrying",
+			"2024-01-01T00:00:05Z INFO done",
+		}, "\n"),
+		"conf/settings.json": `{"name": "设备"}`,
+		"big.log":            strings.Repeat("2024-01-01T00:00:01Z ERROR too big\n", 20),
+		"core":               "\x7fELF\x02\x01\x01\x00\x00\x00",
+	})
+	mtime := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(filepath.Join(logDir, "app.log"), mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.OnLogImported("log1"); err != nil {
+		t.Fatal(err)
+	}
+	tree, err := s.GetFileStructure("log1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want models.FileNode
+	}{
+		{path: "app.log", want: models.FileNode{ContentType: "text", Encoding: "ascii", Lines: 5, Errors: 2, Warnings: 1}},
+		{path: "conf/settings.json", want: models.FileNode{ContentType: "json", Encoding: "utf-8", Lines: 1}},
+		// 超过大小限制的文件只识别类型
+		{path: "big.log", want: models.FileNode{ContentType: "text", Encoding: "ascii"}},
+		{path: "core", want: models.FileNode{ContentType: "binary", Binary: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			node := findNode(tree, tt.path)
+			if node == nil {
+				t.Fatalf("文件树中没有 %s", tt.path)
+			}
+			got := models.FileNode{
+				ContentType: node.ContentType,
+				Encoding:    node.Encoding,
+				Binary:      node.Binary,
+				Lines:       node.Lines,
+				Errors:      node.Errors,
+				Warnings:    node.Warnings,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s = %+v, 期望 %+v", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	if node := findNode(tree, "app.log"); !node.ModTime.Equal(mtime) {
+		t.Errorf("ModTime = %v, 期望 %v", node.ModTime, mtime)
+	}
+
+	// 删除日志后不再有索引信息
+	if err := s.OnLogDeleted("log1"); err != nil {
+		t.Fatal(err)
+	}
+	tree, err = s.GetFileStructure("log1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node := findNode(tree, "app.log"); node.ContentType != "" || node.Lines != 0 {
+		t.Errorf("删除索引后 app.log = %+v", node)
+	}
+}
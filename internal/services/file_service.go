@@ -1,19 +1,35 @@
 package services
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"logview-goversion/internal/config"
 	"logview-goversion/internal/models"
 	"logview-goversion/internal/pkg/cache"
 	"logview-goversion/internal/pkg/fileutil"
 	"logview-goversion/internal/pkg/httpclient"
+	"logview-goversion/internal/pkg/logparser"
 	"logview-goversion/internal/pkg/ziputil"
+	"logview-goversion/internal/repository"
 	"net/url"
-	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
+const (
+	// defaultPageSize 分页读取的默认行数，与前端分页大小一致
+	defaultPageSize = 1000
+	// detectSampleLines 检测日志格式时采样的行数
+	detectSampleLines = 200
+)
+
+// errStopReading 用于提前结束逐行读取
+var errStopReading = errors.New("stop reading")
+
 // FileService 文件服务
 type FileService struct {
 	cfg        *config.Config
@@ -21,16 +37,25 @@ type FileService struct {
 	fileUtil   *fileutil.FileUtil
 	zipUtil    *ziputil.ZipUtil
 	treeCache  *cache.Cache  // 文件树缓存
+	parsers    *logparser.Registry
+	redaction  *RedactionService
+	indexRepo  *repository.FileIndexRepository
+	treeRepo   *repository.FileTreeRepository
 }
 
 // NewFileService 创建文件服务
-func NewFileService(cfg *config.Config) *FileService {
+func NewFileService(cfg *config.Config, redaction *RedactionService, indexRepo *repository.FileIndexRepository, treeRepo *repository.FileTreeRepository) *FileService {
+	fileUtil := fileutil.NewFileUtil(cfg)
 	svc := &FileService{
 		cfg:        cfg,
 		httpClient: httpclient.NewClient(cfg),
-		fileUtil:   fileutil.NewFileUtil(cfg),
-		zipUtil:    ziputil.NewZipUtil(),
+		fileUtil:   fileUtil,
+		zipUtil:    ziputil.NewZipUtil(fileUtil.Storage()),
 		treeCache:  cache.NewCache(5 * time.Minute), // 5分钟缓存
+		parsers:    logparser.Default(),
+		redaction:  redaction,
+		indexRepo:  indexRepo,
+		treeRepo:   treeRepo,
 	}
 	
 	// 启动缓存清理
@@ -62,8 +87,15 @@ func (s *FileService) DownloadLog(logID string) (*models.DownloadResult, error)
 		}, nil
 	}
 
-	// 解压文件
+	// 解压文件，先清空上次解压的内容，避免残留文件（含切换存储方式前的压缩或未压缩副本）
 	extractPath := filepath.Join(s.cfg.Storage.ExtractDir, logID)
+	if err := os.RemoveAll(extractPath); err != nil {
+		os.Remove(zipPath)
+		return &models.DownloadResult{
+			Success: false,
+			Error:   fmt.Sprintf("清理解压目录失败: %v", err),
+		}, nil
+	}
 	if err := s.zipUtil.Extract(zipPath, extractPath); err != nil {
 		os.Remove(zipPath)
 		return &models.DownloadResult{
@@ -82,7 +114,7 @@ func (s *FileService) DownloadLog(logID string) (*models.DownloadResult, error)
 	}, nil
 }
 
-// GetFileStructure 获取文件结构（带缓存）
+// GetFileStructure 获取文件结构，读取导入时保存的文件树（带缓存）
 func (s *FileService) GetFileStructure(logID string) (*models.FileNode, error) {
 	// 尝试从缓存获取
 	cacheKey := "tree:" + logID
@@ -96,12 +128,15 @@ func (s *FileService) GetFileStructure(logID string) (*models.FileNode, error) {
 		return nil, fmt.Errorf(models.ErrLogNotFound)
 	}
 
-	fileNode, err := s.fileUtil.BuildTree(extractPath, "")
+	result, err := s.loadFileTree(logID)
 	if err != nil {
 		return nil, err
 	}
-
-	result := s.convertFileNode(fileNode)
+	index, err := s.indexRepo.GetIndex(logID)
+	if err != nil {
+		return nil, err
+	}
+	applyIndex(result, index)
 	
 	// 存入缓存
 	s.treeCache.Set(cacheKey, result)
@@ -115,36 +150,62 @@ func (s *FileService) InvalidateTreeCache(logID string) {
 	s.treeCache.Delete(cacheKey)
 }
 
-// convertFileNode 转换 fileutil.FileNode 到 models.FileNode
-func (s *FileService) convertFileNode(fn *fileutil.FileNode) *models.FileNode {
-	if fn == nil {
-		return nil
+// applyIndex 为文件树中的文件附加文件索引中的信息
+func applyIndex(node *models.FileNode, index map[string]models.FileIndex) {
+	if entry, ok := index[node.Path]; ok && node.Type == "file" {
+		node.ContentType = entry.ContentType
+		node.Encoding = entry.Encoding
+		node.Compression = entry.Compression
+		node.Lines = entry.Lines
+		node.Errors = entry.Errors
+		node.Warnings = entry.Warnings
 	}
+	for _, child := range node.Children {
+		applyIndex(child, index)
+	}
+}
 
-	node := &models.FileNode{
-		Name: fn.Name,
-		Path: fn.Path,
-		Type: fn.Type,
-		Size: fn.Size,
+// GetFileContent 获取文件内容
+func (s *FileService) GetFileContent(logID, filePath string, opts models.FileContentOptions) (*models.FileContent, error) {
+	fullPath, err := s.ResolvePath(logID, filePath)
+	if err != nil {
+		return nil, err
 	}
 
-	if fn.Children != nil {
-		node.Children = make([]*models.FileNode, len(fn.Children))
-		for i, child := range fn.Children {
-			node.Children[i] = s.convertFileNode(child)
-		}
+	fileInfo, err := s.fileUtil.Stat(fullPath)
+	if err != nil || fileInfo.IsDir() {
+		return nil, fmt.Errorf(models.ErrFileNotFound)
 	}
 
-	return node
-}
+	compression := s.fileUtil.Compression(fullPath)
 
-// GetFileContent 获取文件内容
-func (s *FileService) GetFileContent(logID, filePath string) (*models.FileContent, error) {
-	extractPath := filepath.Join(s.cfg.Storage.ExtractDir, logID)
-	fullPath := filepath.Join(extractPath, filePath)
+	// 二进制文件返回分页的十六进制视图
+	if binaryType, binary := s.fileUtil.SniffBinary(fullPath); binary {
+		size := fileInfo.Size()
+		if compression != "" {
+			// 解压后的大小未知
+			size = -1
+		}
+		result, err := s.getHexDumpPage(fullPath, size, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.BinaryType = binaryType
+		result.Compression = compression
+		return result, nil
+	}
 
-	if _, err := os.Stat(fullPath); os.IsNotExist(err) || !s.fileUtil.IsFile(fullPath) {
-		return nil, fmt.Errorf(models.ErrFileNotFound)
+	// 分页、过滤、大文件或压缩文件时逐行流式读取，不受预览大小限制
+	if compression != "" {
+		result, err := s.getFileContentPage(logID, filePath, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.Compression = compression
+		return result, nil
+	}
+	if opts.Limit > 0 || opts.HasFilter() || fileInfo.Size() > s.cfg.Storage.MaxPreview {
+		return s.getFileContentPage(logID, filePath, opts)
 	}
 
 	content, err := s.fileUtil.ReadFileContent(fullPath)
@@ -156,16 +217,530 @@ func (s *FileService) GetFileContent(logID, filePath string) (*models.FileConten
 		}, nil
 	}
 
+	content = s.redaction.Redact(content, opts.Raw)
 	fileType := s.fileUtil.DetectFileType(filePath, content)
-	formattedContent := s.fileUtil.FormatContent(content, fileType)
+	formattedContent, diagnostics := s.fileUtil.FormatContent(content, fileType)
+
+	result := &models.FileContent{
+		Content:     formattedContent,
+		Type:        fileType,
+		Size:        len(content),
+		Diagnostics: convertDiagnostics(diagnostics, nil),
+	}
+
+	if opts.Parse {
+		lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+		lineParser, err := s.NewLineParser(opts.Format, lines)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Format = lineParser.Format()
+		result.Records = []models.LogRecord{}
+		for _, ev := range lineParser.GroupLines(1, lines) {
+			result.Records = append(result.Records, s.convertEvent(lineParser, ev, true))
+		}
+	}
+
+	return result, nil
+}
+
+// getFileContentPage 逐行读取文件，先按级别和时间过滤，再对结果分页
+func (s *FileService) getFileContentPage(logID, filePath string, opts models.FileContentOptions) (*models.FileContent, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	offset := opts.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	var lineParser *logparser.LineParser
+	if opts.Parse || opts.HasFilter() {
+		sample, err := s.HeadLines(logID, filePath, detectSampleLines)
+		if err != nil {
+			return nil, err
+		}
+		if lineParser, err = s.NewLineParser(opts.Format, sample); err != nil {
+			return nil, err
+		}
+	}
+
+	levels := make(map[string]bool, len(opts.Levels))
+	for _, level := range opts.Levels {
+		levels[level] = true
+	}
+
+	result := &models.FileContent{
+		Paginated: true,
+		Offset:    offset,
+		Limit:     limit,
+		Filtered:  opts.HasFilter(),
+	}
+	var pageLines []string
+	var lastTime time.Time
+	matched := 0
+
+	// addEvent 过滤整个事件，按行分页；只对当前页完整脱敏，其他行只跟踪跨行的块（如私钥）
+	stream := s.redaction.Stream(opts.Raw)
+	addEvent := func(ev *logparser.Event) {
+		if lineParser != nil {
+			// 没有时间戳的事件沿用上一事件的时间
+			if ev.Record.HasTime() {
+				lastTime = ev.Record.Time
+			}
+			if !matchFilter(ev.Record, lastTime, levels, opts) {
+				for _, line := range ev.Lines {
+					stream.Blocks(line)
+				}
+				return
+			}
+		}
+
+		first := matched
+		matched += len(ev.Lines)
+		for i, line := range ev.Lines {
+			if first+i < offset || first+i >= offset+limit {
+				stream.Blocks(line)
+				continue
+			}
+			pageLines = append(pageLines, stream.Redact(line))
+			result.LineNumbers = append(result.LineNumbers, ev.StartLine()+i)
+		}
+		// 记录只在事件首行所在的页返回
+		if opts.Parse && first >= offset {
+			result.Records = append(result.Records, s.convertEvent(lineParser, ev, opts.Raw))
+		}
+	}
+
+	var grouper *logparser.Grouper
+	if lineParser != nil {
+		grouper = lineParser.NewGrouper()
+	}
+	err := s.ForEachLine(logID, filePath, func(lineNo int, line string) error {
+		if grouper == nil {
+			addEvent(&logparser.Event{Record: &logparser.Record{Line: lineNo}, Lines: []string{line}})
+		} else if ev := grouper.Add(lineNo, line); ev != nil {
+			addEvent(ev)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if grouper != nil {
+		if ev := grouper.Flush(); ev != nil {
+			addEvent(ev)
+		}
+	}
+
+	content := strings.Join(pageLines, "\n")
+	result.Type = s.fileUtil.DetectFileType(fileutil.TrimCompressionExt(filePath), content)
+	formatted, diagnostics := s.fileUtil.FormatContent(content, result.Type)
+	result.Content = formatted
+	result.Size = len(content)
+	result.TotalLines = matched
+	// JSON Lines 逐行校验；其他格式只有当前页包含完整文件时语法错误才有意义
+	if result.Type == "jsonl" || (offset == 0 && matched <= limit && !result.Filtered) {
+		result.Diagnostics = convertDiagnostics(diagnostics, result.LineNumbers)
+	}
+	if lineParser != nil && opts.Parse {
+		result.Format = lineParser.Format()
+	}
+	return result, nil
+}
+
+// hexRedactContext 十六进制视图脱敏时在页首和页尾前后多读取的字节数，跨页的敏感内容也能被识别
+const hexRedactContext = 1024
+
+// getHexDumpPage 生成二进制文件的十六进制视图，每行16字节，按行分页；size<0时读完文件计算总行数
+// 未请求原始内容时，可打印文本中命中脱敏规则的字节在十六进制和ASCII两栏中都被遮盖
+func (s *FileService) getHexDumpPage(fullPath string, size int64, opts models.FileContentOptions) (*models.FileContent, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	offset := opts.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	start := int64(offset) * fileutil.HexBytesPerLine
+	if size >= 0 && start > size {
+		start = size
+	}
+	var margin int64
+	if !opts.Raw {
+		margin = hexRedactContext
+	}
+	readFrom := start - margin
+	if readFrom < 0 {
+		readFrom = 0
+	}
+
+	var file io.ReadCloser
+	var skipped int64
+	if size >= 0 {
+		// 大小已知时直接跳到页首，压缩保存的文件只解压所在的帧
+		raw, err := s.fileUtil.OpenRaw(fullPath)
+		if err != nil {
+			return nil, err
+		}
+		if skipped, err = raw.Seek(readFrom, io.SeekStart); err != nil {
+			raw.Close()
+			return nil, err
+		}
+		file = raw
+	} else {
+		decompressed, err := s.fileUtil.OpenFile(fullPath)
+		if err != nil {
+			return nil, err
+		}
+		skipped, err = io.CopyN(io.Discard, decompressed, readFrom)
+		if err != nil && err != io.EOF {
+			decompressed.Close()
+			return nil, err
+		}
+		file = decompressed
+	}
+	defer file.Close()
+
+	// 页首之前的上下文，文件在此之前已结束时为0
+	before := start - readFrom
+	if skipped < readFrom {
+		before = 0
+	}
+	data := make([]byte, before+int64(limit)*fileutil.HexBytesPerLine+margin)
+	n, err := io.ReadFull(file, data)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	data = data[:n]
+	masked := s.redaction.MaskBytes(data, opts.Raw)
 
+	pageStart := int(before)
+	if pageStart > n {
+		pageStart = n
+	}
+	pageEnd := pageStart + limit*fileutil.HexBytesPerLine
+	if pageEnd > n {
+		pageEnd = n
+	}
+
+	rows := make([]string, 0, limit)
+	lineNumbers := make([]int, 0, limit)
+	pos := skipped + int64(pageStart)
+	for i := pageStart; i < pageEnd; i += fileutil.HexBytesPerLine {
+		j := i + fileutil.HexBytesPerLine
+		if j > pageEnd {
+			j = pageEnd
+		}
+		var rowMask []bool
+		if masked != nil {
+			rowMask = masked[i:j]
+		}
+		rows = append(rows, fileutil.HexDumpLine(pos, data[i:j], rowMask))
+		lineNumbers = append(lineNumbers, int(pos/fileutil.HexBytesPerLine)+1)
+		pos += int64(j - i)
+	}
+
+	total := size
+	if total < 0 {
+		rest, err := io.Copy(io.Discard, file)
+		if err != nil {
+			return nil, err
+		}
+		total = skipped + int64(n) + rest
+	}
+
+	content := strings.Join(rows, "\n")
 	return &models.FileContent{
-		Content: formattedContent,
-		Type:    fileType,
-		Size:    len(content),
+		Content:     content,
+		Type:        "hex",
+		Size:        len(content),
+		Paginated:   true,
+		TotalLines:  int((total + fileutil.HexBytesPerLine - 1) / fileutil.HexBytesPerLine),
+		Offset:      offset,
+		Limit:       limit,
+		LineNumbers: lineNumbers,
 	}, nil
 }
 
+// convertDiagnostics 转换语法错误，lineNumbers 不为空时将页内行号映射为原文件行号
+func convertDiagnostics(diagnostics []fileutil.Diagnostic, lineNumbers []int) []models.Diagnostic {
+	if len(diagnostics) == 0 {
+		return nil
+	}
+	result := make([]models.Diagnostic, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		line := d.Line
+		if line > 0 && line <= len(lineNumbers) {
+			line = lineNumbers[line-1]
+		}
+		result = append(result, models.Diagnostic{Line: line, Column: d.Column, Message: d.Message})
+	}
+	return result
+}
+
+// matchFilter 判断记录是否满足级别和时间过滤条件，effectiveTime为记录自身或继承的时间
+func matchFilter(rec *logparser.Record, effectiveTime time.Time, levels map[string]bool, opts models.FileContentOptions) bool {
+	if len(levels) > 0 && !levels[rec.Level] {
+		return false
+	}
+	if !opts.Since.IsZero() || !opts.Until.IsZero() {
+		if effectiveTime.IsZero() {
+			return false
+		}
+		if !opts.Since.IsZero() && effectiveTime.Before(opts.Since) {
+			return false
+		}
+		if !opts.Until.IsZero() && effectiveTime.After(opts.Until) {
+			return false
+		}
+	}
+	return true
+}
+
+// HeadLines 读取文件的前n行，用于格式检测
+func (s *FileService) HeadLines(logID, filePath string, n int) ([]string, error) {
+	lines := make([]string, 0, n)
+	err := s.ForEachLine(logID, filePath, func(lineNo int, line string) error {
+		lines = append(lines, line)
+		if len(lines) >= n {
+			return errStopReading
+		}
+		return nil
+	})
+	if err != nil && err != errStopReading {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// ReadLines 读取文件的前maxLines行，超出时或压缩文件解压后超过大小限制时truncated为true
+func (s *FileService) ReadLines(logID, filePath string, maxLines int) (lines []string, truncated bool, err error) {
+	scanner, closer, err := s.OpenLines(logID, filePath)
+	if err != nil {
+		return nil, false, err
+	}
+	defer closer.Close()
+
+	for scanner.Next() {
+		if len(lines) >= maxLines {
+			return lines, true, nil
+		}
+		lines = append(lines, scanner.Line())
+	}
+	return lines, fileutil.IsTruncated(closer), scanner.Err()
+}
+
+// ForEachEvent 自动检测格式，将文件按多行事件（堆栈、panic等合并为一个事件）逐个回调
+func (s *FileService) ForEachEvent(logID, filePath string, fn func(ev *logparser.Event) error) error {
+	sample, err := s.HeadLines(logID, filePath, detectSampleLines)
+	if err != nil {
+		return err
+	}
+	lineParser, err := s.NewLineParser("", sample)
+	if err != nil {
+		return err
+	}
+
+	grouper := lineParser.NewGrouper()
+	err = s.ForEachLine(logID, filePath, func(lineNo int, line string) error {
+		if ev := grouper.Add(lineNo, line); ev != nil {
+			return fn(ev)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if ev := grouper.Flush(); ev != nil {
+		return fn(ev)
+	}
+	return nil
+}
+
+// EventsAt 返回包含指定行的事件（按行号索引），读到最后一个指定行所在的事件为止
+func (s *FileService) EventsAt(logID, filePath string, lines []int) (map[int]*logparser.Event, error) {
+	events := make(map[int]*logparser.Event, len(lines))
+	if len(lines) == 0 {
+		return events, nil
+	}
+	wanted := append([]int(nil), lines...)
+	sort.Ints(wanted)
+
+	err := s.ForEachEvent(logID, filePath, func(ev *logparser.Event) error {
+		for len(wanted) > 0 && wanted[0] <= ev.EndLine() {
+			if wanted[0] >= ev.StartLine() {
+				events[wanted[0]] = ev
+			}
+			wanted = wanted[1:]
+		}
+		if len(wanted) == 0 {
+			return errStopReading
+		}
+		return nil
+	})
+	if err != nil && err != errStopReading {
+		return nil, err
+	}
+	return events, nil
+}
+
+// LogFormats 返回支持的日志格式名称
+func (s *FileService) LogFormats() []string {
+	return s.parsers.Names()
+}
+
+// SupportsLogFormat 是否支持指定的日志格式
+func (s *FileService) SupportsLogFormat(format string) bool {
+	_, ok := s.parsers.Get(format)
+	return ok
+}
+
+// NewLineParser 创建行解析器，format为空时根据样本行自动检测格式
+func (s *FileService) NewLineParser(format string, sample []string) (*logparser.LineParser, error) {
+	if format != "" {
+		parser, ok := s.parsers.Get(format)
+		if !ok {
+			return nil, fmt.Errorf("%s: %s", models.ErrUnknownLogFormat, format)
+		}
+		return s.parsers.NewLineParser(parser), nil
+	}
+	return s.parsers.NewLineParser(s.parsers.Detect(sample)), nil
+}
+
+// convertRecord 转换 logparser.Record 到 models.LogRecord
+func convertRecord(rec *logparser.Record) models.LogRecord {
+	record := models.LogRecord{
+		Line:      rec.Line,
+		Level:     rec.Level,
+		Component: rec.Component,
+		PID:       rec.PID,
+		Message:   rec.Message,
+		Fields:    rec.Fields,
+	}
+	if rec.HasTime() {
+		record.Timestamp = rec.Time.Format(time.RFC3339Nano)
+	}
+	if len(record.Fields) == 0 {
+		record.Fields = nil
+	}
+	return record
+}
+
+// convertEvent 将多行事件转换为一条记录，续行追加到消息正文
+// raw为false时先对事件脱敏（内容已脱敏时传true）
+func (s *FileService) convertEvent(lineParser *logparser.LineParser, ev *logparser.Event, raw bool) models.LogRecord {
+	rec := ev.Record
+	lines := s.redaction.RedactLines(ev.Lines, raw)
+	if lines[0] != ev.Lines[0] {
+		rec = lineParser.Parse(ev.StartLine(), lines[0])
+	}
+	record := convertRecord(rec)
+	if len(lines) > 1 {
+		record.EndLine = ev.EndLine()
+		record.Message += "\n" + strings.Join(lines[1:], "\n")
+	}
+	return record
+}
+
+// ResolvePath 将日志内的相对路径解析为磁盘上的绝对路径，拒绝越出日志目录的路径
+func (s *FileService) ResolvePath(logID, filePath string) (string, error) {
+	extractPath := filepath.Clean(filepath.Join(s.cfg.Storage.ExtractDir, logID))
+	fullPath := filepath.Join(extractPath, filePath)
+
+	if fullPath != extractPath && !strings.HasPrefix(fullPath, extractPath+string(os.PathSeparator)) {
+		return "", fmt.Errorf(models.ErrFileNotFound)
+	}
+	return fullPath, nil
+}
+
+// ForEachLine 逐行读取日志中的文件
+func (s *FileService) ForEachLine(logID, filePath string, fn func(lineNo int, line string) error) error {
+	fullPath, err := s.ResolvePath(logID, filePath)
+	if err != nil {
+		return err
+	}
+	if !s.fileUtil.IsFile(fullPath) {
+		return fmt.Errorf(models.ErrFileNotFound)
+	}
+	return s.fileUtil.ForEachFileLine(fullPath, fn)
+}
+
+// HashFile 计算日志中文件内容的SHA-256
+// 结果缓存在文件索引中，重新导入或删除日志时随索引一起清除
+func (s *FileService) HashFile(logID, filePath string) (string, error) {
+	fullPath, err := s.ResolvePath(logID, filePath)
+	if err != nil {
+		return "", err
+	}
+	if !s.fileUtil.IsFile(fullPath) {
+		return "", fmt.Errorf(models.ErrFileNotFound)
+	}
+
+	indexPath := filepath.ToSlash(filepath.Clean(filePath))
+	if s.indexRepo != nil {
+		hash, err := s.indexRepo.GetHash(logID, indexPath)
+		if err != nil {
+			return "", err
+		}
+		if hash != "" {
+			return hash, nil
+		}
+	}
+
+	hash, err := s.fileUtil.HashFile(fullPath)
+	if err != nil {
+		return "", err
+	}
+	if s.indexRepo != nil {
+		if err := s.indexRepo.SetHash(logID, indexPath, hash); err != nil {
+			return "", err
+		}
+	}
+	return hash, nil
+}
+
+// OpenLines 打开日志中的文件并返回逐行读取器，调用方负责关闭
+func (s *FileService) OpenLines(logID, filePath string) (*fileutil.LineScanner, io.Closer, error) {
+	return s.OpenLinesAt(logID, filePath, 0, 0)
+}
+
+// OpenLinesAt 从第 lineNo 行末尾（字节偏移 offset，取自 LineScanner.Offset）继续逐行读取，调用方负责关闭
+func (s *FileService) OpenLinesAt(logID, filePath string, offset int64, lineNo int) (*fileutil.LineScanner, io.Closer, error) {
+	fullPath, err := s.ResolvePath(logID, filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !s.fileUtil.IsFile(fullPath) {
+		return nil, nil, fmt.Errorf(models.ErrFileNotFound)
+	}
+
+	file, err := s.fileUtil.OpenFileAt(fullPath, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fileutil.NewLineScannerAt(file, offset, lineNo), file, nil
+}
+
+// WalkTextFiles 遍历日志中的所有文本文件（跳过二进制文件和超过大小限制的文件）
+func (s *FileService) WalkTextFiles(logID string, fn func(relPath string, size int64) error) error {
+	extractPath := filepath.Join(s.cfg.Storage.ExtractDir, logID)
+	if _, err := os.Stat(extractPath); os.IsNotExist(err) {
+		return fmt.Errorf(models.ErrLogNotFound)
+	}
+
+	return s.fileUtil.WalkFiles(extractPath, func(relPath, fullPath string, info os.FileInfo) error {
+		if info.Size() > s.cfg.Storage.MaxFileSize || !s.fileUtil.IsTextFile(fullPath) {
+			return nil
+		}
+		return fn(relPath, info.Size())
+	})
+}
+
 // DeleteLogFiles 删除日志文件
 func (s *FileService) DeleteLogFiles(logID string) error {
 	extractPath := filepath.Join(s.cfg.Storage.ExtractDir, logID)
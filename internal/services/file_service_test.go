@@ -0,0 +1,284 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"logview-goversion/internal/config"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/repository"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHexDumpRedaction(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	cfg.Storage.ZipDir = filepath.Join(dir, "zip")
+	cfg.Storage.ExtractDir = filepath.Join(dir, "extracted")
+	cfg.Redaction.Enabled = true
+
+	redaction, err := NewRedactionService(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewFileService(cfg, redaction, nil, nil)
+
+	// 口令跨越第2、3行（每行16字节），第3行单独成页时也应被遮盖
+	var data bytes.Buffer
+	data.Write(make([]byte, 20))
+	data.WriteString("password=hunter2secret")
+	data.Write(make([]byte, 20))
+	logDir := filepath.Join(cfg.Storage.ExtractDir, "log1")
+	os.MkdirAll(logDir, 0755)
+	if err := os.WriteFile(filepath.Join(logDir, "data.bin"), data.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		opts   models.FileContentOptions
+		hidden bool
+		key    bool // 页中包含键名
+	}{
+		{name: "整个文件", opts: models.FileContentOptions{}, hidden: true, key: true},
+		{name: "口令后半部分所在的页", opts: models.FileContentOptions{Offset: 2, Limit: 1}, hidden: true},
+		{name: "原始内容", opts: models.FileContentOptions{Raw: true}, hidden: false, key: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.GetFileContent("log1", "data.bin", tt.opts)
+			if err != nil {
+				t.Fatalf("GetFileContent() 错误: %v", err)
+			}
+			if result.Type != "hex" {
+				t.Fatalf("Type = %s, 期望 hex", result.Type)
+			}
+			// "secr" 的十六进制和ASCII
+			leaked := strings.Contains(result.Content, "73 65 63 72") || strings.Contains(result.Content, "secr")
+			if leaked == tt.hidden {
+				t.Errorf("口令可见 = %v, 内容:\n%s", leaked, result.Content)
+			}
+			if tt.key && !strings.Contains(result.Content, "password=") {
+				t.Errorf("键名不应被遮盖:\n%s", result.Content)
+			}
+		})
+	}
+}
+
+func TestHashFileCache(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	cfg.Storage.ZipDir = filepath.Join(dir, "zip")
+	cfg.Storage.ExtractDir = filepath.Join(dir, "extracted")
+	cfg.Storage.MaxFileSize = 1 << 20
+
+	logRepo, err := repository.NewLogRepository(filepath.Join(dir, "logs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logRepo.Close()
+	indexRepo, err := repository.NewFileIndexRepository(logRepo.DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeRepo, err := repository.NewFileTreeRepository(logRepo.DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewFileService(cfg, nil, indexRepo, treeRepo)
+
+	logDir := filepath.Join(cfg.Storage.ExtractDir, "log1", "sub")
+	os.MkdirAll(logDir, 0755)
+	file := filepath.Join(logDir, "a.log")
+	write := func(content string) {
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hash := func() string {
+		h, err := s.HashFile("log1", "sub/a.log")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	write("first\n")
+	if err := s.OnLogImported("log1"); err != nil {
+		t.Fatal(err)
+	}
+	first := hash()
+
+	// 导入后文件不会变化，第二次直接使用缓存
+	write("other\n")
+	if got := hash(); got != first {
+		t.Errorf("缓存的哈希 = %s, 期望 %s", got, first)
+	}
+
+	// 重新导入后重新计算
+	if err := s.OnLogImported("log1"); err != nil {
+		t.Fatal(err)
+	}
+	second := hash()
+	if second == first {
+		t.Error("重新导入后哈希未更新")
+	}
+
+	if err := s.OnLogDeleted("log1"); err != nil {
+		t.Fatal(err)
+	}
+	if cached, err := indexRepo.GetHash("log1", "sub/a.log"); err != nil || cached != "" {
+		t.Errorf("删除日志后缓存 = %q, %v", cached, err)
+	}
+}
+
+// newFileTestService 在临时目录中写入日志 log1 的文件并创建文件服务
+func newFileTestService(t *testing.T, files map[string]string) *FileService {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	cfg.Storage.ZipDir = filepath.Join(dir, "zip")
+	cfg.Storage.ExtractDir = filepath.Join(dir, "extracted")
+	cfg.Storage.MaxFileSize = 1 << 20
+	cfg.Storage.MaxPreview = 1 << 20
+
+	for name, content := range files {
+		path := filepath.Join(cfg.Storage.ExtractDir, "log1", filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewFileService(cfg, nil, nil, nil)
+}
+
+// 过滤和分页以多行事件为单位，堆栈随首行一起返回
+func TestGetFileContentEvents(t *testing.T) {
+	content := strings.Join([]string{
+		"2024-01-01T00:00:01Z INFO start",
+		"2024-01-01T00:00:02Z ERROR request failed",
+		"java.lang.IllegalStateException: bad state",
+		"\tat com.example.Handler.run(Handler.java:10)",
+		"2024-01-01T00:00:03Z WARN slow",
+		"2024-01-01T00:00:04Z ERROR handler crashed",
+		"goroutine 7 [running]:",
+		"main.handler()",
+	}, "\n")
+	s := newFileTestService(t, map[string]string{"app.log": content})
+
+	tests := []struct {
+		name    string
+		opts    models.FileContentOptions
+		lines   []int
+		total   int
+		records [][2]int // 每条记录的 起始行号,结束行号
+	}{
+		{
+			name:  "级别过滤返回整个事件",
+			opts:  models.FileContentOptions{Levels: []string{"ERROR"}, Limit: 100},
+			lines: []int{2, 3, 4, 6, 7, 8},
+			total: 6,
+		},
+		{
+			name:    "解析记录包含续行",
+			opts:    models.FileContentOptions{Levels: []string{"ERROR"}, Limit: 100, Parse: true},
+			lines:   []int{2, 3, 4, 6, 7, 8},
+			total:   6,
+			records: [][2]int{{2, 4}, {6, 8}},
+		},
+		{
+			name:    "记录只在事件首行所在的页返回",
+			opts:    models.FileContentOptions{Levels: []string{"ERROR"}, Offset: 2, Limit: 2, Parse: true},
+			lines:   []int{4, 6},
+			total:   6,
+			records: [][2]int{{6, 8}},
+		},
+		{
+			name:  "续行沿用首行的时间",
+			opts:  models.FileContentOptions{Since: time.Date(2024, 1, 1, 0, 0, 2, 0, time.UTC), Until: time.Date(2024, 1, 1, 0, 0, 2, 0, time.UTC), Limit: 100},
+			lines: []int{2, 3, 4},
+			total: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.GetFileContent("log1", "app.log", tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result.LineNumbers, tt.lines) {
+				t.Errorf("行号 = %v, 期望 %v", result.LineNumbers, tt.lines)
+			}
+			if result.TotalLines != tt.total {
+				t.Errorf("TotalLines = %d, 期望 %d", result.TotalLines, tt.total)
+			}
+			var records [][2]int
+			for _, r := range result.Records {
+				records = append(records, [2]int{r.Line, r.EndLine})
+			}
+			if !reflect.DeepEqual(records, tt.records) {
+				t.Errorf("记录 = %v, 期望 %v", records, tt.records)
+			}
+		})
+	}
+}
+
+func TestGetFileContentFilter(t *testing.T) {
+	var lines []string
+	levels := []string{"INFO", "WARN", "ERROR"}
+	for i := 0; i < 12; i++ {
+		lines = append(lines, fmt.Sprintf("2024-01-01T00:00:%02dZ %s message %d", i, levels[i%3], i))
+	}
+	lines = append(lines, "no timestamp here")
+	s := newFileTestService(t, map[string]string{
+		"app.log":   strings.Join(lines, "\n"),
+		"plain.txt": "first\nsecond\n",
+	})
+	at := func(sec int) time.Time { return time.Date(2024, 1, 1, 0, 0, sec, 0, time.UTC) }
+
+	tests := []struct {
+		name  string
+		file  string
+		opts  models.FileContentOptions
+		lines []int
+		total int
+	}{
+		{name: "单个级别", file: "app.log", opts: models.FileContentOptions{Levels: []string{"ERROR"}, Limit: 100}, lines: []int{3, 6, 9, 12}, total: 4},
+		{name: "多个级别", file: "app.log", opts: models.FileContentOptions{Levels: []string{"WARN", "ERROR"}, Limit: 3}, lines: []int{2, 3, 5}, total: 8},
+		{name: "过滤后分页", file: "app.log", opts: models.FileContentOptions{Levels: []string{"WARN", "ERROR"}, Offset: 6, Limit: 3}, lines: []int{11, 12}, total: 8},
+		{name: "时间范围包含两端", file: "app.log", opts: models.FileContentOptions{Since: at(3), Until: at(5), Limit: 100}, lines: []int{4, 5, 6}, total: 3},
+		{name: "没有时间戳的行沿用上一行的时间", file: "app.log", opts: models.FileContentOptions{Since: at(11), Limit: 100}, lines: []int{12, 13}, total: 2},
+		{name: "级别和时间同时过滤", file: "app.log", opts: models.FileContentOptions{Levels: []string{"INFO"}, Until: at(6), Limit: 100}, lines: []int{1, 4, 7}, total: 3},
+		{name: "偏移超出匹配数", file: "app.log", opts: models.FileContentOptions{Levels: []string{"ERROR"}, Offset: 10, Limit: 5}, total: 4},
+		{name: "没有时间戳的文件不匹配时间范围", file: "plain.txt", opts: models.FileContentOptions{Since: at(0), Limit: 100}, total: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.GetFileContent("log1", tt.file, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !result.Filtered || !result.Paginated {
+				t.Errorf("Filtered/Paginated = %v/%v, 期望 true/true", result.Filtered, result.Paginated)
+			}
+			if !reflect.DeepEqual(result.LineNumbers, tt.lines) {
+				t.Errorf("行号 = %v, 期望 %v", result.LineNumbers, tt.lines)
+			}
+			if result.TotalLines != tt.total {
+				t.Errorf("TotalLines = %d, 期望 %d", result.TotalLines, tt.total)
+			}
+			if got := strings.Count(result.Content, "\n") + 1; len(tt.lines) > 0 && got != len(tt.lines) {
+				t.Errorf("内容行数 = %d, 期望 %d", got, len(tt.lines))
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"fmt"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/pkg/fileutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// SaveFileTree 遍历日志目录计算文件树和目录汇总（总大小、文件数），保存后不再扫描磁盘
+func (s *FileService) SaveFileTree(logID string) (*models.FileNode, error) {
+	extractPath := filepath.Join(s.cfg.Storage.ExtractDir, logID)
+	if _, err := os.Stat(extractPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf(models.ErrLogNotFound)
+	}
+
+	tree, err := s.fileUtil.BuildTree(extractPath, "")
+	if err != nil {
+		return nil, err
+	}
+	root := convertTreeNode(tree)
+
+	var nodes []models.FileNode
+	flattenTree(root, &nodes)
+	if err := s.treeRepo.ReplaceTree(logID, nodes); err != nil {
+		return nil, fmt.Errorf("保存文件树失败: %w", err)
+	}
+	return root, nil
+}
+
+// loadFileTree 读取保存的文件树，没有保存过时（文件树持久化之前导入的日志）计算并保存
+func (s *FileService) loadFileTree(logID string) (*models.FileNode, error) {
+	nodes, err := s.treeRepo.GetTree(logID)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return s.SaveFileTree(logID)
+	}
+
+	// 按路径排序后父目录总在子项之前
+	byPath := make(map[string]*models.FileNode, len(nodes))
+	var root *models.FileNode
+	for i := range nodes {
+		node := &nodes[i]
+		if node.Type == "directory" {
+			node.Children = []*models.FileNode{}
+		}
+		byPath[node.Path] = node
+		if node.Path == "" {
+			root = node
+			continue
+		}
+		parentPath := path.Dir(node.Path)
+		if parentPath == "." {
+			parentPath = ""
+		}
+		if parent, ok := byPath[parentPath]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+	if root == nil {
+		return s.SaveFileTree(logID)
+	}
+
+	for _, node := range byPath {
+		sortFileNodes(node.Children)
+	}
+	return root, nil
+}
+
+// convertTreeNode 转换 fileutil.FileNode 到 models.FileNode，并计算目录的总大小、直接子项数和文件数
+func convertTreeNode(fn *fileutil.FileNode) *models.FileNode {
+	node := &models.FileNode{
+		Name:    fn.Name,
+		Path:    filepath.ToSlash(fn.Path),
+		Type:    fn.Type,
+		Size:    fn.Size,
+		Binary:  fn.Binary,
+		ModTime: fn.ModTime,
+	}
+	if fn.Type != "directory" {
+		return node
+	}
+
+	node.Children = make([]*models.FileNode, len(fn.Children))
+	node.ChildCount = len(fn.Children)
+	for i, child := range fn.Children {
+		c := convertTreeNode(child)
+		node.Children[i] = c
+		node.Size += c.Size
+		if c.Type == "directory" {
+			node.FileCount += c.FileCount
+		} else {
+			node.FileCount++
+		}
+	}
+	return node
+}
+
+// flattenTree 将文件树展开为节点列表
+func flattenTree(node *models.FileNode, nodes *[]models.FileNode) {
+	flat := *node
+	flat.Children = nil
+	*nodes = append(*nodes, flat)
+	for _, child := range node.Children {
+		flattenTree(child, nodes)
+	}
+}
+
+// sortFileNodes 排序文件节点（目录优先，然后按名称），与 fileutil 构建文件树时的顺序一致
+func sortFileNodes(nodes []*models.FileNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Type != nodes[j].Type {
+			return nodes[i].Type == "directory"
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+}
+
+// BundleDiskSize 日志解压目录在磁盘上实际占用的大小，压缩保存时小于 BundleSize
+func (s *FileService) BundleDiskSize(logID string) (int64, error) {
+	return s.fileUtil.Storage().DiskSize(filepath.Join(s.cfg.Storage.ExtractDir, logID))
+}
+
+// BundleSize 日志解压后的总大小，取自保存的文件树
+func (s *FileService) BundleSize(logID string) (int64, error) {
+	nodes, err := s.treeRepo.GetNodes(logID, []string{""})
+	if err != nil {
+		return 0, err
+	}
+	if root, ok := nodes[""]; ok {
+		return root.Size, nil
+	}
+	root, err := s.SaveFileTree(logID)
+	if err != nil {
+		return 0, err
+	}
+	return root.Size, nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveFileTree(t *testing.T) {
+	s, logDir := newIndexedFileTestService(t, 1<<20, map[string]string{
+		"a.log":             "12345",
+		"var/log/syslog":    "1234567890",
+		"var/log/old/app.1": "123",
+		"var/empty.txt":     "",
+	})
+	if err := os.MkdirAll(filepath.Join(logDir, "tmp"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.OnLogImported("log1"); err != nil {
+		t.Fatal(err)
+	}
+
+	type aggregate struct {
+		size                  int64
+		childCount, fileCount int
+	}
+	check := func(name string, want map[string]aggregate) {
+		t.Helper()
+		s.InvalidateTreeCache("log1")
+		tree, err := s.GetFileStructure("log1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for path, w := range want {
+			node := findNode(tree, path)
+			if node == nil {
+				t.Errorf("%s: 文件树中没有 %q", name, path)
+				continue
+			}
+			if got := (aggregate{node.Size, node.ChildCount, node.FileCount}); got != w {
+				t.Errorf("%s: %q = %+v, 期望 %+v", name, path, got, w)
+			}
+		}
+	}
+
+	check("导入后", map[string]aggregate{
+		"":            {18, 3, 4},
+		"var":         {13, 2, 3},
+		"var/log":     {13, 2, 2},
+		"var/log/old": {3, 1, 1},
+		"tmp":         {0, 0, 0},
+		"a.log":       {5, 0, 0},
+	})
+	if size, err := s.BundleSize("log1"); err != nil || size != 18 {
+		t.Errorf("BundleSize() = %d, %v, 期望 18", size, err)
+	}
+
+	// 导入后不再扫描磁盘，缓存失效后仍读取保存的文件树
+	if err := os.WriteFile(filepath.Join(logDir, "new.log"), []byte("1234"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	check("磁盘变化后", map[string]aggregate{"": {18, 3, 4}})
+	if tree, _ := s.GetFileStructure("log1"); findNode(tree, "new.log") != nil {
+		t.Error("保存的文件树中出现了导入后新增的文件")
+	}
+
+	// 重新导入后重新计算
+	if err := s.OnLogImported("log1"); err != nil {
+		t.Fatal(err)
+	}
+	check("重新导入后", map[string]aggregate{"": {22, 4, 5}, "new.log": {4, 0, 0}})
+
+	// 删除保存的文件树后（与文件树持久化之前导入的日志相同）读取时重新计算并保存
+	if err := s.OnLogDeleted("log1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(logDir, "a.log")); err != nil {
+		t.Fatal(err)
+	}
+	check("删除后", map[string]aggregate{"": {17, 3, 4}})
+	if size, err := s.BundleSize("log1"); err != nil || size != 17 {
+		t.Errorf("BundleSize() = %d, %v, 期望 17", size, err)
+	}
+
+	if _, err := s.GetFileStructure("missing"); err == nil {
+		t.Error("日志不存在时应返回错误")
+	}
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"fmt"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/pkg/gostack"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxGroupIDs 每组返回的goroutine ID数量
+	maxGroupIDs = 100
+	// longLockWaitMinutes 等待锁超过该时长时提示可能的死锁
+	longLockWaitMinutes = 5
+	// lockContentionCount 同一位置等待锁的goroutine数量超过该值时提示锁竞争
+	lockContentionCount = 10
+)
+
+// lockWaitStates 等待锁的状态（semacquire 为 Go 1.20 之前的写法）
+var lockWaitStates = []string{
+	"semacquire",
+	"sync.Mutex.Lock",
+	"sync.RWMutex.Lock",
+	"sync.RWMutex.RLock",
+	"sync.WaitGroup.Wait",
+	"sync.Cond.Wait",
+}
+
+// blockedForeverStates 永远不会被唤醒的状态
+var blockedForeverStates = []string{
+	"chan receive (nil chan)",
+	"chan send (nil chan)",
+	"select (no cases)",
+}
+
+// GoroutineService goroutine转储分析服务
+type GoroutineService struct {
+	fileService *FileService
+	redaction   *RedactionService
+}
+
+// NewGoroutineService 创建goroutine转储分析服务
+func NewGoroutineService(fileService *FileService, redaction *RedactionService) *GoroutineService {
+	return &GoroutineService{
+		fileService: fileService,
+		redaction:   redaction,
+	}
+}
+
+// Analyze 解析文件中的goroutine转储，将调用栈相同的goroutine分组并提示可能的死锁
+// dump为转储序号（从1开始），0表示最后一次转储；raw为false时对panic信息脱敏
+func (s *GoroutineService) Analyze(logID, filePath string, dump int, raw bool) (*models.GoroutineAnalysis, error) {
+	parser := gostack.NewParser()
+	err := s.fileService.ForEachLine(logID, filePath, func(lineNo int, line string) error {
+		parser.Add(lineNo, line)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dumps := parser.Dumps()
+	if len(dumps) == 0 {
+		return nil, fmt.Errorf(models.ErrNoGoroutineDump)
+	}
+	if dump == 0 {
+		dump = len(dumps)
+	}
+	if dump < 0 || dump > len(dumps) {
+		return nil, fmt.Errorf("%s: %d", models.ErrInvalidDump, dump)
+	}
+	d := dumps[dump-1]
+	if isRuntimeDeadlock(d) {
+		// 运行时死锁时打印的第一个goroutine并不是出错的goroutine
+		d.PanicID = 0
+	}
+
+	result := &models.GoroutineAnalysis{
+		LogID:          logID,
+		Path:           filePath,
+		Dump:           dump,
+		Dumps:          len(dumps),
+		Line:           d.Line,
+		Total:          len(d.Goroutines),
+		Panic:          d.Panic,
+		Signal:         d.Signal,
+		PanicGoroutine: d.PanicID,
+		States:         make(map[string]int),
+		Groups:         groupGoroutines(d),
+	}
+	for _, g := range d.Goroutines {
+		result.States[g.State]++
+	}
+	result.Hints = goroutineHints(d, result.Groups)
+
+	// panic信息和运行时死锁提示中可能带有日志内容
+	result.Panic = s.redaction.Redact(result.Panic, raw)
+	for i := range result.Hints {
+		result.Hints[i].Message = s.redaction.Redact(result.Hints[i].Message, raw)
+	}
+	return result, nil
+}
+
+// groupGoroutines 按状态和调用栈（函数和位置，不含参数）分组，发生panic的组排在最前，其余按数量降序
+func groupGoroutines(d *gostack.Dump) []models.GoroutineGroup {
+	var groups []models.GoroutineGroup
+	index := make(map[string]int)
+
+	for _, g := range d.Goroutines {
+		key := stackKey(g)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			group := models.GoroutineGroup{
+				State:      g.State,
+				MinWait:    g.WaitMinutes,
+				Frames:     make([]models.StackFrame, 0, len(g.Frames)),
+				Goroutines: []int{},
+				Elided:     g.Elided,
+			}
+			for _, f := range g.Frames {
+				group.Frames = append(group.Frames, convertFrame(f))
+			}
+			if g.CreatedBy != nil {
+				createdBy := convertFrame(*g.CreatedBy)
+				group.CreatedBy = &createdBy
+			}
+			groups = append(groups, group)
+		}
+
+		group := &groups[i]
+		group.Count++
+		if len(group.Goroutines) < maxGroupIDs {
+			group.Goroutines = append(group.Goroutines, g.ID)
+		}
+		if g.WaitMinutes < group.MinWait {
+			group.MinWait = g.WaitMinutes
+		}
+		if g.WaitMinutes > group.MaxWait {
+			group.MaxWait = g.WaitMinutes
+		}
+		if g.LockedToThread {
+			group.LockedToThread++
+		}
+		if d.PanicID != 0 && g.ID == d.PanicID {
+			group.Panicking = true
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Panicking != groups[j].Panicking {
+			return groups[i].Panicking
+		}
+		return groups[i].Count > groups[j].Count
+	})
+	for i := range groups {
+		groups[i].ID = i + 1
+	}
+	return groups
+}
+
+// stackKey 分组键
+func stackKey(g *gostack.Goroutine) string {
+	var sb strings.Builder
+	sb.WriteString(g.State)
+	for _, f := range g.Frames {
+		sb.WriteString("\n" + f.Func + " " + f.File + ":" + strconv.Itoa(f.Line))
+	}
+	if g.CreatedBy != nil {
+		sb.WriteString("\ncreated by " + g.CreatedBy.Func + " " + g.CreatedBy.File + ":" + strconv.Itoa(g.CreatedBy.Line))
+	}
+	return sb.String()
+}
+
+// convertFrame 转换栈帧
+func convertFrame(f gostack.Frame) models.StackFrame {
+	return models.StackFrame{Func: f.Func, Args: f.Args, File: f.File, Line: f.Line}
+}
+
+// goroutineHints 根据等待状态和时长提示可能的死锁
+func goroutineHints(d *gostack.Dump, groups []models.GoroutineGroup) []models.GoroutineHint {
+	hints := []models.GoroutineHint{}
+
+	// 运行时检测到的死锁：所有非运行中的goroutine都相关
+	if isRuntimeDeadlock(d) {
+		hint := models.GoroutineHint{Kind: models.HintRuntimeDeadlock, Message: d.Panic, Groups: []int{}}
+		for _, g := range groups {
+			if g.State != "running" && g.State != "runnable" {
+				hint.Groups = append(hint.Groups, g.ID)
+				hint.Goroutines += g.Count
+			}
+		}
+		hints = append(hints, hint)
+	}
+
+	for _, g := range groups {
+		site := blockingSite(g.Frames)
+		switch {
+		case matchState(g.State, blockedForeverStates):
+			hints = append(hints, models.GoroutineHint{
+				Kind:       models.HintBlockedForever,
+				Message:    fmt.Sprintf("%d 个goroutine在 %s 处永久阻塞（%s）", g.Count, site, g.State),
+				Groups:     []int{g.ID},
+				Goroutines: g.Count,
+			})
+		case matchState(g.State, lockWaitStates) && g.MaxWait >= longLockWaitMinutes:
+			hints = append(hints, models.GoroutineHint{
+				Kind:       models.HintLongLockWait,
+				Message:    fmt.Sprintf("%d 个goroutine在 %s 处等待锁（%s）已达 %d 分钟", g.Count, site, g.State, g.MaxWait),
+				Groups:     []int{g.ID},
+				Goroutines: g.Count,
+			})
+		case matchState(g.State, lockWaitStates) && g.Count >= lockContentionCount:
+			hints = append(hints, models.GoroutineHint{
+				Kind:       models.HintLockContention,
+				Message:    fmt.Sprintf("%d 个goroutine在 %s 处竞争锁（%s）", g.Count, site, g.State),
+				Groups:     []int{g.ID},
+				Goroutines: g.Count,
+			})
+		}
+	}
+	return hints
+}
+
+// isRuntimeDeadlock 是否为运行时检测到的死锁
+func isRuntimeDeadlock(d *gostack.Dump) bool {
+	return strings.Contains(d.Panic, "all goroutines are asleep - deadlock")
+}
+
+// matchState 状态是否以列表中的某一项开头（状态后可能附带其他说明）
+func matchState(state string, states []string) bool {
+	for _, s := range states {
+		if strings.HasPrefix(state, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// blockingSite 调用栈中第一个非标准库同步原语的栈帧，即发生阻塞的业务代码位置
+func blockingSite(frames []models.StackFrame) string {
+	for _, f := range frames {
+		if strings.HasPrefix(f.Func, "runtime.") || strings.HasPrefix(f.Func, "sync.") ||
+			strings.HasPrefix(f.Func, "internal/") {
+			continue
+		}
+		if f.File == "" {
+			return f.Func
+		}
+		return fmt.Sprintf("%s (%s:%d)", f.Func, f.File, f.Line)
+	}
+	if len(frames) > 0 {
+		return frames[0].Func
+	}
+	return "未知位置"
+}
@@ -2,40 +2,224 @@ package services
 
 import (
 	"fmt"
+	"log"
 	"logview-goversion/internal/models"
 	"logview-goversion/internal/repository"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 )
 
+// LogHook 日志生命周期钩子（导入完成、删除后触发）
+type LogHook interface {
+	// OnLogImported 日志下载并解压完成、写入数据库后调用
+	OnLogImported(logID string) error
+	// OnLogDeleted 日志从数据库删除后调用
+	OnLogDeleted(logID string) error
+}
+
+// namedHook 带名称的钩子，名称用于展示导入进度和失败原因
+type namedHook struct {
+	name string
+	hook LogHook
+}
+
 // LogService 日志服务
 type LogService struct {
 	logRepo *repository.LogRepository
+	hooks   []namedHook
+
+	// 导入钩子在后台逐个日志执行
+	mu       sync.Mutex
+	queue    []string      // 等待处理的日志ID
+	wake     chan struct{} // 有新日志入队时通知后台处理
+	running  string        // 正在处理的日志ID
+	canceled bool          // 正在处理的日志已被删除，处理完当前步骤后清理
+	progress models.ImportProgress
 }
 
 // NewLogService 创建日志服务
 func NewLogService(logRepo *repository.LogRepository) *LogService {
 	return &LogService{
 		logRepo: logRepo,
+		wake:    make(chan struct{}, 1),
 	}
 }
 
+// AddHook 注册日志生命周期钩子，按注册顺序依次执行
+func (s *LogService) AddHook(name string, hook LogHook) {
+	s.hooks = append(s.hooks, namedHook{name: name, hook: hook})
+}
+
 // GetAllLogs 获取所有日志
 func (s *LogService) GetAllLogs() ([]models.Log, error) {
-	return s.logRepo.GetAll()
+	logs, err := s.logRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for i := range logs {
+		s.attachProgress(&logs[i])
+	}
+	return logs, nil
 }
 
 // GetLog 获取指定日志
 func (s *LogService) GetLog(logID string) (*models.Log, error) {
-	return s.logRepo.GetByID(logID)
+	l, err := s.logRepo.GetByID(logID)
+	if err != nil || l == nil {
+		return l, err
+	}
+	s.attachProgress(l)
+	return l, nil
+}
+
+// attachProgress 日志正在处理时附上进度
+func (s *LogService) attachProgress(l *models.Log) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running == l.LogID {
+		progress := s.progress
+		l.Progress = &progress
+	}
 }
 
-// AddLog 添加日志
+// AddLog 添加日志，导入钩子在后台执行，处理状态见日志的 status 字段
 func (s *LogService) AddLog(logID, filePath, extractPath string) error {
-	return s.logRepo.Create(logID, filePath, extractPath)
+	if err := s.logRepo.Create(logID, filePath, extractPath); err != nil {
+		return err
+	}
+	s.enqueue(logID)
+	return nil
+}
+
+// StartImportWorker 启动后台导入处理，上次退出时未处理完的日志重新处理
+func (s *LogService) StartImportWorker() {
+	ids, err := s.logRepo.GetIDsByStatus(models.ImportPending, models.ImportProcessing)
+	if err != nil {
+		log.Printf("获取未处理完的日志失败: %v", err)
+	}
+	for _, id := range ids {
+		s.enqueue(id)
+	}
+
+	go func() {
+		for range s.wake {
+			for {
+				logID, ok := s.next()
+				if !ok {
+					break
+				}
+				s.runImportHooks(logID)
+			}
+		}
+	}()
+}
+
+// enqueue 日志加入等待处理队列
+func (s *LogService) enqueue(logID string) {
+	s.mu.Lock()
+	if !slices.Contains(s.queue, logID) {
+		s.queue = append(s.queue, logID)
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// next 取出下一个等待处理的日志，并标记为正在处理
+func (s *LogService) next() (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.queue) == 0 {
+		return "", false
+	}
+	logID := s.queue[0]
+	s.queue = s.queue[1:]
+	s.running = logID
+	s.canceled = false
+	s.progress = models.ImportProgress{Total: len(s.hooks)}
+	return logID, true
+}
+
+// runImportHooks 依次执行导入钩子，单个钩子失败不影响后续钩子，最后记录处理状态
+func (s *LogService) runImportHooks(logID string) {
+	if err := s.logRepo.UpdateStatus(logID, models.ImportProcessing, ""); err != nil {
+		log.Printf("更新日志 %s 处理状态失败: %v", logID, err)
+	}
+
+	var failures []string
+	for i, h := range s.hooks {
+		s.mu.Lock()
+		canceled := s.canceled
+		s.progress = models.ImportProgress{Step: h.name, Done: i, Total: len(s.hooks)}
+		s.mu.Unlock()
+		if canceled {
+			break
+		}
+
+		if err := h.hook.OnLogImported(logID); err != nil {
+			log.Printf("日志 %s 导入钩子 %s 执行失败: %v", logID, h.name, err)
+			failures = append(failures, h.name+": "+err.Error())
+		}
+	}
+
+	s.mu.Lock()
+	canceled := s.canceled
+	s.running = ""
+	s.mu.Unlock()
+
+	// 处理期间日志被删除：清理已生成的数据
+	if canceled {
+		s.runDeleteHooks(logID)
+		return
+	}
+
+	status, errMsg := models.ImportReady, ""
+	if len(failures) > 0 {
+		status, errMsg = models.ImportFailed, strings.Join(failures, "; ")
+	}
+	if err := s.logRepo.UpdateStatus(logID, status, errMsg); err != nil {
+		log.Printf("更新日志 %s 处理状态失败: %v", logID, err)
+	}
 }
 
 // DeleteLog 删除日志
 func (s *LogService) DeleteLog(logID string) (bool, error) {
-	return s.logRepo.Delete(logID)
+	deleted, err := s.logRepo.Delete(logID)
+	if err != nil || !deleted {
+		return deleted, err
+	}
+
+	// 还在等待的直接移出队列；正在处理的由后台处理完当前步骤后清理
+	s.mu.Lock()
+	for i, id := range s.queue {
+		if id == logID {
+			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			break
+		}
+	}
+	if s.running == logID {
+		s.canceled = true
+		s.mu.Unlock()
+		return true, nil
+	}
+	s.mu.Unlock()
+
+	s.runDeleteHooks(logID)
+	return true, nil
+}
+
+// runDeleteHooks 依次执行删除钩子
+func (s *LogService) runDeleteHooks(logID string) {
+	for _, h := range s.hooks {
+		if err := h.hook.OnLogDeleted(logID); err != nil {
+			log.Printf("日志 %s 删除钩子 %s 执行失败: %v", logID, h.name, err)
+		}
+	}
 }
 
 // UpdateTags 更新标签
@@ -53,6 +237,29 @@ func (s *LogService) UpdateMetadata(logID, tags, notes string) error {
 	return s.logRepo.UpdateTagsAndNotes(logID, tags, notes)
 }
 
+// UpdateSize 记录日志解压后的总大小和在磁盘上实际占用的大小
+func (s *LogService) UpdateSize(logID string, size, diskSize int64) error {
+	return s.logRepo.UpdateSize(logID, size, diskSize)
+}
+
+// GetIDsWithoutSize 获取还没有记录大小的日志ID
+func (s *LogService) GetIDsWithoutSize() ([]string, error) {
+	return s.logRepo.GetIDsWithoutSize()
+}
+
+// SortLogs 按下载时间（time，默认）或大小（size）从大到小排序日志
+func SortLogs(logs []models.Log, by string) error {
+	switch by {
+	case "", "time":
+		sort.SliceStable(logs, func(i, j int) bool { return logs[i].DownloadTime.After(logs[j].DownloadTime) })
+	case "size":
+		sort.SliceStable(logs, func(i, j int) bool { return logs[i].Size > logs[j].Size })
+	default:
+		return fmt.Errorf(models.ErrInvalidSort)
+	}
+	return nil
+}
+
 // ValidateLogID 验证日志ID
 func (s *LogService) ValidateLogID(logID interface{}) (string, error) {
 	if logID == nil {
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/pkg/drain"
+	"sort"
+	"time"
+)
+
+// 模板挖掘限制
+const (
+	defaultPatternLimit = 100
+	maxPatternLimit     = 1000
+	maxPatternLines     = 2000000
+	maxPatternExamples  = 3
+)
+
+// PatternService 日志模板挖掘服务
+type PatternService struct {
+	fileService *FileService
+	redaction   *RedactionService
+}
+
+// NewPatternService 创建日志模板挖掘服务
+func NewPatternService(fileService *FileService, redaction *RedactionService) *PatternService {
+	return &PatternService{
+		fileService: fileService,
+		redaction:   redaction,
+	}
+}
+
+// patternStats 单个模板的统计信息
+type patternStats struct {
+	cluster   *drain.Cluster
+	firstSeen time.Time
+	lastSeen  time.Time
+	files     []string
+	levels    map[string]int
+	examples  []models.PatternExample
+}
+
+// GetPatterns 将相似的日志行归并为模板，可以针对单个文件或整个日志包
+func (s *PatternService) GetPatterns(logID string, opts models.PatternOptions) (*models.PatternResult, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultPatternLimit
+	}
+	if limit > maxPatternLimit {
+		limit = maxPatternLimit
+	}
+
+	var files []string
+	if opts.File != "" {
+		files = []string{opts.File}
+	} else {
+		err := s.fileService.WalkTextFiles(logID, func(relPath string, size int64) error {
+			files = append(files, relPath)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	levels := make(map[string]bool, len(opts.Levels))
+	for _, level := range opts.Levels {
+		levels[level] = true
+	}
+
+	result := &models.PatternResult{
+		LogID:    logID,
+		File:     opts.File,
+		Patterns: []models.Pattern{},
+	}
+	miner := drain.NewMiner(drain.DefaultOptions())
+	stats := make(map[*drain.Cluster]*patternStats)
+
+	for _, file := range files {
+		if result.Truncated {
+			break
+		}
+		sample, err := s.fileService.HeadLines(logID, file, detectSampleLines)
+		if err != nil {
+			return nil, err
+		}
+		lineParser, err := s.fileService.NewLineParser("", sample)
+		if err != nil {
+			return nil, err
+		}
+
+		// 跨行的块（如私钥）在挖掘前就替换为占位符，块内的行单独看不出是否敏感，也不构成有意义的模板
+		var lastTime time.Time
+		stream := s.redaction.Stream(false)
+		err = s.fileService.ForEachLine(logID, file, func(lineNo int, line string) error {
+			if result.TotalLines >= maxPatternLines {
+				result.Truncated = true
+				return errStopReading
+			}
+			line = stream.Blocks(line)
+
+			rec := lineParser.Parse(lineNo, line)
+			if rec.HasTime() {
+				lastTime = rec.Time
+			}
+			if len(levels) > 0 && !levels[rec.Level] {
+				return nil
+			}
+
+			message := rec.Message
+			if message == "" {
+				message = rec.Raw
+			}
+			cluster := miner.Add(normalizeTimestamps(message))
+			if cluster == nil {
+				return nil
+			}
+			result.TotalLines++
+
+			st, ok := stats[cluster]
+			if !ok {
+				st = &patternStats{cluster: cluster, levels: make(map[string]int)}
+				stats[cluster] = st
+			}
+			if !lastTime.IsZero() {
+				if st.firstSeen.IsZero() || lastTime.Before(st.firstSeen) {
+					st.firstSeen = lastTime
+				}
+				if lastTime.After(st.lastSeen) {
+					st.lastSeen = lastTime
+				}
+			}
+			if len(st.files) == 0 || st.files[len(st.files)-1] != file {
+				st.files = append(st.files, file)
+			}
+			if rec.Level != "" {
+				st.levels[rec.Level]++
+			}
+			if len(st.examples) < maxPatternExamples {
+				st.examples = append(st.examples, models.PatternExample{File: file, Line: lineNo, Content: line})
+			}
+			return nil
+		})
+		if err != nil && err != errStopReading {
+			return nil, err
+		}
+	}
+
+	ordered := make([]*patternStats, 0, len(stats))
+	for _, st := range stats {
+		if st.cluster.Count >= opts.MinCount {
+			ordered = append(ordered, st)
+		}
+	}
+	result.Total = len(ordered)
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].cluster.Count != ordered[j].cluster.Count {
+			return ordered[i].cluster.Count > ordered[j].cluster.Count
+		}
+		return ordered[i].cluster.ID < ordered[j].cluster.ID
+	})
+	if len(ordered) > limit {
+		ordered = ordered[:limit]
+	}
+
+	for _, st := range ordered {
+		pattern := toPattern(st)
+		pattern.Template = s.redaction.Redact(pattern.Template, opts.Raw)
+		for i := range pattern.Examples {
+			pattern.Examples[i].Content = s.redaction.Redact(pattern.Examples[i].Content, opts.Raw)
+		}
+		result.Patterns = append(result.Patterns, pattern)
+	}
+	return result, nil
+}
+
+// toPattern 转换为响应模型
+func toPattern(st *patternStats) models.Pattern {
+	pattern := models.Pattern{
+		ID:       st.cluster.ID,
+		Template: st.cluster.Template(),
+		Count:    st.cluster.Count,
+		Files:    st.files,
+		Examples: st.examples,
+	}
+	if !st.firstSeen.IsZero() {
+		pattern.FirstSeen = st.firstSeen.Format(time.RFC3339Nano)
+		pattern.LastSeen = st.lastSeen.Format(time.RFC3339Nano)
+	}
+	best := 0
+	for level, count := range st.levels {
+		if count > best || (count == best && level < pattern.Level) {
+			pattern.Level, best = level, count
+		}
+	}
+	return pattern
+}
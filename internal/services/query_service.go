@@ -0,0 +1,209 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"logview-goversion/internal/models"
+	"strings"
+	"time"
+
+	"github.com/itchyny/gojq"
+)
+
+// 查询限制
+const (
+	defaultQueryLimit = 100
+	maxQueryLimit     = 1000
+	queryTimeout      = 30 * time.Second
+)
+
+// QueryService JSON日志查询服务，表达式语法与jq一致
+type QueryService struct {
+	fileService *FileService
+	redaction   *RedactionService
+}
+
+// NewQueryService 创建JSON日志查询服务
+func NewQueryService(fileService *FileService, redaction *RedactionService) *QueryService {
+	return &QueryService{
+		fileService: fileService,
+		redaction:   redaction,
+	}
+}
+
+// ValidateExpr 检查表达式能否编译
+func (s *QueryService) ValidateExpr(expr string) error {
+	_, err := compileQuery(expr)
+	return err
+}
+
+// compileQuery 解析并编译jq表达式
+func compileQuery(expr string) (*gojq.Code, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, fmt.Errorf(models.ErrEmptyExpr)
+	}
+	query, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", models.ErrInvalidExpr, err)
+	}
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", models.ErrInvalidExpr, err)
+	}
+	return code, nil
+}
+
+// Query 对文件中每一行JSON记录执行表达式，对输出结果分页，得到当前页和下一页的第一个结果后停止读取
+// 例如 select(.level=="error" and .user_id=="42") | {ts, msg}
+// raw为false时表达式在脱敏后的记录上执行，取出单个字段也不会得到敏感内容
+func (s *QueryService) Query(logID, filePath, expr string, offset, limit int, raw bool) (*models.QueryResult, error) {
+	code, err := compileQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultQueryLimit
+	}
+	if limit > maxQueryLimit {
+		limit = maxQueryLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	result := &models.QueryResult{
+		Path:    filePath,
+		Expr:    expr,
+		Offset:  offset,
+		Limit:   limit,
+		Results: []models.QueryMatch{},
+	}
+
+	err = s.fileService.ForEachLine(logID, filePath, func(lineNo int, line string) error {
+		if ctx.Err() != nil {
+			result.Truncated = true
+			return errStopReading
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return nil
+		}
+		if !strings.HasPrefix(line, "{") {
+			result.Skipped++
+			return nil
+		}
+		record, err := decodeJSON([]byte(line))
+		if err != nil {
+			result.Skipped++
+			return nil
+		}
+		result.Records++
+		if !raw {
+			record = s.redactRecord(record)
+		}
+
+		iter := code.RunWithContext(ctx, record)
+		for {
+			value, ok := iter.Next()
+			if !ok {
+				break
+			}
+			if err, isErr := value.(error); isErr {
+				if ctx.Err() != nil {
+					result.Truncated = true
+					return errStopReading
+				}
+				result.Errors++
+				result.LastError = err.Error()
+				break
+			}
+
+			result.Total++
+			if result.Total > offset+limit {
+				result.HasMore = true
+				return errStopReading
+			}
+			if result.Total > offset {
+				result.Results = append(result.Results, models.QueryMatch{Line: lineNo, Value: value})
+			}
+		}
+		return nil
+	})
+	if err != nil && err != errStopReading {
+		return nil, err
+	}
+	return result, nil
+}
+
+// decodeJSON 解码一个JSON值，数字保留为 json.Number，由 gojq 转换为整数或浮点数，超过 2^53 的整数（如64位ID）不会丢失精度
+func decodeJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("JSON 值之后还有其他内容")
+	}
+	return v, nil
+}
+
+// redactRecord 按JSON文本脱敏记录，使 "password": "..." 等按键名匹配的规则同样生效
+// 脱敏后不再是合法JSON时逐个字段脱敏
+func (s *QueryService) redactRecord(record interface{}) interface{} {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return redactJSONValue(record, s.redaction)
+	}
+	text := string(data)
+	redacted := s.redaction.Redact(text, false)
+	if redacted == text {
+		return record
+	}
+	v, err := decodeJSON([]byte(redacted))
+	if err != nil {
+		return redactJSONValue(record, s.redaction)
+	}
+	return v
+}
+
+// redactJSONValue 递归脱敏JSON值，对象中的字段按键名和值一起脱敏，键名规则同样生效
+func redactJSONValue(value interface{}, redaction *RedactionService) interface{} {
+	switch v := value.(type) {
+	case string:
+		return redaction.Redact(v, false)
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = redactJSONField(key, item, redaction)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJSONValue(item, redaction)
+		}
+	}
+	return value
+}
+
+// redactJSONField 脱敏对象中的单个字段，数字和布尔值只在命中键名规则时替换为脱敏后的字符串
+func redactJSONField(key string, value interface{}, redaction *RedactionService) interface{} {
+	switch v := value.(type) {
+	case string:
+		return redaction.RedactField(key, v, false)
+	case json.Number, bool:
+		text := fmt.Sprint(v)
+		if redacted := redaction.RedactField(key, text, false); redacted != text {
+			return redacted
+		}
+		return value
+	}
+	return redactJSONValue(value, redaction)
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"logview-goversion/internal/config"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRedactRecord(t *testing.T) {
+	// 自定义规则的替换内容包含引号，脱敏JSON文本后不再是合法JSON，走逐个字段脱敏
+	rulesFile := filepath.Join(t.TempDir(), "rules.json")
+	rules := `[{"name": "quote", "pattern": "zzz", "replacement": "\""}]`
+	if err := os.WriteFile(rulesFile, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	cfg.Redaction.Enabled = true
+	cfg.Redaction.RulesFile = rulesFile
+	redaction, err := NewRedactionService(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewQueryService(nil, redaction)
+
+	tests := []struct {
+		name   string
+		record string
+		want   string
+	}{
+		{
+			name:   "按JSON文本脱敏",
+			record: `{"password": "hunter2", "msg": "ok"}`,
+			want:   `{"password": "[REDACTED:secret]", "msg": "ok"}`,
+		},
+		{
+			name:   "逐个字段脱敏时键名规则仍然生效",
+			record: `{"password": "hunter2", "msg": "zzz"}`,
+			want:   `{"password": "[REDACTED:secret]", "msg": "\""}`,
+		},
+		{
+			name:   "逐个字段脱敏时数字值和嵌套对象",
+			record: `{"pin": 1, "token": 12345, "user": {"api_key": "abc", "email": "bob@example.com"}, "tags": ["zzz"]}`,
+			want:   `{"pin": 1, "token": "[REDACTED:secret]", "user": {"api_key": "[REDACTED:secret]", "email": "[REDACTED:email]"}, "tags": ["\""]}`,
+		},
+		{
+			name:   "没有敏感内容",
+			record: `{"msg": "ok", "n": 3}`,
+			want:   `{"msg": "ok", "n": 3}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, err := decodeJSON([]byte(tt.record))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := decodeJSON([]byte(tt.want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.redactRecord(record); !reflect.DeepEqual(got, want) {
+				t.Errorf("redactRecord() = %v, 期望 %v", got, want)
+			}
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	cfg.Storage.ZipDir = filepath.Join(dir, "zip")
+	cfg.Storage.ExtractDir = filepath.Join(dir, "extracted")
+	cfg.Storage.MaxFileSize = 1 << 20
+	s := NewQueryService(NewFileService(cfg, nil, nil, nil), nil)
+
+	logDir := filepath.Join(cfg.Storage.ExtractDir, "log1")
+	os.MkdirAll(logDir, 0755)
+	content := "not json\n"
+	for i := 1; i <= 10; i++ {
+		content += fmt.Sprintf(`{"id": %d, "level": "info", "n": %d}`+"\n", 9007199254740993+int64(i), i)
+	}
+	if err := os.WriteFile(filepath.Join(logDir, "app.json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		expr    string
+		offset  int
+		limit   int
+		values  string // 结果值的JSON
+		total   int
+		records int
+		hasMore bool
+	}{
+		{name: "第一页", expr: ".n", limit: 3, values: "[1,2,3]", total: 4, records: 4, hasMore: true},
+		{name: "中间页", expr: ".n", offset: 3, limit: 3, values: "[4,5,6]", total: 7, records: 7, hasMore: true},
+		{name: "最后一页", expr: ".n", offset: 8, limit: 3, values: "[9,10]", total: 10, records: 10},
+		{name: "每条记录多个输出", expr: ".n, .n", limit: 3, values: "[1,1,2]", total: 4, records: 2, hasMore: true},
+		{name: "64位ID不丢失精度", expr: "select(.id == 9007199254740995) | .id", limit: 10, values: "[9007199254740995]", total: 1, records: 10},
+		{name: "64位ID运算", expr: "select(.n == 1) | .id + 1", limit: 10, values: "[9007199254740995]", total: 1, records: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.Query("log1", "app.json", tt.expr, tt.offset, tt.limit, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			values := make([]interface{}, len(result.Results))
+			for i, r := range result.Results {
+				values[i] = r.Value
+			}
+			data, _ := json.Marshal(values)
+			if string(data) != tt.values {
+				t.Errorf("结果 = %s, 期望 %s", data, tt.values)
+			}
+			if result.Total != tt.total || result.Records != tt.records || result.HasMore != tt.hasMore {
+				t.Errorf("Total/Records/HasMore = %d/%d/%v, 期望 %d/%d/%v",
+					result.Total, result.Records, result.HasMore, tt.total, tt.records, tt.hasMore)
+			}
+			if result.Skipped != 1 {
+				t.Errorf("Skipped = %d, 期望 1", result.Skipped)
+			}
+		})
+	}
+}
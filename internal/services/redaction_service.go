@@ -0,0 +1,172 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"logview-goversion/internal/config"
+	"logview-goversion/internal/pkg/redact"
+	"logview-goversion/internal/repository"
+	"strings"
+)
+
+// RedactionService 脱敏服务，在返回文件内容、搜索结果和导出内容时即时脱敏
+type RedactionService struct {
+	enabled     bool
+	redactor    *redact.Redactor
+	rawRoles    map[string]bool
+	fingerprint string
+}
+
+// NewRedactionService 创建脱敏服务，自定义规则在内置规则之前应用
+func NewRedactionService(cfg *config.Config) (*RedactionService, error) {
+	rules := []redact.Rule{}
+	if cfg.Redaction.RulesFile != "" {
+		custom, err := redact.LoadRules(cfg.Redaction.RulesFile)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, custom...)
+	}
+	rules = append(rules, redact.BuiltinRules()...)
+
+	redactor, err := redact.New(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	rawRoles := make(map[string]bool, len(cfg.Redaction.RawRoles))
+	for _, role := range cfg.Redaction.RawRoles {
+		rawRoles[strings.ToLower(role)] = true
+	}
+	return &RedactionService{
+		enabled:     cfg.Redaction.Enabled,
+		redactor:    redactor,
+		rawRoles:    rawRoles,
+		fingerprint: rulesFingerprint(cfg.Redaction.Enabled, rules),
+	}, nil
+}
+
+// rulesFingerprint 脱敏开关和规则的摘要，全文索引据此判断脱敏列是否需要重建
+func rulesFingerprint(enabled bool, rules []redact.Rule) string {
+	data, _ := json.Marshal(struct {
+		Enabled bool          `json:"enabled"`
+		Rules   []redact.Rule `json:"rules"`
+	}{enabled, rules})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Fingerprint 当前脱敏开关和规则的摘要，规则文件或内置规则变化时随之改变
+func (s *RedactionService) Fingerprint() string {
+	if s == nil {
+		return rulesFingerprint(false, nil)
+	}
+	return s.fingerprint
+}
+
+// CanViewRaw 角色是否允许查看未脱敏的原始内容
+func (s *RedactionService) CanViewRaw(role string) bool {
+	return !s.enabled || s.rawRoles[strings.ToLower(strings.TrimSpace(role))]
+}
+
+// Redact 脱敏文本，raw为true或未启用脱敏时原样返回
+func (s *RedactionService) Redact(text string, raw bool) string {
+	if s == nil || !s.enabled || raw {
+		return text
+	}
+	return s.redactor.Redact(text)
+}
+
+// Stream 创建逐行脱敏器，块规则（如私钥）可以跨行；raw为true或未启用脱敏时返回nil，按原样返回每一行
+// 同一个文件或事件中的行需要按顺序经过同一个实例
+func (s *RedactionService) Stream(raw bool) *redact.Stream {
+	if s == nil || !s.enabled || raw {
+		return nil
+	}
+	return s.redactor.NewStream()
+}
+
+// RedactLines 按顺序脱敏连续的行（如一个多行事件），返回的行数不变
+func (s *RedactionService) RedactLines(lines []string, raw bool) []string {
+	stream := s.Stream(raw)
+	if stream == nil {
+		return lines
+	}
+	redacted := make([]string, len(lines))
+	for i, line := range lines {
+		redacted[i] = stream.Redact(line)
+	}
+	return redacted
+}
+
+// MaskBlocks 只替换连续的行中跨行的块，其他规则之后再逐行应用，返回的行数不变
+func (s *RedactionService) MaskBlocks(lines []string, raw bool) []string {
+	stream := s.Stream(raw)
+	if stream == nil {
+		return lines
+	}
+	masked := make([]string, len(lines))
+	for i, line := range lines {
+		masked[i] = stream.Blocks(line)
+	}
+	return masked
+}
+
+// RedactField 脱敏键值对中的值，值本身没有命中规则时再按 name=value 的写法匹配键名规则
+func (s *RedactionService) RedactField(name, value string, raw bool) string {
+	if s == nil || !s.enabled || raw {
+		return value
+	}
+	if redacted := s.redactor.Redact(value); redacted != value {
+		return redacted
+	}
+	prefix := name + "="
+	if redacted := s.redactor.Redact(prefix + value); redacted != prefix+value {
+		return strings.TrimPrefix(redacted, prefix)
+	}
+	return value
+}
+
+// MaskBytes 返回二进制数据中需要遮盖的字节，raw为true、未启用脱敏或没有命中时返回nil
+// 可打印ASCII按原样匹配，其他字节视为换行，规则不会跨过二进制内容匹配
+func (s *RedactionService) MaskBytes(data []byte, raw bool) []bool {
+	if s == nil || !s.enabled || raw {
+		return nil
+	}
+	text := make([]byte, len(data))
+	for i, b := range data {
+		if b >= 0x20 && b < 0x7f {
+			text[i] = b
+		} else {
+			text[i] = '\n'
+		}
+	}
+	spans := s.redactor.Spans(string(text))
+	if len(spans) == 0 {
+		return nil
+	}
+	masked := make([]bool, len(data))
+	for _, span := range spans {
+		for i := span[0]; i < span[1]; i++ {
+			masked[i] = true
+		}
+	}
+	return masked
+}
+
+// RedactSnippet 脱敏带高亮标记的搜索摘要（标记为 repository.SnippetMarkStart 和 repository.SnippetMarkEnd）
+// 高亮标记可能把敏感内容拆开，先去掉标记再脱敏；有内容被替换时不再保留高亮
+func (s *RedactionService) RedactSnippet(snippet string, raw bool) string {
+	if s == nil || !s.enabled || raw {
+		return snippet
+	}
+	plain := snippetMarks.Replace(snippet)
+	if redacted := s.redactor.Redact(plain); redacted != plain {
+		return redacted
+	}
+	return snippet
+}
+
+// snippetMarks 去掉搜索摘要中的高亮标记
+var snippetMarks = strings.NewReplacer(repository.SnippetMarkStart, "", repository.SnippetMarkEnd, "")
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"logview-goversion/internal/config"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/repository"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestPrivateKeyRedaction 多行私钥在所有按行读取的路径上都整体脱敏
+func TestPrivateKeyRedaction(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	var body []string
+	for _, line := range strings.Split(strings.TrimSpace(keyPEM), "\n") {
+		if !strings.HasPrefix(line, "-----") {
+			body = append(body, line)
+		}
+	}
+
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	cfg.Storage.ZipDir = filepath.Join(dir, "zip")
+	cfg.Storage.ExtractDir = filepath.Join(dir, "extracted")
+	cfg.Storage.MaxFileSize = 1 << 20
+	cfg.Storage.MaxPreview = 1 << 20
+	cfg.Redaction.Enabled = true
+
+	redaction, err := NewRedactionService(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileService := NewFileService(cfg, redaction, nil, nil)
+
+	content := "2024-01-01T00:00:01Z INFO loading key\n" + keyPEM + "2024-01-01T00:00:02Z ERROR key rejected\n"
+	logDir := filepath.Join(cfg.Storage.ExtractDir, "log1")
+	os.MkdirAll(logDir, 0755)
+	if err := os.WriteFile(filepath.Join(logDir, "a.log"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(logDir, "b.log"), []byte("2024-01-01T00:00:01Z INFO loading key\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	diffService := NewDiffService(fileService, redaction)
+	logRepo, err := repository.NewLogRepository(filepath.Join(dir, "logs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logRepo.Close()
+	searchRepo, err := repository.NewSearchRepository(logRepo.DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	searchService := NewSearchService(searchRepo, fileService, redaction)
+	timelineService := NewTimelineService(fileService, redaction)
+	patternService := NewPatternService(fileService, redaction)
+
+	tests := []struct {
+		name string
+		run  func(raw bool) (interface{}, error)
+	}{
+		{name: "整个文件", run: func(raw bool) (interface{}, error) {
+			return fileService.GetFileContent("log1", "a.log", models.FileContentOptions{Raw: raw})
+		}},
+		{name: "从块中间开始的页", run: func(raw bool) (interface{}, error) {
+			return fileService.GetFileContent("log1", "a.log", models.FileContentOptions{Offset: 3, Limit: 2, Raw: raw})
+		}},
+		{name: "解析记录", run: func(raw bool) (interface{}, error) {
+			return fileService.GetFileContent("log1", "a.log", models.FileContentOptions{Limit: 100, Parse: true, Raw: raw})
+		}},
+		{name: "差异", run: func(raw bool) (interface{}, error) {
+			return diffService.DiffFiles(models.FileRef{LogID: "log1", Path: "b.log"}, models.FileRef{LogID: "log1", Path: "a.log"}, models.DiffOptions{Raw: raw})
+		}},
+		{name: "正则搜索", run: func(raw bool) (interface{}, error) {
+			return searchService.Grep("log1", models.GrepOptions{Pattern: "[A-Za-z0-9+/]{20,}|key", Raw: raw})
+		}},
+		{name: "时间线", run: func(raw bool) (interface{}, error) {
+			return timelineService.GetTimeline("log1", models.TimelineOptions{Files: []string{"a.log"}, Raw: raw})
+		}},
+		{name: "日志模板", run: func(raw bool) (interface{}, error) {
+			return patternService.GetPatterns("log1", models.PatternOptions{File: "a.log", Raw: raw})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.run(false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, _ := json.Marshal(result)
+			for _, line := range body {
+				if strings.Contains(string(data), line) {
+					t.Errorf("私钥正文未脱敏: %q", line)
+				}
+			}
+			if !strings.Contains(string(data), "[REDACTED:private-key]") {
+				t.Errorf("结果中没有私钥占位符: %s", data)
+			}
+		})
+	}
+
+	// 全文索引的脱敏列中不包含块内的行（需要 -tags sqlite_fts5）
+	if searchRepo.Enabled() {
+		if _, err := searchService.IndexLog("log1"); err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range body {
+			// 取正文中的一段字母数字作为查询词
+			term := strings.FieldsFunc(line, func(r rune) bool { return r == '+' || r == '/' || r == '=' })[0]
+			result, err := searchService.Search(`"`+term+`"`, "log1", 0, 10, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Total != 0 {
+				t.Errorf("搜索私钥正文 %q 命中 %d 条", term, result.Total)
+			}
+		}
+	}
+
+	// 有原始内容权限时文件视图仍返回私钥原文
+	result, err := fileService.GetFileContent("log1", "a.log", models.FileContentOptions{Raw: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result.Content, body[0]) {
+		t.Error("原始内容中缺少私钥正文")
+	}
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"log"
+	"logview-goversion/internal/config"
+	"logview-goversion/internal/models"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RetentionService 保留策略服务，按最长保留时间、总大小和数量上限删除最早下载的日志，
+// 带有豁免标签（如 pinned）的日志计入总量但不会被删除
+type RetentionService struct {
+	policy      models.RetentionPolicy
+	logService  *LogService
+	fileService *FileService
+	mu          sync.Mutex // 同一时间只执行一次清理
+}
+
+// NewRetentionService 创建保留策略服务
+func NewRetentionService(cfg *config.Config, logService *LogService, fileService *FileService) *RetentionService {
+	return &RetentionService{
+		policy: models.RetentionPolicy{
+			MaxAgeDays:    cfg.Retention.MaxAgeDays,
+			MaxTotalBytes: cfg.Retention.MaxTotalBytes,
+			MaxBundles:    cfg.Retention.MaxBundles,
+			ExemptTags:    cfg.Retention.ExemptTags,
+			Interval:      cfg.Retention.Interval,
+		},
+		logService:  logService,
+		fileService: fileService,
+	}
+}
+
+// Policy 获取生效的保留策略
+func (s *RetentionService) Policy() models.RetentionPolicy {
+	return s.policy
+}
+
+// StartJanitor 启动后台清理，间隔为0或没有任何限制时不启动
+func (s *RetentionService) StartJanitor() {
+	p := s.policy
+	if p.Interval <= 0 || (p.MaxAgeDays <= 0 && p.MaxTotalBytes <= 0 && p.MaxBundles <= 0) {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(p.Interval) * time.Minute)
+		defer ticker.Stop()
+
+		for ; ; <-ticker.C {
+			plan, err := s.Run(false)
+			if err != nil {
+				log.Printf("保留策略清理失败: %v", err)
+				continue
+			}
+			if len(plan.Evict) > 0 {
+				log.Printf("保留策略清理删除了 %d 个日志，释放 %d 字节", len(plan.Evict), plan.FreedBytes)
+			}
+		}
+	}()
+}
+
+// Run 计算需要删除的日志，dryRun 为 false 时按与删除接口相同的方式删除
+func (s *RetentionService) Run(dryRun bool) (*models.RetentionPlan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plan, err := s.plan()
+	if err != nil {
+		return nil, err
+	}
+	plan.DryRun = dryRun
+	if dryRun {
+		return plan, nil
+	}
+
+	plan.FreedBytes = 0
+	for i := range plan.Evict {
+		c := &plan.Evict[i]
+		if err := s.evict(c.LogID); err != nil {
+			c.Error = err.Error()
+			log.Printf("保留策略删除日志 %s 失败: %v", c.LogID, err)
+			continue
+		}
+		plan.FreedBytes += c.Size
+	}
+	return plan, nil
+}
+
+// plan 按下载时间从早到晚依次检查最长保留时间、数量上限和总大小上限
+func (s *RetentionService) plan() (*models.RetentionPlan, error) {
+	logs, err := s.logService.GetAllLogs()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].DownloadTime.Before(logs[j].DownloadTime)
+	})
+
+	plan := &models.RetentionPlan{
+		Policy:  s.policy,
+		Bundles: len(logs),
+		Evict:   []models.RetentionCandidate{},
+		RunAt:   time.Now(),
+	}
+	// 总大小上限按磁盘上实际占用的大小计算，压缩保存时远小于解压后的大小
+	sizes := make([]int64, len(logs))
+	for i, l := range logs {
+		size := l.DiskSize
+		if size == 0 {
+			// 还没有记录大小的日志统计解压目录，目录已不存在时按0字节计算
+			size, _ = s.fileService.BundleDiskSize(l.LogID)
+		}
+		sizes[i] = size
+		plan.TotalBytes += size
+	}
+
+	// 下载时间按本地时间读取，截止时间使用相同的表示方式比较
+	var cutoff time.Time
+	if s.policy.MaxAgeDays > 0 {
+		now, _ := time.Parse("2006-01-02 15:04:05", time.Now().Format("2006-01-02 15:04:05"))
+		cutoff = now.AddDate(0, 0, -s.policy.MaxAgeDays)
+	}
+
+	remaining := len(logs)
+	remainingBytes := plan.TotalBytes
+	for i, l := range logs {
+		if s.exempt(l.Tags) {
+			plan.Exempt++
+			continue
+		}
+
+		reason := ""
+		switch {
+		case s.policy.MaxAgeDays > 0 && l.DownloadTime.Before(cutoff):
+			reason = models.RetentionMaxAge
+		case s.policy.MaxBundles > 0 && remaining > s.policy.MaxBundles:
+			reason = models.RetentionMaxBundles
+		case s.policy.MaxTotalBytes > 0 && remainingBytes > s.policy.MaxTotalBytes:
+			reason = models.RetentionMaxBytes
+		}
+		if reason == "" {
+			continue
+		}
+
+		plan.Evict = append(plan.Evict, models.RetentionCandidate{
+			LogID:        l.LogID,
+			DownloadTime: l.DownloadTime,
+			Tags:         l.Tags,
+			Size:         sizes[i],
+			LogicalSize:  l.Size,
+			Reason:       reason,
+		})
+		plan.FreedBytes += sizes[i]
+		remaining--
+		remainingBytes -= sizes[i]
+	}
+	return plan, nil
+}
+
+// exempt 日志是否带有豁免标签，标签不区分大小写
+func (s *RetentionService) exempt(tags string) bool {
+	for _, tag := range strings.Split(tags, ",") {
+		tag = strings.TrimSpace(tag)
+		for _, t := range s.policy.ExemptTags {
+			if strings.EqualFold(tag, t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// evict 删除日志记录（触发删除钩子）和解压后的文件，与删除接口相同
+func (s *RetentionService) evict(logID string) error {
+	if _, err := s.logService.DeleteLog(logID); err != nil {
+		return err
+	}
+	return s.fileService.DeleteLogFiles(logID)
+}
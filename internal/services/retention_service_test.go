@@ -0,0 +1,172 @@
+package services
+
+import (
+	"fmt"
+	"logview-goversion/internal/config"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/repository"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// retentionLog 测试用的日志记录，diskSize 为0时在解压目录写入 files 字节的文件
+type retentionLog struct {
+	id       string
+	ageDays  int
+	tags     string
+	size     int64
+	diskSize int64
+	files    int
+}
+
+func TestRetentionPlan(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy models.RetentionPolicy
+		logs   []retentionLog
+		evict  []string // 日志ID:原因，按删除顺序
+		total  int64
+		exempt int
+	}{
+		{
+			name:  "没有限制",
+			logs:  []retentionLog{{id: "a", ageDays: 100, diskSize: 10}, {id: "b", ageDays: 1, diskSize: 10}},
+			total: 20,
+		},
+		{
+			name:   "超过最长保留时间",
+			policy: models.RetentionPolicy{MaxAgeDays: 7},
+			logs: []retentionLog{
+				{id: "new", ageDays: 1, diskSize: 10},
+				{id: "old", ageDays: 10, diskSize: 10},
+				{id: "older", ageDays: 20, diskSize: 10},
+			},
+			evict: []string{"older:max_age", "old:max_age"},
+			total: 30,
+		},
+		{
+			name:   "超过数量上限时删除最早的",
+			policy: models.RetentionPolicy{MaxBundles: 2},
+			logs: []retentionLog{
+				{id: "a", ageDays: 4, diskSize: 10},
+				{id: "b", ageDays: 3, diskSize: 10},
+				{id: "c", ageDays: 2, diskSize: 10},
+				{id: "d", ageDays: 1, diskSize: 10},
+			},
+			evict: []string{"a:max_bundles", "b:max_bundles"},
+			total: 40,
+		},
+		{
+			name:   "总大小按磁盘占用计算",
+			policy: models.RetentionPolicy{MaxTotalBytes: 250},
+			logs: []retentionLog{
+				{id: "a", ageDays: 4, size: 1000, diskSize: 100},
+				{id: "b", ageDays: 3, size: 1000, diskSize: 100},
+				{id: "c", ageDays: 2, size: 1000, diskSize: 100},
+				{id: "d", ageDays: 1, size: 1000, diskSize: 100},
+			},
+			evict: []string{"a:max_total_bytes", "b:max_total_bytes"},
+			total: 400,
+		},
+		{
+			name:   "豁免标签计入总量但不删除",
+			policy: models.RetentionPolicy{MaxBundles: 2, ExemptTags: []string{"pinned"}},
+			logs: []retentionLog{
+				{id: "a", ageDays: 3, tags: "prod, Pinned", diskSize: 10},
+				{id: "b", ageDays: 2, diskSize: 10},
+				{id: "c", ageDays: 1, diskSize: 10},
+			},
+			evict:  []string{"b:max_bundles"},
+			total:  30,
+			exempt: 1,
+		},
+		{
+			name:   "先按时间再按数量",
+			policy: models.RetentionPolicy{MaxAgeDays: 7, MaxBundles: 1},
+			logs: []retentionLog{
+				{id: "a", ageDays: 10, diskSize: 10},
+				{id: "b", ageDays: 2, diskSize: 10},
+				{id: "c", ageDays: 1, diskSize: 10},
+			},
+			evict: []string{"a:max_age", "b:max_bundles"},
+			total: 30,
+		},
+		{
+			name:   "没有记录大小时统计解压目录",
+			policy: models.RetentionPolicy{MaxTotalBytes: 150},
+			logs: []retentionLog{
+				{id: "a", ageDays: 2, files: 100},
+				{id: "b", ageDays: 1, files: 100},
+			},
+			evict: []string{"a:max_total_bytes"},
+			total: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			cfg := &config.Config{}
+			cfg.Storage.ZipDir = filepath.Join(dir, "zip")
+			cfg.Storage.ExtractDir = filepath.Join(dir, "extracted")
+
+			logRepo, err := repository.NewLogRepository(filepath.Join(dir, "logs.db"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer logRepo.Close()
+
+			for _, l := range tt.logs {
+				extractPath := filepath.Join(cfg.Storage.ExtractDir, l.id)
+				if err := logRepo.Create(l.id, "", extractPath); err != nil {
+					t.Fatal(err)
+				}
+				_, err := logRepo.DB().Exec("UPDATE logs SET download_time = datetime('now', ?), tags = ? WHERE log_id = ?",
+					fmt.Sprintf("-%d days", l.ageDays), l.tags, l.id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if l.diskSize > 0 {
+					if err := logRepo.UpdateSize(l.id, l.size, l.diskSize); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if l.files > 0 {
+					os.MkdirAll(extractPath, 0755)
+					if err := os.WriteFile(filepath.Join(extractPath, "app.log"), make([]byte, l.files), 0644); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			s := &RetentionService{
+				policy:      tt.policy,
+				logService:  NewLogService(logRepo),
+				fileService: NewFileService(cfg, nil, nil, nil),
+			}
+			plan, err := s.plan()
+			if err != nil {
+				t.Fatalf("plan() 错误: %v", err)
+			}
+
+			var evict []string
+			var freed int64
+			for _, c := range plan.Evict {
+				evict = append(evict, c.LogID+":"+c.Reason)
+				freed += c.Size
+			}
+			if !reflect.DeepEqual(evict, tt.evict) {
+				t.Errorf("删除 = %v, 期望 %v", evict, tt.evict)
+			}
+			if plan.Bundles != len(tt.logs) || plan.TotalBytes != tt.total || plan.Exempt != tt.exempt {
+				t.Errorf("Bundles/TotalBytes/Exempt = %d/%d/%d, 期望 %d/%d/%d",
+					plan.Bundles, plan.TotalBytes, plan.Exempt, len(tt.logs), tt.total, tt.exempt)
+			}
+			if plan.FreedBytes != freed {
+				t.Errorf("FreedBytes = %d, 期望 %d", plan.FreedBytes, freed)
+			}
+		})
+	}
+}
@@ -0,0 +1,343 @@
+package services
+
+import (
+	"fmt"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/repository"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxFindingContent 匹配结果中保存的行内容的最大长度（字节）
+const maxFindingContent = 1000
+
+// validSeverities 有效的严重程度
+var validSeverities = map[string]bool{
+	models.SeverityCritical: true,
+	models.SeverityHigh:     true,
+	models.SeverityMedium:   true,
+	models.SeverityLow:      true,
+	models.SeverityInfo:     true,
+}
+
+// RuleService 已知问题规则服务，日志导入后自动扫描
+type RuleService struct {
+	ruleRepo    *repository.RuleRepository
+	fileService *FileService
+	redaction   *RedactionService
+}
+
+// NewRuleService 创建规则服务
+func NewRuleService(ruleRepo *repository.RuleRepository, fileService *FileService, redaction *RedactionService) *RuleService {
+	return &RuleService{
+		ruleRepo:    ruleRepo,
+		fileService: fileService,
+		redaction:   redaction,
+	}
+}
+
+// OnLogImported 导入完成后运行所有启用的规则
+func (s *RuleService) OnLogImported(logID string) error {
+	_, err := s.ScanLog(logID)
+	return err
+}
+
+// OnLogDeleted 删除日志的匹配结果
+func (s *RuleService) OnLogDeleted(logID string) error {
+	return s.ruleRepo.DeleteFindingsByLogID(logID)
+}
+
+// compiledRule 编译后的规则
+type compiledRule struct {
+	rule       models.Rule
+	conditions []*regexp.Regexp
+	negate     []bool
+}
+
+// ruleMatch 规则在单个文件中的匹配状态
+type ruleMatch struct {
+	matched   []bool // 各条件是否出现过
+	firstLine int
+	content   string
+	count     int
+}
+
+// buildRule 校验请求并转换为规则
+func buildRule(req *models.RuleRequest) (*models.Rule, error) {
+	rule := &models.Rule{
+		Name:        strings.TrimSpace(req.Name),
+		FileGlob:    strings.TrimSpace(req.FileGlob),
+		Pattern:     req.Pattern,
+		Conditions:  []models.RuleCondition{},
+		Match:       strings.ToLower(strings.TrimSpace(req.Match)),
+		Severity:    strings.ToLower(strings.TrimSpace(req.Severity)),
+		Remediation: strings.TrimSpace(req.Remediation),
+		Enabled:     req.Enabled == nil || *req.Enabled,
+	}
+	for _, cond := range req.Conditions {
+		if cond.Pattern != "" {
+			rule.Conditions = append(rule.Conditions, cond)
+		}
+	}
+
+	if rule.Name == "" {
+		return nil, fmt.Errorf(models.ErrEmptyName)
+	}
+	if rule.Match == "" {
+		rule.Match = models.RuleMatchAll
+	}
+	if rule.Match != models.RuleMatchAll && rule.Match != models.RuleMatchAny {
+		return nil, fmt.Errorf(models.ErrInvalidRuleMatch)
+	}
+	if rule.Severity == "" {
+		rule.Severity = models.SeverityMedium
+	}
+	if !validSeverities[rule.Severity] {
+		return nil, fmt.Errorf(models.ErrInvalidSeverity)
+	}
+	if err := validateFileGlob(rule.FileGlob); err != nil {
+		return nil, err
+	}
+	if _, err := compileRule(*rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// compileRule 编译规则的所有条件，pattern 作为第一个条件
+func compileRule(rule models.Rule) (*compiledRule, error) {
+	conditions := rule.Conditions
+	if rule.Pattern != "" {
+		conditions = append([]models.RuleCondition{{Pattern: rule.Pattern}}, conditions...)
+	}
+
+	compiled := &compiledRule{rule: rule}
+	positive := 0
+	for _, cond := range conditions {
+		re, err := regexp.Compile(cond.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", models.ErrInvalidPattern, err)
+		}
+		compiled.conditions = append(compiled.conditions, re)
+		compiled.negate = append(compiled.negate, cond.Negate)
+		if !cond.Negate {
+			positive++
+		}
+	}
+	// 只有否定条件时无法定位匹配的行
+	if positive == 0 {
+		return nil, fmt.Errorf(models.ErrEmptyRule)
+	}
+	return compiled, nil
+}
+
+// ValidateRule 检查规则请求是否有效
+func (s *RuleService) ValidateRule(req *models.RuleRequest) error {
+	_, err := buildRule(req)
+	return err
+}
+
+// CreateRule 创建规则
+func (s *RuleService) CreateRule(req *models.RuleRequest) (*models.Rule, error) {
+	rule, err := buildRule(req)
+	if err != nil {
+		return nil, err
+	}
+	id, err := s.ruleRepo.CreateRule(rule)
+	if err != nil {
+		return nil, fmt.Errorf("保存规则失败: %w", err)
+	}
+	return s.ruleRepo.GetRule(id)
+}
+
+// ListRules 获取所有规则
+func (s *RuleService) ListRules() ([]models.Rule, error) {
+	return s.ruleRepo.ListRules(false)
+}
+
+// GetRule 获取规则，不存在时返回nil
+func (s *RuleService) GetRule(id int64) (*models.Rule, error) {
+	return s.ruleRepo.GetRule(id)
+}
+
+// UpdateRule 更新规则，不存在时返回nil；已有的匹配结果在重新扫描后更新
+func (s *RuleService) UpdateRule(id int64, req *models.RuleRequest) (*models.Rule, error) {
+	rule, err := buildRule(req)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = id
+	found, err := s.ruleRepo.UpdateRule(rule)
+	if err != nil {
+		return nil, fmt.Errorf("更新规则失败: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return s.ruleRepo.GetRule(id)
+}
+
+// DeleteRule 删除规则及其匹配结果
+func (s *RuleService) DeleteRule(id int64) (bool, error) {
+	return s.ruleRepo.DeleteRule(id)
+}
+
+// ScanLog 对日志中的文本文件运行所有启用的规则，替换旧的匹配结果
+// 多个条件以文件为单位判断：all 要求每个条件都在文件中出现，any 要求至少一个出现；否定条件出现时不匹配
+func (s *RuleService) ScanLog(logID string) (*models.RuleScanResult, error) {
+	rules, err := s.ruleRepo.ListRules(true)
+	if err != nil {
+		return nil, err
+	}
+	compiled := make([]*compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		c, err := compileRule(rule)
+		if err != nil {
+			// 规则在保存时已校验，这里只会是手工修改数据库导致的错误
+			continue
+		}
+		compiled = append(compiled, c)
+	}
+
+	result := &models.RuleScanResult{LogID: logID, Rules: len(compiled)}
+	findings := []models.Finding{}
+	if len(compiled) > 0 {
+		var files []string
+		err = s.fileService.WalkTextFiles(logID, func(relPath string, size int64) error {
+			files = append(files, relPath)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			var applicable []*compiledRule
+			for _, c := range compiled {
+				if matchFileGlob(c.rule.FileGlob, file) {
+					applicable = append(applicable, c)
+				}
+			}
+			if len(applicable) == 0 {
+				continue
+			}
+			result.Files++
+
+			fileFindings, err := s.scanFile(logID, file, applicable)
+			if err != nil {
+				return nil, err
+			}
+			findings = append(findings, fileFindings...)
+		}
+	}
+
+	if err := s.ruleRepo.ReplaceFindings(logID, findings); err != nil {
+		return nil, fmt.Errorf("保存规则匹配结果失败: %w", err)
+	}
+	result.Findings = len(findings)
+	return result, nil
+}
+
+// scanFile 在单个文件上运行规则
+func (s *RuleService) scanFile(logID, file string, rules []*compiledRule) ([]models.Finding, error) {
+	matches := make([]*ruleMatch, len(rules))
+	for i, c := range rules {
+		matches[i] = &ruleMatch{matched: make([]bool, len(c.conditions))}
+	}
+
+	// 规则在原文上匹配；保存的内容中遮盖跨行的块（如私钥），块内的行单独看不出是否敏感
+	stream := s.redaction.Stream(false)
+	err := s.fileService.ForEachLine(logID, file, func(lineNo int, line string) error {
+		shown := stream.Blocks(line)
+		for i, c := range rules {
+			m := matches[i]
+			lineMatched := false
+			for j, re := range c.conditions {
+				if !re.MatchString(line) {
+					continue
+				}
+				m.matched[j] = true
+				if !c.negate[j] {
+					lineMatched = true
+				}
+			}
+			if lineMatched {
+				m.count++
+				if m.firstLine == 0 {
+					m.firstLine = lineNo
+					m.content = truncateString(shown, maxFindingContent)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []models.Finding
+	for i, c := range rules {
+		m := matches[i]
+		if !c.satisfied(m.matched) {
+			continue
+		}
+		findings = append(findings, models.Finding{
+			RuleID:   c.rule.ID,
+			FilePath: file,
+			Line:     m.firstLine,
+			Content:  m.content,
+			Count:    m.count,
+		})
+	}
+	return findings, nil
+}
+
+// satisfied 根据各条件是否出现判断规则是否匹配
+func (c *compiledRule) satisfied(matched []bool) bool {
+	anyPositive, allPositive := false, true
+	for j, ok := range matched {
+		if c.negate[j] {
+			if ok {
+				return false
+			}
+			continue
+		}
+		anyPositive = anyPositive || ok
+		allPositive = allPositive && ok
+	}
+	if c.rule.Match == models.RuleMatchAny {
+		return anyPositive
+	}
+	return allPositive
+}
+
+// truncateString 按字节截断字符串，不截断多字节字符
+func truncateString(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	for maxLen > 0 && !utf8.RuneStart(s[maxLen]) {
+		maxLen--
+	}
+	return s[:maxLen] + "..."
+}
+
+// GetFindings 获取日志的规则匹配结果，raw为false时对匹配行脱敏
+func (s *RuleService) GetFindings(logID string, raw bool) (*models.FindingResult, error) {
+	findings, err := s.ruleRepo.ListFindings(logID)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.FindingResult{
+		LogID:      logID,
+		Total:      len(findings),
+		Severities: make(map[string]int),
+		Findings:   findings,
+	}
+	for i := range findings {
+		findings[i].Content = s.redaction.Redact(findings[i].Content, raw)
+		result.Severities[findings[i].Severity]++
+	}
+	return result, nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"logview-goversion/internal/models"
+	"testing"
+)
+
+func TestCompiledRuleSatisfied(t *testing.T) {
+	tests := []struct {
+		name       string
+		match      string
+		conditions []models.RuleCondition
+		matched    []bool
+		want       bool
+	}{
+		{
+			name:       "全部条件出现",
+			match:      models.RuleMatchAll,
+			conditions: []models.RuleCondition{{Pattern: "a"}, {Pattern: "b"}},
+			matched:    []bool{true, true},
+			want:       true,
+		},
+		{
+			name:       "缺少一个条件",
+			match:      models.RuleMatchAll,
+			conditions: []models.RuleCondition{{Pattern: "a"}, {Pattern: "b"}},
+			matched:    []bool{true, false},
+			want:       false,
+		},
+		{
+			name:       "任一条件出现",
+			match:      models.RuleMatchAny,
+			conditions: []models.RuleCondition{{Pattern: "a"}, {Pattern: "b"}},
+			matched:    []bool{false, true},
+			want:       true,
+		},
+		{
+			name:       "没有条件出现",
+			match:      models.RuleMatchAny,
+			conditions: []models.RuleCondition{{Pattern: "a"}, {Pattern: "b"}},
+			matched:    []bool{false, false},
+			want:       false,
+		},
+		{
+			name:       "否定条件未出现",
+			match:      models.RuleMatchAll,
+			conditions: []models.RuleCondition{{Pattern: "a"}, {Pattern: "b", Negate: true}},
+			matched:    []bool{true, false},
+			want:       true,
+		},
+		{
+			name:       "否定条件出现",
+			match:      models.RuleMatchAll,
+			conditions: []models.RuleCondition{{Pattern: "a"}, {Pattern: "b", Negate: true}},
+			matched:    []bool{true, true},
+			want:       false,
+		},
+		{
+			name:       "任一模式下否定条件出现",
+			match:      models.RuleMatchAny,
+			conditions: []models.RuleCondition{{Pattern: "a"}, {Pattern: "c"}, {Pattern: "b", Negate: true}},
+			matched:    []bool{true, true, true},
+			want:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := compileRule(models.Rule{Match: tt.match, Conditions: tt.conditions})
+			if err != nil {
+				t.Fatalf("compileRule() 错误: %v", err)
+			}
+			if got := c.satisfied(tt.matched); got != tt.want {
+				t.Errorf("satisfied(%v) = %v, 期望 %v", tt.matched, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    models.Rule
+		count   int
+		wantErr bool
+	}{
+		{name: "pattern 作为第一个条件", rule: models.Rule{Pattern: "x", Conditions: []models.RuleCondition{{Pattern: "y"}}}, count: 2},
+		{name: "只有否定条件", rule: models.Rule{Conditions: []models.RuleCondition{{Pattern: "y", Negate: true}}}, wantErr: true},
+		{name: "没有条件", rule: models.Rule{}, wantErr: true},
+		{name: "无效的正则", rule: models.Rule{Pattern: "("}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := compileRule(tt.rule)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("compileRule() 应返回错误")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("compileRule() 错误: %v", err)
+			}
+			if len(c.conditions) != tt.count || c.conditions[0].String() != tt.rule.Pattern {
+				t.Errorf("条件 = %v, 期望 %d 个且以 %q 开头", c.conditions, tt.count, tt.rule.Pattern)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"fmt"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/pkg/logparser"
+	"logview-goversion/internal/repository"
+	"strings"
+)
+
+// SavedSearchService 保存的搜索服务
+type SavedSearchService struct {
+	savedSearchRepo *repository.SavedSearchRepository
+	searchService   *SearchService
+}
+
+// NewSavedSearchService 创建保存的搜索服务
+func NewSavedSearchService(savedSearchRepo *repository.SavedSearchRepository, searchService *SearchService) *SavedSearchService {
+	return &SavedSearchService{
+		savedSearchRepo: savedSearchRepo,
+		searchService:   searchService,
+	}
+}
+
+// buildSavedSearch 校验请求并转换为保存的搜索
+func buildSavedSearch(req *models.SavedSearchRequest) (*models.SavedSearch, error) {
+	search := &models.SavedSearch{
+		Name:     strings.TrimSpace(req.Name),
+		Pattern:  req.Pattern,
+		Levels:   []string{},
+		FileGlob: strings.TrimSpace(req.FileGlob),
+		Owner:    strings.TrimSpace(req.Owner),
+	}
+	if search.Name == "" {
+		return nil, fmt.Errorf(models.ErrEmptyName)
+	}
+	for _, item := range req.Levels {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		level := logparser.NormalizeLevel(item)
+		if level == "" {
+			return nil, fmt.Errorf("%s: %s", models.ErrInvalidLevel, item)
+		}
+		search.Levels = append(search.Levels, level)
+	}
+	if _, err := CompileGrep(grepOptions(search)); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+// ValidateSavedSearch 检查名称、正则、级别和文件匹配模式是否有效
+func (s *SavedSearchService) ValidateSavedSearch(req *models.SavedSearchRequest) error {
+	_, err := buildSavedSearch(req)
+	return err
+}
+
+// grepOptions 保存的搜索对应的正则搜索条件
+func grepOptions(search *models.SavedSearch) models.GrepOptions {
+	return models.GrepOptions{
+		Pattern:  search.Pattern,
+		Levels:   search.Levels,
+		FileGlob: search.FileGlob,
+	}
+}
+
+// CreateSavedSearch 创建保存的搜索
+func (s *SavedSearchService) CreateSavedSearch(req *models.SavedSearchRequest) (*models.SavedSearch, error) {
+	search, err := buildSavedSearch(req)
+	if err != nil {
+		return nil, err
+	}
+	id, err := s.savedSearchRepo.Create(search)
+	if err != nil {
+		return nil, fmt.Errorf("保存搜索失败: %w", err)
+	}
+	return s.savedSearchRepo.GetByID(id)
+}
+
+// ListSavedSearches 获取保存的搜索列表，owner不为空时只返回该用户的
+func (s *SavedSearchService) ListSavedSearches(owner string) ([]models.SavedSearch, error) {
+	return s.savedSearchRepo.List(strings.TrimSpace(owner))
+}
+
+// GetSavedSearch 获取保存的搜索，不存在时返回nil
+func (s *SavedSearchService) GetSavedSearch(id int64) (*models.SavedSearch, error) {
+	return s.savedSearchRepo.GetByID(id)
+}
+
+// UpdateSavedSearch 更新保存的搜索，不存在时返回nil
+func (s *SavedSearchService) UpdateSavedSearch(id int64, req *models.SavedSearchRequest) (*models.SavedSearch, error) {
+	search, err := buildSavedSearch(req)
+	if err != nil {
+		return nil, err
+	}
+	search.ID = id
+	found, err := s.savedSearchRepo.Update(search)
+	if err != nil {
+		return nil, fmt.Errorf("更新搜索失败: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return s.savedSearchRepo.GetByID(id)
+}
+
+// DeleteSavedSearch 删除保存的搜索
+func (s *SavedSearchService) DeleteSavedSearch(id int64) (bool, error) {
+	return s.savedSearchRepo.Delete(id)
+}
+
+// RunSavedSearch 在指定日志上执行保存的搜索，搜索不存在时返回nil
+func (s *SavedSearchService) RunSavedSearch(id int64, logID string, offset, limit int, raw bool) (*models.GrepResult, error) {
+	search, err := s.savedSearchRepo.GetByID(id)
+	if err != nil || search == nil {
+		return nil, err
+	}
+
+	opts := grepOptions(search)
+	opts.Offset = offset
+	opts.Limit = limit
+	opts.Raw = raw
+	result, err := s.searchService.Grep(logID, opts)
+	if err != nil {
+		return nil, err
+	}
+	result.Search = search
+	return result, nil
+}
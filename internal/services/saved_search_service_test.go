@@ -0,0 +1,110 @@
+package services
+
+import (
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/repository"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildSavedSearch(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.SavedSearchRequest
+		levels  []string
+		wantErr string
+	}{
+		{name: "级别统一为标准写法", req: models.SavedSearchRequest{Name: " oom ", Pattern: "Out of memory", Levels: []string{"err", " ", "warning"}}, levels: []string{"ERROR", "WARN"}},
+		{name: "没有级别", req: models.SavedSearchRequest{Name: "io", Pattern: "I/O error", FileGlob: "var/log/*"}, levels: []string{}},
+		{name: "缺少名称", req: models.SavedSearchRequest{Name: " ", Pattern: "x"}, wantErr: models.ErrEmptyName},
+		{name: "缺少正则", req: models.SavedSearchRequest{Name: "x"}, wantErr: models.ErrEmptyQuery},
+		{name: "无效的正则", req: models.SavedSearchRequest{Name: "x", Pattern: "("}, wantErr: models.ErrInvalidPattern},
+		{name: "无效的级别", req: models.SavedSearchRequest{Name: "x", Pattern: "x", Levels: []string{"loud"}}, wantErr: models.ErrInvalidLevel},
+		{name: "无效的文件匹配模式", req: models.SavedSearchRequest{Name: "x", Pattern: "x", FileGlob: "["}, wantErr: models.ErrInvalidGlob},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search, err := buildSavedSearch(&tt.req)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Errorf("buildSavedSearch() 错误 = %v, 期望 %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if search.Name != strings.TrimSpace(tt.req.Name) {
+				t.Errorf("Name = %q", search.Name)
+			}
+			if !reflect.DeepEqual(search.Levels, tt.levels) {
+				t.Errorf("Levels = %v, 期望 %v", search.Levels, tt.levels)
+			}
+		})
+	}
+}
+
+func TestRunSavedSearch(t *testing.T) {
+	content := strings.Join([]string{
+		"2024-01-01T00:00:01Z INFO Out of memory: Killed process 42",
+		"2024-01-01T00:00:02Z ERROR Out of memory: Killed process 43",
+		"2024-01-01T00:00:03Z ERROR disk full",
+	}, "\n")
+	searchService, _ := newGrepTestService(t, map[string]string{"var/log/kern.log": content, "app.log": content})
+	logRepo, err := repository.NewLogRepository(filepath.Join(t.TempDir(), "searches.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logRepo.Close()
+	repo, err := repository.NewSavedSearchRepository(logRepo.DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewSavedSearchService(repo, searchService)
+
+	search, err := s.CreateSavedSearch(&models.SavedSearchRequest{
+		Name: "oom", Pattern: `Out of memory: Killed process \d+`, Levels: []string{"error"}, FileGlob: "var/log/*", Owner: "alice",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := s.RunSavedSearch(search.ID, "log1", 0, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Search == nil || result.Search.ID != search.ID {
+		t.Errorf("Search = %+v, 期望保存的搜索 %d", result.Search, search.ID)
+	}
+	if result.Total != 1 || len(result.Hits) != 1 || result.Hits[0].File != "var/log/kern.log" || result.Hits[0].Line != 2 {
+		t.Errorf("RunSavedSearch() = %+v, 期望只命中 var/log/kern.log 第2行", result)
+	}
+
+	// 更新后按新条件执行
+	if _, err := s.UpdateSavedSearch(search.ID, &models.SavedSearchRequest{Name: "oom", Pattern: "Out of memory", Owner: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if result, err = s.RunSavedSearch(search.ID, "log1", 0, 0, false); err != nil || result.Total != 4 {
+		t.Errorf("更新后 Total = %+v, %v, 期望 4", result, err)
+	}
+
+	if list, err := s.ListSavedSearches(" alice "); err != nil || len(list) != 1 || list[0].Pattern != "Out of memory" {
+		t.Errorf("ListSavedSearches(alice) = %+v, %v, 期望更新后的搜索", list, err)
+	}
+	if list, err := s.ListSavedSearches("bob"); err != nil || len(list) != 0 {
+		t.Errorf("ListSavedSearches(bob) = %+v, %v, 期望为空", list, err)
+	}
+
+	if deleted, err := s.DeleteSavedSearch(search.ID); err != nil || !deleted {
+		t.Fatalf("DeleteSavedSearch() = %v, %v", deleted, err)
+	}
+	if result, err := s.RunSavedSearch(search.ID, "log1", 0, 0, false); err != nil || result != nil {
+		t.Errorf("删除后 RunSavedSearch() = %+v, %v, 期望 nil", result, err)
+	}
+	if updated, err := s.UpdateSavedSearch(search.ID, &models.SavedSearchRequest{Name: "x", Pattern: "x"}); err != nil || updated != nil {
+		t.Errorf("删除后 UpdateSavedSearch() = %+v, %v, 期望 nil", updated, err)
+	}
+}
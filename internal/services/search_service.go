@@ -0,0 +1,342 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/pkg/logparser"
+	"logview-goversion/internal/repository"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// 搜索分页默认值
+const (
+	defaultSearchLimit = 100
+	maxSearchLimit     = 1000
+)
+
+// SearchService 全文搜索服务
+type SearchService struct {
+	searchRepo  *repository.SearchRepository
+	fileService *FileService
+	redaction   *RedactionService
+}
+
+// NewSearchService 创建全文搜索服务
+func NewSearchService(searchRepo *repository.SearchRepository, fileService *FileService, redaction *RedactionService) *SearchService {
+	if !searchRepo.Enabled() {
+		log.Printf("警告: %s", models.ErrSearchUnavailable)
+	}
+	svc := &SearchService{
+		searchRepo:  searchRepo,
+		fileService: fileService,
+		redaction:   redaction,
+	}
+	if searchRepo.Enabled() {
+		svc.checkIndex()
+	}
+	return svc
+}
+
+// checkIndex 索引表升级或脱敏规则变化后，在后台重建受影响日志的索引
+// 不查看原始内容的搜索只匹配建立索引时的脱敏结果，规则变化后旧索引中可能保留了新规则要隐藏的值
+func (s *SearchService) checkIndex() {
+	logIDs := s.searchRepo.StaleLogs()
+	fingerprint := s.redaction.Fingerprint()
+	stored, err := s.searchRepo.RulesFingerprint()
+	if err != nil {
+		log.Printf("读取全文索引的脱敏规则失败: %v", err)
+		return
+	}
+	if stored == fingerprint && len(logIDs) == 0 {
+		return
+	}
+	if stored != fingerprint {
+		indexed, err := s.searchRepo.IndexedLogs()
+		if err != nil {
+			log.Printf("读取已索引的日志失败: %v", err)
+			return
+		}
+		logIDs = append(logIDs, indexed...)
+	}
+	if len(logIDs) == 0 {
+		if err := s.searchRepo.SetRulesFingerprint(fingerprint); err != nil {
+			log.Printf("记录全文索引的脱敏规则失败: %v", err)
+		}
+		return
+	}
+	go s.reindex(logIDs, fingerprint)
+}
+
+// reindex 重建日志的索引，全部成功后记录当前的脱敏规则，失败时下次启动重试
+func (s *SearchService) reindex(logIDs []string, fingerprint string) {
+	log.Printf("全文索引表已升级或脱敏规则已变化，正在重建 %d 个日志的索引", len(logIDs))
+	failed := false
+	for _, logID := range logIDs {
+		if _, err := s.IndexLog(logID); err != nil {
+			log.Printf("重建日志 %s 的索引失败: %v", logID, err)
+			failed = true
+		}
+	}
+	if failed {
+		return
+	}
+	if err := s.searchRepo.SetRulesFingerprint(fingerprint); err != nil {
+		log.Printf("记录全文索引的脱敏规则失败: %v", err)
+	}
+}
+
+// OnLogImported 导入完成后建立索引
+func (s *SearchService) OnLogImported(logID string) error {
+	if !s.searchRepo.Enabled() {
+		return nil
+	}
+	_, err := s.IndexLog(logID)
+	return err
+}
+
+// OnLogDeleted 删除日志后清理索引
+func (s *SearchService) OnLogDeleted(logID string) error {
+	return s.searchRepo.DeleteByLogID(logID)
+}
+
+// IndexLog 重建指定日志的全文索引
+func (s *SearchService) IndexLog(logID string) (*models.IndexResult, error) {
+	if !s.searchRepo.Enabled() {
+		return nil, fmt.Errorf(models.ErrSearchUnavailable)
+	}
+
+	// 先清理旧索引，保证重新解压后不会残留已删除的文件
+	if err := s.searchRepo.DeleteByLogID(logID); err != nil {
+		return nil, fmt.Errorf("清理旧索引失败: %w", err)
+	}
+
+	result := &models.IndexResult{LogID: logID}
+	err := s.fileService.WalkTextFiles(logID, func(relPath string, size int64) error {
+		// 边读边写入索引，区分读取文件失败（跳过该文件）和写入索引失败
+		var readFailed bool
+		lines, err := s.searchRepo.IndexFile(logID, relPath, func(add func(lineNo int, line, redacted string) error) error {
+			var indexErr error
+			stream := s.redaction.Stream(false)
+			err := s.fileService.ForEachLine(logID, relPath, func(lineNo int, line string) error {
+				indexErr = add(lineNo, line, stream.Redact(line))
+				return indexErr
+			})
+			readFailed = err != nil && indexErr == nil
+			return err
+		})
+		if readFailed {
+			log.Printf("读取文件 %s 失败，跳过索引: %v", relPath, err)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("索引文件 %s 失败: %w", relPath, err)
+		}
+		result.Files++
+		result.Lines += lines
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Search 在所有已索引的日志中搜索，logID不为空时只搜索该日志
+// raw为false时只在脱敏后的文本上匹配并对摘要脱敏（脱敏规则修改后启动时自动重建索引）
+// 摘要是 HTML，日志内容已转义，命中部分用 <mark> 标签高亮
+func (s *SearchService) Search(query, logID string, offset, limit int, raw bool) (*models.SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf(models.ErrEmptyQuery)
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	result, err := s.searchRepo.Search(query, logID, offset, limit, raw)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result.Hits {
+		result.Hits[i].Snippet = highlightSnippet(s.redaction.RedactSnippet(result.Hits[i].Snippet, raw))
+	}
+	s.expandEvents(result, raw)
+	return result, nil
+}
+
+// snippetHighlight 将摘要中的高亮标记替换为 HTML 标签
+var snippetHighlight = strings.NewReplacer(repository.SnippetMarkStart, "<mark>", repository.SnippetMarkEnd, "</mark>")
+
+// highlightSnippet 转义摘要中的日志内容后再加入高亮标签，日志中的 HTML 不会被浏览器执行
+func highlightSnippet(snippet string) string {
+	return snippetHighlight.Replace(html.EscapeString(snippet))
+}
+
+// expandEvents 命中行属于多行事件（堆栈、panic等）时附带整个事件
+func (s *SearchService) expandEvents(result *models.SearchResult, raw bool) {
+	type fileKey struct{ logID, path string }
+	hitLines := make(map[fileKey][]int)
+	for _, hit := range result.Hits {
+		key := fileKey{hit.LogID, hit.FilePath}
+		hitLines[key] = append(hitLines[key], hit.Line)
+	}
+
+	events := make(map[fileKey]map[int]*logparser.Event, len(hitLines))
+	for key, lines := range hitLines {
+		fileEvents, err := s.fileService.EventsAt(key.logID, key.path, lines)
+		if err != nil {
+			// 索引之后文件可能已被删除，保留原来的命中行
+			continue
+		}
+		events[key] = fileEvents
+	}
+
+	for i := range result.Hits {
+		hit := &result.Hits[i]
+		ev := events[fileKey{hit.LogID, hit.FilePath}][hit.Line]
+		if ev == nil || len(ev.Lines) < 2 {
+			continue
+		}
+		hit.StartLine = ev.StartLine()
+		hit.EndLine = ev.EndLine()
+		hit.Event = s.redaction.Redact(ev.Text(), raw)
+	}
+}
+
+// CompileGrep 校验并编译正则搜索条件
+func CompileGrep(opts models.GrepOptions) (*regexp.Regexp, error) {
+	if strings.TrimSpace(opts.Pattern) == "" {
+		return nil, fmt.Errorf(models.ErrEmptyQuery)
+	}
+	re, err := regexp.Compile(opts.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", models.ErrInvalidPattern, err)
+	}
+	if err := validateFileGlob(opts.FileGlob); err != nil {
+		return nil, err
+	}
+	return re, nil
+}
+
+// validateFileGlob 检查文件匹配模式的语法
+func validateFileGlob(glob string) error {
+	if glob == "" {
+		return nil
+	}
+	if _, err := path.Match(glob, ""); err != nil {
+		return fmt.Errorf("%s: %s", models.ErrInvalidGlob, glob)
+	}
+	return nil
+}
+
+// matchFileGlob 文件匹配模式可以匹配相对路径或文件名
+func matchFileGlob(glob, relPath string) bool {
+	if glob == "" {
+		return true
+	}
+	relPath = filepath.ToSlash(relPath)
+	if ok, _ := path.Match(glob, relPath); ok {
+		return true
+	}
+	ok, _ := path.Match(glob, path.Base(relPath))
+	return ok
+}
+
+// Grep 逐行扫描日志中的文本文件进行正则搜索，不依赖全文索引；raw为false时只匹配脱敏后的文本
+func (s *SearchService) Grep(logID string, opts models.GrepOptions) (*models.GrepResult, error) {
+	re, err := CompileGrep(opts)
+	if err != nil {
+		return nil, err
+	}
+	limit, offset := opts.Limit, opts.Offset
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var files []string
+	err = s.fileService.WalkTextFiles(logID, func(relPath string, size int64) error {
+		if matchFileGlob(opts.FileGlob, relPath) {
+			files = append(files, relPath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	levels := make(map[string]bool, len(opts.Levels))
+	for _, level := range opts.Levels {
+		levels[level] = true
+	}
+
+	result := &models.GrepResult{
+		LogID:  logID,
+		Files:  make(map[string]int),
+		Offset: offset,
+		Limit:  limit,
+		Hits:   []models.GrepHit{},
+	}
+	for _, file := range files {
+		// 以多行事件为单位匹配，堆栈中任意一行命中时返回整个事件
+		stream := s.redaction.Stream(opts.Raw)
+		err := s.fileService.ForEachEvent(logID, file, func(ev *logparser.Event) error {
+			if len(levels) > 0 && !levels[ev.Record.Level] {
+				for _, line := range ev.Lines {
+					stream.Blocks(line)
+				}
+				return nil
+			}
+			// 未请求原始内容时在脱敏后的文本上匹配，避免通过命中数推测被隐藏的值
+			matchedLine := 0
+			lines := make([]string, len(ev.Lines))
+			for i, line := range ev.Lines {
+				lines[i] = stream.Redact(line)
+				if matchedLine == 0 && re.MatchString(lines[i]) {
+					matchedLine = ev.StartLine() + i
+				}
+			}
+			if matchedLine == 0 {
+				return nil
+			}
+
+			result.Total++
+			result.Files[file]++
+			if result.Total > offset && result.Total <= offset+limit {
+				hit := models.GrepHit{
+					File:    file,
+					Line:    matchedLine,
+					Level:   ev.Record.Level,
+					Content: strings.Join(lines, "\n"),
+				}
+				if len(ev.Lines) > 1 {
+					hit.StartLine = ev.StartLine()
+					hit.EndLine = ev.EndLine()
+				}
+				result.Hits = append(result.Hits, hit)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
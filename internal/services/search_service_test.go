@@ -0,0 +1,291 @@
+package services
+
+import (
+	"logview-goversion/internal/config"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/repository"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 全文索引需要使用 -tags sqlite_fts5 构建，否则跳过
+func TestSearchRedactedLiterals(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	cfg.Storage.ZipDir = filepath.Join(dir, "zip")
+	cfg.Storage.ExtractDir = filepath.Join(dir, "extracted")
+	cfg.Storage.MaxFileSize = 1 << 20
+	cfg.Redaction.Enabled = true
+
+	logRepo, err := repository.NewLogRepository(filepath.Join(dir, "logs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logRepo.Close()
+	searchRepo, err := repository.NewSearchRepository(logRepo.DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !searchRepo.Enabled() {
+		t.Skip("未使用 sqlite_fts5 构建")
+	}
+
+	redaction, err := NewRedactionService(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileService := NewFileService(cfg, redaction, nil, nil)
+	s := NewSearchService(searchRepo, fileService, redaction)
+
+	logDir := filepath.Join(cfg.Storage.ExtractDir, "log1")
+	os.MkdirAll(logDir, 0755)
+	content := "login password=hunter2 ok\nuser bob@example.com connected\nlogin ok\n"
+	if err := os.WriteFile(filepath.Join(logDir, "app.log"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.IndexLog("log1"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		raw   bool
+		total int
+	}{
+		{name: "脱敏的口令", query: "hunter2", total: 0},
+		{name: "脱敏的口令（原始内容）", query: "hunter2", raw: true, total: 1},
+		{name: "脱敏的邮箱", query: "bob@example.com", total: 0},
+		{name: "脱敏的邮箱（原始内容）", query: "bob@example.com", raw: true, total: 1},
+		{name: "未脱敏的部分仍可搜索", query: "login", total: 2},
+		{name: "脱敏标记", query: "REDACTED", total: 2},
+		{name: "原始内容中没有脱敏标记", query: "REDACTED", raw: true, total: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.Search(tt.query, "", 0, 0, tt.raw)
+			if err != nil {
+				t.Fatalf("Search() 错误: %v", err)
+			}
+			if result.Total != tt.total || len(result.Hits) != tt.total {
+				t.Errorf("Total/Hits = %d/%d, 期望 %d", result.Total, len(result.Hits), tt.total)
+			}
+			for _, b := range result.Bundles {
+				if b.Hits > tt.total {
+					t.Errorf("日志包 %s 命中数 %d, 期望不超过 %d", b.LogID, b.Hits, tt.total)
+				}
+			}
+			if !tt.raw {
+				for _, hit := range result.Hits {
+					if strings.Contains(hit.Snippet, "hunter2") || strings.Contains(hit.Snippet, "bob@") {
+						t.Errorf("摘要未脱敏: %s", hit.Snippet)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Redaction.Enabled = true
+	redaction, err := NewRedactionService(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const start, end = repository.SnippetMarkStart, repository.SnippetMarkEnd
+	tests := []struct {
+		name    string
+		snippet string
+		raw     bool
+		want    string
+	}{
+		{name: "高亮命中部分", snippet: "login " + start + "failed" + end + " for bob", want: "login <mark>failed</mark> for bob"},
+		{
+			name:    "日志中的HTML被转义",
+			snippet: `<script>alert(1)</script> ` + start + "<img src=x onerror=alert(1)>" + end,
+			want:    `&lt;script&gt;alert(1)&lt;/script&gt; <mark>&lt;img src=x onerror=alert(1)&gt;</mark>`,
+		},
+		{name: "日志中原有的mark标签被转义", snippet: "<mark>x</mark> " + start + "y" + end, want: "&lt;mark&gt;x&lt;/mark&gt; <mark>y</mark>"},
+		{name: "高亮拆开的敏感值整体脱敏", snippet: "password=" + start + "hunter2" + end + " <b>", want: "password=[REDACTED:secret] &lt;b&gt;"},
+		{name: "原始内容", snippet: "password=" + start + "hunter2" + end, raw: true, want: "password=<mark>hunter2</mark>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightSnippet(redaction.RedactSnippet(tt.snippet, tt.raw)); got != tt.want {
+				t.Errorf("摘要 = %q, 期望 %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// 脱敏规则变化后创建搜索服务时在后台重建已有索引
+func TestSearchReindexOnRulesChange(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	cfg.Storage.ZipDir = filepath.Join(dir, "zip")
+	cfg.Storage.ExtractDir = filepath.Join(dir, "extracted")
+	cfg.Storage.MaxFileSize = 1 << 20
+
+	logRepo, err := repository.NewLogRepository(filepath.Join(dir, "logs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logRepo.Close()
+	searchRepo, err := repository.NewSearchRepository(logRepo.DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !searchRepo.Enabled() {
+		t.Skip("未使用 sqlite_fts5 构建")
+	}
+
+	logDir := filepath.Join(cfg.Storage.ExtractDir, "log1")
+	os.MkdirAll(logDir, 0755)
+	if err := os.WriteFile(filepath.Join(logDir, "app.log"), []byte("login password=hunter2 ok\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 未启用脱敏时建立索引
+	off, err := NewRedactionService(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewSearchService(searchRepo, NewFileService(cfg, off, nil, nil), off)
+	if _, err := s.IndexLog("log1"); err != nil {
+		t.Fatal(err)
+	}
+	before, err := s.Search("hunter2", "", 0, 0, false)
+	if err != nil || before.Total != 1 {
+		t.Fatalf("启用脱敏前 Search() = %+v, %v", before, err)
+	}
+	if got := before.Hits[0].Snippet; got != "login password=<mark>hunter2</mark> ok" {
+		t.Errorf("摘要 = %q", got)
+	}
+
+	cfg.Redaction.Enabled = true
+	on, err := NewRedactionService(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if on.Fingerprint() == off.Fingerprint() {
+		t.Fatal("脱敏开关变化后指纹应不同")
+	}
+	s = NewSearchService(searchRepo, NewFileService(cfg, on, nil, nil), on)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		stored, err := searchRepo.RulesFingerprint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored == on.Fingerprint() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("等待重建索引超时")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	result, err := s.Search("hunter2", "", 0, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 0 {
+		t.Errorf("重建索引后仍命中被脱敏的值 %d 次", result.Total)
+	}
+}
+
+// newGrepTestService 创建使用 newFileTestService 中文件的搜索服务
+func newGrepTestService(t *testing.T, files map[string]string) (*SearchService, *repository.SearchRepository) {
+	t.Helper()
+	fileService := newFileTestService(t, files)
+	logRepo, err := repository.NewLogRepository(filepath.Join(t.TempDir(), "logs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { logRepo.Close() })
+	searchRepo, err := repository.NewSearchRepository(logRepo.DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewSearchService(searchRepo, fileService, nil), searchRepo
+}
+
+// 搜索以多行事件为单位匹配和返回
+func TestGrepEvents(t *testing.T) {
+	content := strings.Join([]string{
+		"2024-01-01T00:00:01Z INFO start",
+		"2024-01-01T00:00:02Z ERROR request failed",
+		"java.lang.IllegalStateException: bad state",
+		"\tat com.example.Handler.run(Handler.java:10)",
+		"2024-01-01T00:00:03Z INFO Handler.java is loaded",
+	}, "\n")
+	s, _ := newGrepTestService(t, map[string]string{"app.log": content, "other.txt": "Handler.java\n"})
+
+	type hit struct{ line, start, end int }
+	tests := []struct {
+		name string
+		opts models.GrepOptions
+		hits []hit
+	}{
+		{name: "堆栈中的行命中时返回整个事件", opts: models.GrepOptions{Pattern: `Handler\.java:\d+`, FileGlob: "*.log"}, hits: []hit{{4, 2, 4}}},
+		{name: "单行事件不返回起止行", opts: models.GrepOptions{Pattern: "start"}, hits: []hit{{1, 0, 0}}},
+		{name: "每个事件只计一次", opts: models.GrepOptions{Pattern: "Handler|request", FileGlob: "*.log"}, hits: []hit{{2, 2, 4}, {5, 0, 0}}},
+		{name: "级别按事件首行过滤", opts: models.GrepOptions{Pattern: "Handler", Levels: []string{"ERROR"}}, hits: []hit{{4, 2, 4}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.Grep("log1", tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var hits []hit
+			for _, h := range result.Hits {
+				hits = append(hits, hit{h.Line, h.StartLine, h.EndLine})
+				if h.StartLine > 0 && strings.Count(h.Content, "\n") != h.EndLine-h.StartLine {
+					t.Errorf("事件内容 %q 与起止行 %d-%d 不符", h.Content, h.StartLine, h.EndLine)
+				}
+			}
+			if !reflect.DeepEqual(hits, tt.hits) {
+				t.Errorf("命中 = %v, 期望 %v", hits, tt.hits)
+			}
+			if result.Total != len(tt.hits) {
+				t.Errorf("Total = %d, 期望 %d", result.Total, len(tt.hits))
+			}
+		})
+	}
+}
+
+// 全文搜索命中堆栈中的行时附带整个事件（需要 -tags sqlite_fts5）
+func TestSearchExpandsEvents(t *testing.T) {
+	content := "2024-01-01T00:00:01Z ERROR request failed\n\tat com.example.Handler.run(Handler.java:10)\n2024-01-01T00:00:02Z INFO ok\n"
+	s, searchRepo := newGrepTestService(t, map[string]string{"app.log": content})
+	if !searchRepo.Enabled() {
+		t.Skip("未使用 sqlite_fts5 构建")
+	}
+	if _, err := s.IndexLog("log1"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := s.Search("Handler", "", 0, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Hits) != 1 {
+		t.Fatalf("命中 %d 行, 期望 1", len(result.Hits))
+	}
+	hit := result.Hits[0]
+	want := "2024-01-01T00:00:01Z ERROR request failed\n\tat com.example.Handler.run(Handler.java:10)"
+	if hit.Line != 2 || hit.StartLine != 1 || hit.EndLine != 2 || hit.Event != want {
+		t.Errorf("命中 = %+v", hit)
+	}
+}
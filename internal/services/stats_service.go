@@ -0,0 +1,294 @@
+package services
+
+import (
+	"fmt"
+	"logview-goversion/internal/models"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// statsCacheKind 统计结果在分析缓存中的类型
+	statsCacheKind = "stats"
+	// statsLevelNone 没有级别的行
+	statsLevelNone = "NONE"
+	// autoBucketTarget 自动选择桶大小时的目标桶数
+	autoBucketTarget = 120
+	// maxStatsBuckets 最大桶数，超出时自动放大桶
+	maxStatsBuckets = 10000
+)
+
+// bucketSizes 可选的桶大小（秒），请求的桶大小向上取整到其中之一，每个日志最多缓存这么多份结果
+var bucketSizes = []int64{1, 5, 10, 30, 60, 300, 600, 900, 1800, 3600, 3 * 3600, 6 * 3600, 12 * 3600, 86400, 7 * 86400}
+
+// StatsService 日志级别/时间分布统计服务
+type StatsService struct {
+	fileService *FileService
+	cache       *AnalysisCache
+}
+
+// NewStatsService 创建统计服务
+func NewStatsService(fileService *FileService, cache *AnalysisCache) *StatsService {
+	return &StatsService{
+		fileService: fileService,
+		cache:       cache,
+	}
+}
+
+// GetStats 获取日志包按时间桶和级别的行数统计，bucket为0时自动选择桶大小
+func (s *StatsService) GetStats(logID string, bucket time.Duration) (*models.LogStats, error) {
+	bucketSeconds := snapBucketSize(int64(bucket / time.Second))
+	cacheKey := "auto"
+	if bucketSeconds > 0 {
+		cacheKey = strconv.FormatInt(bucketSeconds, 10)
+	}
+
+	var cached models.LogStats
+	if ok, err := s.cache.Load(logID, statsCacheKind, cacheKey, &cached); err != nil {
+		return nil, err
+	} else if ok {
+		cached.Cached = true
+		return &cached, nil
+	}
+
+	stats, err := s.computeStats(logID, bucketSeconds)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Store(logID, statsCacheKind, cacheKey, stats); err != nil {
+		return nil, fmt.Errorf("保存统计缓存失败: %w", err)
+	}
+	return stats, nil
+}
+
+// computeStats 逐行解析所有文本文件并统计
+func (s *StatsService) computeStats(logID string, bucketSeconds int64) (*models.LogStats, error) {
+	stats := &models.LogStats{
+		LogID:  logID,
+		Levels: map[string]int{},
+		Files:  []models.FileStats{},
+	}
+
+	// 边读边按桶累计，最后再合并为选定的桶大小
+	counter := newBucketCounter(bucketSeconds)
+
+	err := s.fileService.WalkTextFiles(logID, func(relPath string, size int64) error {
+		sample, err := s.fileService.HeadLines(logID, relPath, detectSampleLines)
+		if err != nil {
+			return err
+		}
+		lineParser, err := s.fileService.NewLineParser("", sample)
+		if err != nil {
+			return err
+		}
+
+		file := models.FileStats{
+			Path:   relPath,
+			Format: lineParser.Format(),
+			Levels: map[string]int{},
+		}
+		var lastTime, firstTime time.Time
+		err = s.fileService.ForEachLine(logID, relPath, func(lineNo int, line string) error {
+			rec := lineParser.Parse(lineNo, line)
+			if rec.HasTime() {
+				lastTime = rec.Time
+				if firstTime.IsZero() {
+					firstTime = rec.Time
+				}
+			}
+			level := rec.Level
+			if level == "" {
+				level = statsLevelNone
+			}
+
+			file.Lines++
+			file.Levels[level]++
+			stats.Levels[level]++
+			stats.TotalLines++
+
+			// 没有时间戳的续行沿用上一行的时间
+			if lastTime.IsZero() {
+				stats.UntimedLines++
+				return nil
+			}
+			counter.add(lastTime.Unix(), level)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("统计文件 %s 失败: %w", relPath, err)
+		}
+
+		if !firstTime.IsZero() {
+			file.FirstTime = firstTime.Format(time.RFC3339Nano)
+			file.LastTime = lastTime.Format(time.RFC3339Nano)
+		}
+		stats.Files = append(stats.Files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(stats.Files, func(i, j int) bool {
+		return stats.Files[i].Path < stats.Files[j].Path
+	})
+
+	stats.Buckets = []models.StatsBucket{}
+	if counter.hasTime {
+		bucketSeconds = chooseBucketSize(counter.maxSec-counter.minSec, bucketSeconds)
+		stats.BucketSeconds = bucketSeconds
+		stats.Buckets = buildBuckets(counter.counts, counter.minSec, counter.maxSec, bucketSeconds)
+		stats.Start = time.Unix(counter.minSec, 0).Format(time.RFC3339)
+		stats.End = time.Unix(counter.maxSec, 0).Format(time.RFC3339)
+	}
+	stats.ComputedAt = time.Now().Format(time.RFC3339)
+	return stats, nil
+}
+
+// snapBucketSize 将请求的桶大小向上取整到可选值，超过最大可选值时使用最大值，0表示自动选择
+func snapBucketSize(requested int64) int64 {
+	if requested <= 0 {
+		return 0
+	}
+	for _, size := range bucketSizes {
+		if size >= requested {
+			return size
+		}
+	}
+	return bucketSizes[len(bucketSizes)-1]
+}
+
+// chooseBucketSize 选择桶大小：未指定时使桶数接近目标值，指定的桶过小时放大到不超过最大桶数
+func chooseBucketSize(span, requested int64) int64 {
+	if requested > 0 && span/requested < maxStatsBuckets {
+		return requested
+	}
+	target := int64(autoBucketTarget)
+	if requested > 0 {
+		target = maxStatsBuckets
+	}
+	for _, size := range bucketSizes {
+		if size >= requested && span/size < target {
+			return size
+		}
+	}
+	// 超过最大可选值时取其整数倍，按最大可选值累计的结果可以准确合并
+	last := bucketSizes[len(bucketSizes)-1]
+	if n := span/target + 1; n > last {
+		return (n + last - 1) / last * last
+	}
+	return last
+}
+
+// bucketCounter 按桶累计各级别的行数，时间范围变大时合并为更大的桶
+// 桶大小总能整除最终可能选定的任何桶大小，合并结果与按秒统计后再合并相同，而占用的内存只与桶数有关
+type bucketCounter struct {
+	requested      int64
+	size           int64
+	counts         map[int64]map[string]int // 桶起始时间（秒） -> 级别 -> 行数
+	minSec, maxSec int64
+	hasTime        bool
+}
+
+// newBucketCounter 创建按桶计数器，requested为请求的桶大小（已取整，0表示自动选择）
+func newBucketCounter(requested int64) *bucketCounter {
+	return &bucketCounter{requested: requested, size: 1, counts: make(map[int64]map[string]int)}
+}
+
+// add 累计一行
+func (c *bucketCounter) add(sec int64, level string) {
+	if !c.hasTime || sec < c.minSec || sec > c.maxSec {
+		if !c.hasTime || sec < c.minSec {
+			c.minSec = sec
+		}
+		if !c.hasTime || sec > c.maxSec {
+			c.maxSec = sec
+		}
+		c.hasTime = true
+		if size := countingBucketSize(chooseBucketSize(c.maxSec-c.minSec, c.requested)); size != c.size {
+			c.regroup(size)
+		}
+	}
+
+	key := floorDiv(sec, c.size) * c.size
+	counts, ok := c.counts[key]
+	if !ok {
+		counts = make(map[string]int)
+		c.counts[key] = counts
+	}
+	counts[level]++
+}
+
+// regroup 将已累计的桶合并为更大的桶，新的桶大小是原大小的整数倍
+func (c *bucketCounter) regroup(size int64) {
+	merged := make(map[int64]map[string]int, len(c.counts))
+	for key, counts := range c.counts {
+		newKey := floorDiv(key, size) * size
+		target, ok := merged[newKey]
+		if !ok {
+			merged[newKey] = counts
+			continue
+		}
+		for level, n := range counts {
+			target[level] += n
+		}
+	}
+	c.counts = merged
+	c.size = size
+}
+
+// countingBucketSize 当前选定的桶大小为 chosen 时累计使用的桶大小：不小于 chosen 的所有可能桶大小的最大公约数
+// 时间范围只会变大，最终选定的桶大小不会小于 chosen，因此总能被累计使用的桶大小整除
+func countingBucketSize(chosen int64) int64 {
+	last := bucketSizes[len(bucketSizes)-1]
+	if chosen >= last {
+		return last
+	}
+	var g int64
+	for _, size := range bucketSizes {
+		if size >= chosen {
+			g = gcd(g, size)
+		}
+	}
+	return g
+}
+
+// gcd 最大公约数
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// buildBuckets 将按较小的桶（键为桶起始时间，桶大小能整除size）统计的结果合并为连续的时间桶，空桶也会输出以便绘图
+func buildBuckets(perSecond map[int64]map[string]int, minSec, maxSec, size int64) []models.StatsBucket {
+	first := floorDiv(minSec, size) * size
+	count := (floorDiv(maxSec, size)*size-first)/size + 1
+
+	buckets := make([]models.StatsBucket, count)
+	for i := range buckets {
+		buckets[i] = models.StatsBucket{
+			Start:  time.Unix(first+int64(i)*size, 0).Format(time.RFC3339),
+			Levels: map[string]int{},
+		}
+	}
+	for sec, counts := range perSecond {
+		b := &buckets[(floorDiv(sec, size)*size-first)/size]
+		for level, n := range counts {
+			b.Levels[level] += n
+			b.Total += n
+		}
+	}
+	return buckets
+}
+
+// floorDiv 向下取整的整数除法（时间戳可能为负）
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"logview-goversion/internal/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSnapBucketSize(t *testing.T) {
+	tests := []struct {
+		requested int64
+		want      int64
+	}{
+		{0, 0},
+		{-5, 0},
+		{1, 1},
+		{7, 10},
+		{60, 60},
+		{61, 300},
+		{4000, 3 * 3600},
+		{86400, 86400},
+		{30 * 86400, 7 * 86400},
+	}
+
+	for _, tt := range tests {
+		if got := snapBucketSize(tt.requested); got != tt.want {
+			t.Errorf("snapBucketSize(%d) = %d, 期望 %d", tt.requested, got, tt.want)
+		}
+	}
+
+	// 任意请求值都只会落到有限的几个缓存键上
+	seen := map[int64]bool{}
+	for requested := int64(0); requested <= 8*86400; requested += 97 {
+		seen[snapBucketSize(requested)] = true
+	}
+	if len(seen) > len(bucketSizes)+1 {
+		t.Errorf("取整后的桶大小有 %d 种，期望不超过 %d", len(seen), len(bucketSizes)+1)
+	}
+}
+
+func TestChooseBucketSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		span      int64
+		requested int64
+		want      int64
+	}{
+		{name: "自动选择短时间范围", span: 60, want: 1},
+		{name: "自动选择一小时", span: 3600, want: 60},
+		{name: "自动选择一天", span: 86400, want: 900},
+		{name: "指定的桶大小", span: 3600, requested: 300, want: 300},
+		{name: "指定的桶过小时放大", span: 86400, requested: 1, want: 10},
+		{name: "超过最大可选值时取其整数倍", span: 10 * 365 * 86400, want: 5 * 7 * 86400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chooseBucketSize(tt.span, tt.requested); got != tt.want {
+				t.Errorf("chooseBucketSize(%d, %d) = %d, 期望 %d", tt.span, tt.requested, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildBuckets(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	perSecond := map[int64]map[string]int{
+		base + 5:   {"INFO": 2},
+		base + 59:  {"ERROR": 1},
+		base + 125: {"INFO": 1, "WARN": 3},
+	}
+
+	buckets := buildBuckets(perSecond, base+5, base+125, 60)
+	want := []models.StatsBucket{
+		{Start: time.Unix(base, 0).Format(time.RFC3339), Total: 3, Levels: map[string]int{"INFO": 2, "ERROR": 1}},
+		{Start: time.Unix(base+60, 0).Format(time.RFC3339), Total: 0, Levels: map[string]int{}},
+		{Start: time.Unix(base+120, 0).Format(time.RFC3339), Total: 4, Levels: map[string]int{"INFO": 1, "WARN": 3}},
+	}
+	if !reflect.DeepEqual(buckets, want) {
+		t.Errorf("buildBuckets() = %+v, 期望 %+v", buckets, want)
+	}
+}
+
+func TestBucketCounter(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	levels := []string{"INFO", "WARN", "ERROR"}
+
+	tests := []struct {
+		name      string
+		requested int64
+		secs      []int64
+	}{
+		{name: "自动选择，时间递增", secs: seqSeconds(base, 0, 86400, 7)},
+		{name: "自动选择，时间乱序", secs: append(seqSeconds(base, 3600, 7200, 13), seqSeconds(base, -86400*3, 0, 997)...)},
+		{name: "指定的桶大小", requested: 60, secs: seqSeconds(base, 0, 3*86400, 11)},
+		{name: "指定的桶过小时放大", requested: 1, secs: seqSeconds(base, -5*86400, 5*86400, 31)},
+		{name: "超过最大可选值", secs: append(seqSeconds(base, 0, 60, 1), base+5*365*86400, base-3*365*86400)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := newBucketCounter(tt.requested)
+			perSecond := map[int64]map[string]int{}
+			for i, sec := range tt.secs {
+				level := levels[i%len(levels)]
+				counter.add(sec, level)
+				if perSecond[sec] == nil {
+					perSecond[sec] = map[string]int{}
+				}
+				perSecond[sec][level]++
+
+				// 累计的桶数不随秒数增长
+				if limit := 2*maxStatsBuckets + 1; len(counter.counts) > limit {
+					t.Fatalf("累计了 %d 个桶, 超过 %d", len(counter.counts), limit)
+				}
+			}
+
+			size := chooseBucketSize(counter.maxSec-counter.minSec, tt.requested)
+			if size%counter.size != 0 {
+				t.Fatalf("累计使用的桶大小 %d 不能整除选定的桶大小 %d", counter.size, size)
+			}
+			got := buildBuckets(counter.counts, counter.minSec, counter.maxSec, size)
+			want := buildBuckets(perSecond, counter.minSec, counter.maxSec, size)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("按桶累计的结果与按秒统计的结果不同")
+			}
+		})
+	}
+}
+
+// seqSeconds 从 base+from 到 base+to（不含）每隔 step 秒的时间戳
+func seqSeconds(base, from, to, step int64) []int64 {
+	var secs []int64
+	for sec := from; sec < to; sec += step {
+		secs = append(secs, base+sec)
+	}
+	return secs
+}
+
+func TestFloorDiv(t *testing.T) {
+	tests := []struct{ a, b, want int64 }{
+		{7, 2, 3},
+		{-7, 2, -4},
+		{-6, 2, -3},
+		{0, 5, 0},
+	}
+	for _, tt := range tests {
+		if got := floorDiv(tt.a, tt.b); got != tt.want {
+			t.Errorf("floorDiv(%d, %d) = %d, 期望 %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
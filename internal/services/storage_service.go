@@ -0,0 +1,163 @@
+package services
+
+import (
+	"log"
+	"logview-goversion/internal/config"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/pkg/fileutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// largestBundles 存储统计中列出的最大日志包数量
+const largestBundles = 10
+
+// StorageService 存储统计服务，导入时在日志记录上保存解压后的大小和在磁盘上实际占用的大小
+type StorageService struct {
+	cfg         *config.Config
+	logService  *LogService
+	fileService *FileService
+}
+
+// NewStorageService 创建存储统计服务
+func NewStorageService(cfg *config.Config, logService *LogService, fileService *FileService) *StorageService {
+	return &StorageService{
+		cfg:         cfg,
+		logService:  logService,
+		fileService: fileService,
+	}
+}
+
+// OnLogImported 导入完成后记录日志的大小，需在文件服务保存文件树之后执行
+func (s *StorageService) OnLogImported(logID string) error {
+	return s.updateSize(logID)
+}
+
+// OnLogDeleted 日志记录删除时大小随之删除，无需处理
+func (s *StorageService) OnLogDeleted(logID string) error {
+	return nil
+}
+
+// BackfillSizes 为大小统计加入之前导入的日志记录大小（包括只记录了解压后大小的）
+func (s *StorageService) BackfillSizes() {
+	ids, err := s.logService.GetIDsWithoutSize()
+	if err != nil {
+		log.Printf("读取未记录大小的日志失败: %v", err)
+		return
+	}
+	for _, id := range ids {
+		if err := s.updateSize(id); err != nil {
+			log.Printf("记录日志 %s 的大小失败: %v", id, err)
+		}
+	}
+}
+
+// updateSize 从保存的文件树读取解压后的大小，统计解压目录在磁盘上的大小，写入日志记录
+func (s *StorageService) updateSize(logID string) error {
+	size, err := s.fileService.BundleSize(logID)
+	if err != nil {
+		return err
+	}
+	diskSize, err := s.fileService.BundleDiskSize(logID)
+	if err != nil {
+		return err
+	}
+	return s.logService.UpdateSize(logID, size, diskSize)
+}
+
+// GetStats 统计解压目录的使用情况，并对比数据库记录和磁盘上的目录
+func (s *StorageService) GetStats() (*models.StorageStats, error) {
+	logs, err := s.logService.GetAllLogs()
+	if err != nil {
+		return nil, err
+	}
+	extractDir := s.cfg.Storage.ExtractDir
+	onDisk, err := listBundleDirs(extractDir)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.StorageStats{
+		ExtractDir: extractDir,
+		Bundles:    []models.BundleUsage{},
+		Orphaned:   []string{},
+		Missing:    []string{},
+		DBCount:    len(logs),
+		DiskCount:  len(onDisk),
+	}
+
+	inDB := make(map[string]bool, len(logs))
+	for _, l := range logs {
+		inDB[l.LogID] = true
+		usage := models.BundleUsage{LogID: l.LogID, Size: l.DiskSize, LogicalSize: l.Size, InDB: true, OnDisk: onDisk[l.LogID]}
+		if !usage.OnDisk {
+			usage.Size, usage.LogicalSize = 0, 0
+			stats.Missing = append(stats.Missing, l.LogID)
+		}
+		stats.Bundles = append(stats.Bundles, usage)
+		stats.TotalBytes += usage.Size
+	}
+
+	var orphaned []string
+	for id := range onDisk {
+		if !inDB[id] {
+			orphaned = append(orphaned, id)
+		}
+	}
+	sort.Strings(orphaned)
+	for _, id := range orphaned {
+		dir := filepath.Join(extractDir, id)
+		size, err := s.fileService.fileUtil.Storage().DiskSize(dir)
+		if err != nil {
+			log.Printf("统计目录 %s 的大小失败: %v", dir, err)
+		}
+		stats.Orphaned = append(stats.Orphaned, id)
+		stats.Bundles = append(stats.Bundles, models.BundleUsage{LogID: id, Size: size, LogicalSize: s.dirSize(dir), OnDisk: true})
+		stats.TotalBytes += size
+	}
+
+	largest := make([]models.BundleUsage, len(stats.Bundles))
+	copy(largest, stats.Bundles)
+	sort.SliceStable(largest, func(i, j int) bool { return largest[i].Size > largest[j].Size })
+	if len(largest) > largestBundles {
+		largest = largest[:largestBundles]
+	}
+	stats.Largest = largest
+
+	usage, err := fileutil.GetDiskUsage(extractDir)
+	if err == nil {
+		stats.Volume = &models.VolumeUsage{Total: usage.Total, Free: usage.Free, Available: usage.Available}
+	} else if err != fileutil.ErrDiskUsageUnsupported {
+		log.Printf("查询磁盘空间失败: %v", err)
+	}
+	return stats, nil
+}
+
+// listBundleDirs 列出解压目录下的日志目录
+func listBundleDirs(extractDir string) (map[string]bool, error) {
+	dirs := make(map[string]bool)
+	entries, err := os.ReadDir(extractDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return dirs, nil
+		}
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			dirs[e.Name()] = true
+		}
+	}
+	return dirs, nil
+}
+
+// dirSize 目录下所有文件解压后的大小之和（与日志记录的 size 一致），无法访问的文件跳过
+func (s *StorageService) dirSize(dir string) int64 {
+	var size int64
+	s.fileService.fileUtil.WalkFiles(dir, func(relPath, fullPath string, info os.FileInfo) error {
+		size += info.Size()
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"logview-goversion/internal/models"
+	"strings"
+)
+
+// RenderSummaryMarkdown 将概览渲染为Markdown，便于粘贴到工单中
+func RenderSummaryMarkdown(summary *models.BundleSummary) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# 日志包概览：%s\n\n", summary.LogID)
+	fmt.Fprintf(&sb, "- 文件：%d 个（文本文件 %d 个），共 %s\n", summary.Files, summary.TextFiles, formatSize(summary.TotalSize))
+	if summary.Start != "" {
+		fmt.Fprintf(&sb, "- 时间范围：%s ~ %s\n", summary.Start, summary.End)
+	}
+	fmt.Fprintf(&sb, "- 日志行数：%d，错误 %d，警告 %d\n", summary.TotalLines, summary.Errors, summary.Warnings)
+	fmt.Fprintf(&sb, "- 规则匹配：%d 条\n", len(summary.Findings))
+
+	if len(summary.Findings) > 0 {
+		sb.WriteString("\n## 规则匹配\n\n| 严重程度 | 规则 | 文件 | 行号 | 匹配行数 | 处理建议 |\n|---|---|---|---|---|---|\n")
+		for _, f := range summary.Findings {
+			fmt.Fprintf(&sb, "| %s | %s | %s | %d | %d | %s |\n", f.Severity, markdownCell(f.RuleName),
+				markdownCell(f.FilePath), f.Line, f.Count, markdownCell(f.Remediation))
+		}
+	}
+
+	if len(summary.TopErrors) > 0 {
+		sb.WriteString("\n## 主要错误\n\n| 次数 | 级别 | 模板 |\n|---|---|---|\n")
+		for _, p := range summary.TopErrors {
+			fmt.Fprintf(&sb, "| %d | %s | `%s` |\n", p.Count, p.Level, markdownCell(strings.ReplaceAll(p.Template, "`", "'")))
+		}
+	}
+
+	if len(summary.FileLevels) > 0 {
+		sb.WriteString("\n## 各文件错误/警告\n\n| 文件 | 行数 | 错误 | 警告 |\n|---|---|---|---|\n")
+		for _, f := range summary.FileLevels {
+			fmt.Fprintf(&sb, "| %s | %d | %d | %d |\n", markdownCell(f.Path), f.Lines, f.Errors, f.Warnings)
+		}
+	}
+
+	if len(summary.LargestFiles) > 0 {
+		sb.WriteString("\n## 最大的文件\n\n| 文件 | 大小 |\n|---|---|\n")
+		for _, f := range summary.LargestFiles {
+			fmt.Fprintf(&sb, "| %s | %s |\n", markdownCell(f.Path), formatSize(f.Size))
+		}
+	}
+
+	fmt.Fprintf(&sb, "\n_生成时间：%s_\n", summary.GeneratedAt)
+	return sb.String()
+}
+
+// markdownCell 转义表格单元格中的竖线和换行
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, "\n", " ")), " ")
+}
+
+// summaryHTMLTemplate 概览的HTML模板，内容由 html/template 转义
+var summaryHTMLTemplate = template.Must(template.New("summary").Funcs(template.FuncMap{
+	"size": formatSize,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>日志包概览：{{.LogID}}</title>
+<style>
+body { font-family: sans-serif; margin: 24px; }
+table { border-collapse: collapse; margin-bottom: 16px; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+code { white-space: pre-wrap; word-break: break-all; }
+.critical, .high { color: #c00; font-weight: bold; }
+.medium { color: #c60; }
+</style>
+</head>
+<body>
+<h1>日志包概览：{{.LogID}}</h1>
+<ul>
+<li>文件：{{.Files}} 个（文本文件 {{.TextFiles}} 个），共 {{size .TotalSize}}</li>
+{{if .Start}}<li>时间范围：{{.Start}} ~ {{.End}}</li>
+{{end}}<li>日志行数：{{.TotalLines}}，错误 {{.Errors}}，警告 {{.Warnings}}</li>
+<li>规则匹配：{{len .Findings}} 条</li>
+</ul>
+{{if .Findings}}<h2>规则匹配</h2>
+<table>
+<tr><th>严重程度</th><th>规则</th><th>文件</th><th>行号</th><th>匹配行数</th><th>内容</th><th>处理建议</th></tr>
+{{range .Findings}}<tr><td class="{{.Severity}}">{{.Severity}}</td><td>{{.RuleName}}</td><td>{{.FilePath}}</td><td>{{.Line}}</td><td>{{.Count}}</td><td><code>{{.Content}}</code></td><td>{{.Remediation}}</td></tr>
+{{end}}</table>
+{{end}}{{if .TopErrors}}<h2>主要错误</h2>
+<table>
+<tr><th>次数</th><th>级别</th><th>模板</th><th>文件</th></tr>
+{{range .TopErrors}}<tr><td>{{.Count}}</td><td>{{.Level}}</td><td><code>{{.Template}}</code></td><td>{{range $i, $f := .Files}}{{if $i}}<br>{{end}}{{$f}}{{end}}</td></tr>
+{{end}}</table>
+{{end}}{{if .FileLevels}}<h2>各文件错误/警告</h2>
+<table>
+<tr><th>文件</th><th>行数</th><th>错误</th><th>警告</th></tr>
+{{range .FileLevels}}<tr><td>{{.Path}}</td><td>{{.Lines}}</td><td>{{.Errors}}</td><td>{{.Warnings}}</td></tr>
+{{end}}</table>
+{{end}}{{if .LargestFiles}}<h2>最大的文件</h2>
+<table>
+<tr><th>文件</th><th>大小</th></tr>
+{{range .LargestFiles}}<tr><td>{{.Path}}</td><td>{{size .Size}}</td></tr>
+{{end}}</table>
+{{end}}<p><small>生成时间：{{.GeneratedAt}}</small></p>
+</body>
+</html>
+`))
+
+// RenderSummaryHTML 将概览渲染为独立的HTML页面
+func RenderSummaryHTML(summary *models.BundleSummary) (string, error) {
+	var buf bytes.Buffer
+	if err := summaryHTMLTemplate.Execute(&buf, summary); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// formatSize 以 B/KB/MB/GB 显示文件大小
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size) / unit
+	for _, suffix := range []string{"KB", "MB", "GB"} {
+		if value < unit {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%.1f TB", value)
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"fmt"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/pkg/logparser"
+	"sort"
+	"time"
+)
+
+const (
+	// summaryCacheKind 概览在分析缓存中的类型
+	summaryCacheKind = "summary"
+	// summaryLargestFiles 概览中列出的最大文件数量
+	summaryLargestFiles = 10
+	// summaryTopErrors 概览中列出的错误模板数量
+	summaryTopErrors = 10
+)
+
+// SummaryService 日志包概览服务，导入完成后计算并保存
+type SummaryService struct {
+	fileService    *FileService
+	statsService   *StatsService
+	patternService *PatternService
+	ruleService    *RuleService
+	cache          *AnalysisCache
+	redaction      *RedactionService
+}
+
+// NewSummaryService 创建日志包概览服务
+func NewSummaryService(fileService *FileService, statsService *StatsService, patternService *PatternService,
+	ruleService *RuleService, cache *AnalysisCache, redaction *RedactionService) *SummaryService {
+	return &SummaryService{
+		fileService:    fileService,
+		statsService:   statsService,
+		patternService: patternService,
+		ruleService:    ruleService,
+		cache:          cache,
+		redaction:      redaction,
+	}
+}
+
+// OnLogImported 导入完成后计算概览（需在分析缓存和规则服务之后注册）
+func (s *SummaryService) OnLogImported(logID string) error {
+	_, err := s.computeAndStore(logID)
+	return err
+}
+
+// OnLogDeleted 概览保存在分析缓存中，随缓存一起删除
+func (s *SummaryService) OnLogDeleted(logID string) error {
+	return nil
+}
+
+// GetSummary 获取日志包概览，未计算过时立即计算；raw为false时对错误模板和匹配行脱敏
+func (s *SummaryService) GetSummary(logID string, raw bool) (*models.BundleSummary, error) {
+	var summary models.BundleSummary
+	ok, err := s.cache.Load(logID, summaryCacheKind, "", &summary)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		summary.Cached = true
+	} else {
+		computed, err := s.computeAndStore(logID)
+		if err != nil {
+			return nil, err
+		}
+		summary = *computed
+	}
+
+	findings, err := s.ruleService.GetFindings(logID, raw)
+	if err != nil {
+		return nil, err
+	}
+	summary.Findings = findings.Findings
+	summary.Severities = findings.Severities
+
+	for i := range summary.TopErrors {
+		pattern := &summary.TopErrors[i]
+		pattern.Template = s.redaction.Redact(pattern.Template, raw)
+		for j := range pattern.Examples {
+			pattern.Examples[j].Content = s.redaction.Redact(pattern.Examples[j].Content, raw)
+		}
+	}
+	return &summary, nil
+}
+
+// computeAndStore 计算概览并保存到分析缓存
+func (s *SummaryService) computeAndStore(logID string) (*models.BundleSummary, error) {
+	summary, err := s.computeSummary(logID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Store(logID, summaryCacheKind, "", summary); err != nil {
+		return nil, fmt.Errorf("保存日志包概览失败: %w", err)
+	}
+	return summary, nil
+}
+
+// computeSummary 汇总文件结构、级别统计和错误模板
+func (s *SummaryService) computeSummary(logID string) (*models.BundleSummary, error) {
+	tree, err := s.fileService.GetFileStructure(logID)
+	if err != nil {
+		return nil, err
+	}
+	summary := &models.BundleSummary{
+		LogID:        logID,
+		LargestFiles: []models.SummaryFile{},
+		FileLevels:   []models.SummaryFileLevels{},
+		TopErrors:    []models.Pattern{},
+	}
+
+	var files []models.SummaryFile
+	collectFiles(tree, &files)
+	summary.Files = len(files)
+	for _, f := range files {
+		summary.TotalSize += f.Size
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Size > files[j].Size
+	})
+	if len(files) > summaryLargestFiles {
+		files = files[:summaryLargestFiles]
+	}
+	summary.LargestFiles = append(summary.LargestFiles, files...)
+
+	stats, err := s.statsService.GetStats(logID, 0)
+	if err != nil {
+		return nil, err
+	}
+	summary.TextFiles = len(stats.Files)
+	summary.TotalLines = stats.TotalLines
+	summary.Start = stats.Start
+	summary.End = stats.End
+	for _, f := range stats.Files {
+		levels := models.SummaryFileLevels{
+			Path:     f.Path,
+			Lines:    f.Lines,
+			Errors:   f.Levels[logparser.LevelError] + f.Levels[logparser.LevelFatal],
+			Warnings: f.Levels[logparser.LevelWarn],
+		}
+		summary.Errors += levels.Errors
+		summary.Warnings += levels.Warnings
+		if levels.Errors > 0 || levels.Warnings > 0 {
+			summary.FileLevels = append(summary.FileLevels, levels)
+		}
+	}
+	sort.SliceStable(summary.FileLevels, func(i, j int) bool {
+		a, b := summary.FileLevels[i], summary.FileLevels[j]
+		if a.Errors != b.Errors {
+			return a.Errors > b.Errors
+		}
+		return a.Warnings > b.Warnings
+	})
+
+	if summary.Errors > 0 {
+		// 缓存中保存原始内容，返回时再按请求脱敏
+		patterns, err := s.patternService.GetPatterns(logID, models.PatternOptions{
+			Levels: []string{logparser.LevelFatal, logparser.LevelError},
+			Limit:  summaryTopErrors,
+			Raw:    true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		summary.TopErrors = patterns.Patterns
+	}
+
+	summary.GeneratedAt = time.Now().Format(time.RFC3339)
+	return summary, nil
+}
+
+// collectFiles 收集文件树中的所有文件
+func collectFiles(node *models.FileNode, files *[]models.SummaryFile) {
+	if node == nil {
+		return
+	}
+	if node.Type == "file" {
+		*files = append(*files, models.SummaryFile{Path: node.Path, Size: node.Size})
+		return
+	}
+	for _, child := range node.Children {
+		collectFiles(child, files)
+	}
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"logview-goversion/internal/config"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/repository"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newSummaryTestService 在临时目录中写入日志包 log1 的文件并创建概览服务，同时返回规则服务
+func newSummaryTestService(t *testing.T, files map[string]string) (*SummaryService, *RuleService) {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	cfg.Storage.ZipDir = filepath.Join(dir, "zip")
+	cfg.Storage.ExtractDir = filepath.Join(dir, "extracted")
+	cfg.Storage.MaxFileSize = 1 << 20
+	cfg.Storage.MaxPreview = 1 << 20
+	cfg.Redaction.Enabled = true
+
+	for name, content := range files {
+		path := filepath.Join(cfg.Storage.ExtractDir, "log1", filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	logRepo, err := repository.NewLogRepository(filepath.Join(dir, "logs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { logRepo.Close() })
+	indexRepo, err := repository.NewFileIndexRepository(logRepo.DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	treeRepo, err := repository.NewFileTreeRepository(logRepo.DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysisRepo, err := repository.NewAnalysisRepository(logRepo.DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ruleRepo, err := repository.NewRuleRepository(logRepo.DB())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redaction, err := NewRedactionService(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileService := NewFileService(cfg, redaction, indexRepo, treeRepo)
+	cache := NewAnalysisCache(analysisRepo)
+	ruleService := NewRuleService(ruleRepo, fileService, redaction)
+	s := NewSummaryService(fileService, NewStatsService(fileService, cache),
+		NewPatternService(fileService, redaction), ruleService, cache, redaction)
+	return s, ruleService
+}
+
+func TestGetSummary(t *testing.T) {
+	s, ruleService := newSummaryTestService(t, map[string]string{
+		"app.log": strings.Join([]string{
+			"2024-01-01T00:00:01Z INFO started",
+			"2024-01-01T00:00:02Z ERROR login failed for alice@example.com",
+			"2024-01-01T00:00:03Z ERROR login failed for bob@example.com",
+			"2024-01-01T00:00:04Z WARN slow request",
+		}, "\n"),
+		"sub/worker.log": "2024-01-01T00:00:05Z WARN queue backlog\n2024-01-01T00:00:06Z INFO done",
+		"quiet.log":      "2024-01-01T00:00:07Z INFO idle",
+	})
+	if _, err := ruleService.CreateRule(&models.RuleRequest{Name: "login", Pattern: "login failed", Severity: "high"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ruleService.ScanLog("log1"); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := s.GetSummary("log1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Cached {
+		t.Error("第一次获取时 Cached = true")
+	}
+	if summary.Files != 3 || summary.TextFiles != 3 || summary.TotalLines != 7 {
+		t.Errorf("Files/TextFiles/TotalLines = %d/%d/%d, 期望 3/3/7", summary.Files, summary.TextFiles, summary.TotalLines)
+	}
+	if summary.Errors != 2 || summary.Warnings != 2 {
+		t.Errorf("Errors/Warnings = %d/%d, 期望 2/2", summary.Errors, summary.Warnings)
+	}
+	if summary.Start != "2024-01-01T00:00:01Z" || summary.End != "2024-01-01T00:00:07Z" {
+		t.Errorf("时间范围 = %s ~ %s", summary.Start, summary.End)
+	}
+
+	var total int64
+	for i, f := range summary.LargestFiles {
+		total += f.Size
+		if i > 0 && f.Size > summary.LargestFiles[i-1].Size {
+			t.Errorf("LargestFiles 未按大小降序: %+v", summary.LargestFiles)
+		}
+	}
+	if len(summary.LargestFiles) != 3 || summary.LargestFiles[0].Path != "app.log" || total != summary.TotalSize {
+		t.Errorf("LargestFiles = %+v, TotalSize = %d", summary.LargestFiles, summary.TotalSize)
+	}
+
+	// 只列出有错误或警告的文件，错误多的在前
+	if len(summary.FileLevels) != 2 || summary.FileLevels[0].Path != "app.log" || summary.FileLevels[1].Path != "sub/worker.log" {
+		t.Errorf("FileLevels = %+v, 期望 app.log、sub/worker.log", summary.FileLevels)
+	}
+
+	if len(summary.TopErrors) != 1 || summary.TopErrors[0].Count != 2 {
+		t.Fatalf("TopErrors = %+v, 期望一个出现2次的模板", summary.TopErrors)
+	}
+	for _, ex := range summary.TopErrors[0].Examples {
+		if strings.Contains(ex.Content, "@example.com") {
+			t.Errorf("示例未脱敏: %q", ex.Content)
+		}
+	}
+	if len(summary.Findings) != 1 || summary.Severities["high"] != 1 {
+		t.Errorf("Findings = %+v, Severities = %v, 期望一条 high 匹配", summary.Findings, summary.Severities)
+	}
+
+	// 再次获取时从缓存读取，有原始内容权限时返回原文
+	raw, err := s.GetSummary("log1", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !raw.Cached {
+		t.Error("第二次获取时 Cached = false")
+	}
+	var found bool
+	for _, ex := range raw.TopErrors[0].Examples {
+		found = found || strings.Contains(ex.Content, "@example.com")
+	}
+	if !found {
+		t.Errorf("原始内容中缺少邮箱: %+v", raw.TopErrors[0].Examples)
+	}
+
+	if _, err := s.GetSummary("missing", false); err == nil {
+		t.Error("日志不存在时应返回错误")
+	}
+}
+
+func TestRenderSummary(t *testing.T) {
+	summary := &models.BundleSummary{
+		LogID:        "log1",
+		Files:        2,
+		TextFiles:    1,
+		TotalSize:    2048,
+		LargestFiles: []models.SummaryFile{{Path: "a|b.log", Size: 2048}},
+		Start:        "2024-01-01T00:00:01Z",
+		End:          "2024-01-01T00:00:09Z",
+		TotalLines:   10,
+		Errors:       3,
+		Warnings:     1,
+		FileLevels:   []models.SummaryFileLevels{{Path: "a|b.log", Lines: 10, Errors: 3, Warnings: 1}},
+		TopErrors:    []models.Pattern{{Template: "<script>alert(`x`)</script>", Level: "ERROR", Count: 3}},
+		Findings:     []models.Finding{{RuleName: "oom", Severity: "high", FilePath: "a|b.log", Line: 2, Count: 1}},
+	}
+
+	md := RenderSummaryMarkdown(summary)
+	for _, want := range []string{"# 日志包概览：log1", "2.0 KB", "2024-01-01T00:00:01Z ~ 2024-01-01T00:00:09Z",
+		"错误 3，警告 1", "| high | oom |", `a\|b.log`, "`<script>alert('x')</script>`"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown 中缺少 %q:\n%s", want, md)
+		}
+	}
+
+	html, err := RenderSummaryHTML(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(html, "<script>") {
+		t.Errorf("HTML 中的日志内容未转义:\n%s", html)
+	}
+	for _, want := range []string{"log1", "&lt;script&gt;", "a|b.log", "oom"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML 中缺少 %q", want)
+		}
+	}
+}
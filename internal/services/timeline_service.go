@@ -0,0 +1,358 @@
+package services
+
+import (
+	"container/heap"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/pkg/fileutil"
+	"logview-goversion/internal/pkg/logparser"
+	"time"
+)
+
+// 时间线限制
+const (
+	defaultTimelineLimit = 500
+	maxTimelineLimit     = 5000
+	maxTimelineFiles     = 50
+)
+
+// 时间线请求错误，处理器据此区分客户端错误和服务端错误
+var (
+	ErrTimelineNoFiles       = errors.New(models.ErrNoFilesSelected)
+	ErrTimelineTooManyFiles  = errors.New(models.ErrTooManyFiles)
+	ErrTimelineDuplicateFile = errors.New(models.ErrDuplicateFile)
+	ErrTimelineFileNotFound  = errors.New(models.ErrFileNotFound)
+	ErrTimelineInvalidCursor = errors.New(models.ErrInvalidCursor)
+)
+
+// TimelineService 多文件时间线服务
+type TimelineService struct {
+	fileService *FileService
+	redaction   *RedactionService
+}
+
+// NewTimelineService 创建多文件时间线服务
+func NewTimelineService(fileService *FileService, redaction *RedactionService) *TimelineService {
+	return &TimelineService{
+		fileService: fileService,
+		redaction:   redaction,
+	}
+}
+
+// timelineEvent 已读取但尚未输出的事件
+type timelineEvent struct {
+	startLine int
+	endLine   int
+	endOffset int64     // 最后一行结束处的字节偏移
+	time      time.Time // 首行自身或继承的时间
+	level     string
+	text      string
+	hasTime   bool
+}
+
+// timelineSource 参与合并的单个文件
+type timelineSource struct {
+	path     string
+	index    int
+	scanner  *fileutil.LineScanner
+	closer   io.Closer
+	grouper  *logparser.Grouper
+	lastTime time.Time
+	consumed timelinePosition // 已输出（或跳过）的位置，用于游标
+	head     *timelineEvent
+}
+
+// timelinePosition 游标中单个文件的位置：已输出的行数、这些行结束处的字节偏移和最后一个事件的时间
+// 下一页从该偏移继续读取，不需要重新扫描之前的行；时间用于让没有时间戳的事件继承正确的时间
+type timelinePosition struct {
+	Line   int       `json:"line"`
+	Offset int64     `json:"offset"`
+	Time   time.Time `json:"time"`
+}
+
+// advance 读取下一个事件作为当前待输出事件
+func (src *timelineSource) advance() error {
+	var ev *logparser.Event
+	var endOffset int64
+	for ev == nil {
+		// 分组器在读到下一个事件的首行时才返回上一个事件，上一个事件结束于读取该行之前
+		endOffset = src.scanner.Offset()
+		if !src.scanner.Next() {
+			if err := src.scanner.Err(); err != nil {
+				return err
+			}
+			if ev = src.grouper.Flush(); ev == nil {
+				src.head = nil
+				return nil
+			}
+			break
+		}
+		ev = src.grouper.Add(src.scanner.LineNo(), src.scanner.Line())
+	}
+
+	rec := ev.Record
+	if rec.HasTime() {
+		src.lastTime = rec.Time
+	}
+	src.head = &timelineEvent{
+		startLine: ev.StartLine(),
+		endLine:   ev.EndLine(),
+		endOffset: endOffset,
+		time:      src.lastTime,
+		level:     rec.Level,
+		text:      ev.Text(),
+		hasTime:   rec.HasTime(),
+	}
+	return nil
+}
+
+// consume 记录当前事件已输出（或被过滤跳过）
+func (src *timelineSource) consume() {
+	src.consumed = timelinePosition{Line: src.head.endLine, Offset: src.head.endOffset, Time: src.head.time}
+}
+
+// timelineHeap 按当前行时间排序的最小堆，时间相同时按文件顺序和行号
+type timelineHeap []*timelineSource
+
+func (h timelineHeap) Len() int { return len(h) }
+func (h timelineHeap) Less(i, j int) bool {
+	a, b := h[i].head, h[j].head
+	if !a.time.Equal(b.time) {
+		return a.time.Before(b.time)
+	}
+	if h[i].index != h[j].index {
+		return h[i].index < h[j].index
+	}
+	return a.startLine < b.startLine
+}
+func (h timelineHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *timelineHeap) Push(x interface{}) { *h = append(*h, x.(*timelineSource)) }
+func (h *timelineHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// GetTimeline 按时间戳k路归并多个文件的事件，多行事件（堆栈、panic等）作为整体输出和过滤
+func (s *TimelineService) GetTimeline(logID string, opts models.TimelineOptions) (*models.TimelineResult, error) {
+	if len(opts.Files) == 0 {
+		return nil, ErrTimelineNoFiles
+	}
+	if len(opts.Files) > maxTimelineFiles {
+		return nil, fmt.Errorf("%w（最多 %d 个）", ErrTimelineTooManyFiles, maxTimelineFiles)
+	}
+	// 游标按路径记录位置，重复的路径会共用同一个位置
+	seen := make(map[string]bool, len(opts.Files))
+	for _, path := range opts.Files {
+		if seen[path] {
+			return nil, fmt.Errorf("%w: %s", ErrTimelineDuplicateFile, path)
+		}
+		seen[path] = true
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultTimelineLimit
+	}
+	if limit > maxTimelineLimit {
+		limit = maxTimelineLimit
+	}
+
+	positions, err := decodeTimelineCursor(opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := make(map[string]bool, len(opts.Levels))
+	for _, level := range opts.Levels {
+		levels[level] = true
+	}
+
+	sources := make([]*timelineSource, 0, len(opts.Files))
+	defer func() {
+		for _, src := range sources {
+			src.closer.Close()
+		}
+	}()
+
+	h := &timelineHeap{}
+	for i, path := range opts.Files {
+		src, err := s.openSource(logID, path, i, positions[path])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		sources = append(sources, src)
+		if src.head != nil {
+			*h = append(*h, src)
+		}
+	}
+	heap.Init(h)
+
+	result := &models.TimelineResult{
+		Files:   opts.Files,
+		Entries: []models.TimelineEntry{},
+	}
+
+	for h.Len() > 0 {
+		src := (*h)[0]
+		ev := src.head
+
+		// 超过结束时间后该文件不会再有符合条件的事件
+		if !opts.Until.IsZero() && ev.time.After(opts.Until) {
+			heap.Pop(h)
+			continue
+		}
+		if len(result.Entries) >= limit {
+			result.HasMore = true
+			break
+		}
+
+		if matchTimelineEvent(ev, levels, opts) {
+			result.Entries = append(result.Entries, s.toTimelineEntry(src.path, ev, opts.Raw))
+		}
+		src.consume()
+
+		// 没有时间戳的事件紧跟前一个事件输出，避免被其他文件的事件插入
+		// 整个文件都没有时间戳时全部事件都走这里，同样受 limit 限制
+		for {
+			if err := src.advance(); err != nil {
+				return nil, err
+			}
+			if src.head == nil || src.head.hasTime {
+				break
+			}
+			if len(result.Entries) >= limit {
+				result.HasMore = true
+				break
+			}
+			if matchTimelineEvent(src.head, levels, opts) {
+				result.Entries = append(result.Entries, s.toTimelineEntry(src.path, src.head, opts.Raw))
+			}
+			src.consume()
+		}
+		if result.HasMore {
+			break
+		}
+
+		if src.head == nil {
+			heap.Pop(h)
+		} else {
+			heap.Fix(h, 0)
+		}
+	}
+
+	if result.HasMore {
+		next := make(map[string]timelinePosition, len(sources))
+		for _, src := range sources {
+			next[src.path] = src.consumed
+		}
+		result.NextCursor = encodeTimelineCursor(next)
+	}
+	return result, nil
+}
+
+// openSource 打开文件并从游标位置继续读取
+func (s *TimelineService) openSource(logID, path string, index int, pos timelinePosition) (*timelineSource, error) {
+	fullPath, err := s.fileService.ResolvePath(logID, path)
+	if err != nil || !s.fileService.fileUtil.IsFile(fullPath) {
+		return nil, ErrTimelineFileNotFound
+	}
+
+	sample, err := s.fileService.HeadLines(logID, path, detectSampleLines)
+	if err != nil {
+		return nil, err
+	}
+	lineParser, err := s.fileService.NewLineParser("", sample)
+	if err != nil {
+		return nil, err
+	}
+
+	// 游标位置总在事件边界上，从这里开始分组与从头读取的结果相同
+	// 游标位置超出文件内容说明文件在翻页期间被替换
+	scanner, closer, err := s.fileService.OpenLinesAt(logID, path, pos.Offset, pos.Line)
+	if errors.Is(err, fileutil.ErrOffsetOutOfRange) {
+		return nil, ErrTimelineInvalidCursor
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	src := &timelineSource{
+		path:     path,
+		index:    index,
+		scanner:  scanner,
+		closer:   closer,
+		grouper:  lineParser.NewGrouper(),
+		lastTime: pos.Time,
+		consumed: pos,
+	}
+	if err := src.advance(); err != nil {
+		closer.Close()
+		return nil, err
+	}
+	return src, nil
+}
+
+// matchTimelineEvent 判断事件是否在时间窗口内且首行满足级别过滤
+func matchTimelineEvent(ev *timelineEvent, levels map[string]bool, opts models.TimelineOptions) bool {
+	if len(levels) > 0 && !levels[ev.level] {
+		return false
+	}
+	if !opts.Since.IsZero() && (ev.time.IsZero() || ev.time.Before(opts.Since)) {
+		return false
+	}
+	if !opts.Until.IsZero() && (ev.time.IsZero() || ev.time.After(opts.Until)) {
+		return false
+	}
+	return true
+}
+
+// toTimelineEntry 转换为响应模型，raw为false时对内容脱敏
+func (s *TimelineService) toTimelineEntry(path string, ev *timelineEvent, raw bool) models.TimelineEntry {
+	entry := models.TimelineEntry{
+		File:    path,
+		Line:    ev.startLine,
+		Level:   ev.level,
+		Content: s.redaction.Redact(ev.text, raw),
+	}
+	if ev.endLine > ev.startLine {
+		entry.EndLine = ev.endLine
+	}
+	if !ev.time.IsZero() {
+		entry.Timestamp = ev.time.Format(time.RFC3339Nano)
+	}
+	return entry
+}
+
+// encodeTimelineCursor 将各文件已读取的位置编码为游标
+func encodeTimelineCursor(positions map[string]timelinePosition) string {
+	data, _ := json.Marshal(positions)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeTimelineCursor 解析游标
+func decodeTimelineCursor(cursor string) (map[string]timelinePosition, error) {
+	positions := map[string]timelinePosition{}
+	if cursor == "" {
+		return positions, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrTimelineInvalidCursor
+	}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return nil, ErrTimelineInvalidCursor
+	}
+	for _, pos := range positions {
+		if pos.Line < 0 || pos.Offset < 0 {
+			return nil, ErrTimelineInvalidCursor
+		}
+	}
+	return positions, nil
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"logview-goversion/internal/config"
+	"logview-goversion/internal/models"
+	"logview-goversion/internal/pkg/fileutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTimelineTestService 在临时目录中写入日志文件并创建时间线服务
+func newTimelineTestService(t *testing.T, compression string, files map[string]string) *TimelineService {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	cfg.Storage.ZipDir = filepath.Join(dir, "zip")
+	cfg.Storage.ExtractDir = filepath.Join(dir, "extracted")
+	cfg.Storage.Compression = compression
+
+	fileService := NewFileService(cfg, nil, nil, nil)
+	storage := fileutil.NewStorage(compression)
+	logDir := filepath.Join(cfg.Storage.ExtractDir, "log1")
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		w, err := storage.Create(filepath.Join(logDir, name), time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewTimelineService(fileService, nil)
+}
+
+// timelineKeys 条目的 文件:行号 列表
+func timelineKeys(entries []models.TimelineEntry) []string {
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		keys[i] = fmt.Sprintf("%s:%d", e.File, e.Line)
+	}
+	return keys
+}
+
+// collectTimeline 按游标翻页读取全部条目，返回每页的条目数
+func collectTimeline(t *testing.T, s *TimelineService, opts models.TimelineOptions) ([]string, []int) {
+	t.Helper()
+	var keys []string
+	var pages []int
+	for i := 0; i < 100; i++ {
+		result, err := s.GetTimeline("log1", opts)
+		if err != nil {
+			t.Fatalf("GetTimeline() 错误: %v", err)
+		}
+		keys = append(keys, timelineKeys(result.Entries)...)
+		pages = append(pages, len(result.Entries))
+		if !result.HasMore {
+			return keys, pages
+		}
+		if result.NextCursor == "" {
+			t.Fatal("HasMore 为 true 但没有 next_cursor")
+		}
+		opts.Cursor = result.NextCursor
+	}
+	t.Fatal("翻页没有结束")
+	return nil, nil
+}
+
+func TestGetTimelineMerge(t *testing.T) {
+	s := newTimelineTestService(t, "", map[string]string{
+		"a.log": "2024-01-01T00:00:01Z INFO a1\n2024-01-01T00:00:03Z ERROR a2\n\tat stack\n2024-01-01T00:00:05Z INFO a3\n",
+		"b.log": "2024-01-01T00:00:02Z INFO b1\n2024-01-01T00:00:03Z WARN b2\n2024-01-01T00:00:04Z INFO b3\n",
+	})
+
+	tests := []struct {
+		name string
+		opts models.TimelineOptions
+		want []string
+	}{
+		{
+			name: "按时间合并，时间相同时按文件顺序",
+			opts: models.TimelineOptions{Files: []string{"a.log", "b.log"}},
+			want: []string{"a.log:1", "b.log:1", "a.log:2", "b.log:2", "b.log:3", "a.log:4"},
+		},
+		{
+			name: "按级别过滤多行事件",
+			opts: models.TimelineOptions{Files: []string{"a.log", "b.log"}, Levels: []string{"ERROR", "WARN"}},
+			want: []string{"a.log:2", "b.log:2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.GetTimeline("log1", tt.opts)
+			if err != nil {
+				t.Fatalf("GetTimeline() 错误: %v", err)
+			}
+			if got := timelineKeys(result.Entries); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("条目 = %v, 期望 %v", got, tt.want)
+			}
+			if result.HasMore {
+				t.Error("HasMore = true, 期望 false")
+			}
+		})
+	}
+
+	result, err := s.GetTimeline("log1", models.TimelineOptions{Files: []string{"a.log", "b.log"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.Entries[2]; got.EndLine != 3 || got.Content != "2024-01-01T00:00:03Z ERROR a2\n\tat stack" {
+		t.Errorf("多行事件 = %+v", got)
+	}
+}
+
+func TestGetTimelinePagination(t *testing.T) {
+	var plain strings.Builder
+	for i := 1; i <= 10; i++ {
+		fmt.Fprintf(&plain, "plain line %d\n", i)
+	}
+	files := map[string]string{
+		"plain.txt": plain.String(),
+		"a.log":     "2024-01-01T00:00:01Z INFO a1\nnot timestamped\n2024-01-01T00:00:03Z ERROR a2\n\tat stack\n\tat more\n2024-01-01T00:00:05Z INFO a3\n",
+		"b.log":     "2024-01-01T00:00:02Z INFO b1\n2024-01-01T00:00:03Z WARN b2\n2024-01-01T00:00:04Z INFO b3",
+	}
+
+	tests := []struct {
+		name  string
+		files []string
+		limit int
+	}{
+		{name: "没有时间戳的文件受limit限制", files: []string{"plain.txt"}, limit: 3},
+		{name: "多文件游标续读", files: []string{"a.log", "b.log"}, limit: 2},
+		{name: "混合文件逐条翻页", files: []string{"a.log", "plain.txt", "b.log"}, limit: 1},
+	}
+
+	for _, compression := range []string{fileutil.StoragePlain, fileutil.StorageZstd} {
+		s := newTimelineTestService(t, compression, files)
+		for _, tt := range tests {
+			t.Run(tt.name+"/"+compression, func(t *testing.T) {
+				all, err := s.GetTimeline("log1", models.TimelineOptions{Files: tt.files, Limit: maxTimelineLimit})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				keys, pages := collectTimeline(t, s, models.TimelineOptions{Files: tt.files, Limit: tt.limit})
+				for _, n := range pages {
+					if n > tt.limit {
+						t.Errorf("单页条目数 %d 超过 limit %d", n, tt.limit)
+					}
+				}
+				if want := timelineKeys(all.Entries); !reflect.DeepEqual(keys, want) {
+					t.Errorf("翻页结果 = %v, 期望 %v", keys, want)
+				}
+			})
+		}
+	}
+}
+
+func TestGetTimelineErrors(t *testing.T) {
+	tooMany := make([]string, maxTimelineFiles+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("f%d.log", i)
+	}
+	stale := encodeTimelineCursor(map[string]timelinePosition{"a.log": {Line: 10, Offset: 1000}})
+
+	tests := []struct {
+		name string
+		opts models.TimelineOptions
+		want error
+	}{
+		{name: "没有选择文件", opts: models.TimelineOptions{}, want: ErrTimelineNoFiles},
+		{name: "文件过多", opts: models.TimelineOptions{Files: tooMany}, want: ErrTimelineTooManyFiles},
+		{name: "重复的文件", opts: models.TimelineOptions{Files: []string{"a.log", "a.log"}}, want: ErrTimelineDuplicateFile},
+		{name: "文件不存在", opts: models.TimelineOptions{Files: []string{"a.log", "missing.log"}}, want: ErrTimelineFileNotFound},
+		{name: "路径越界", opts: models.TimelineOptions{Files: []string{"../log2/a.log"}}, want: ErrTimelineFileNotFound},
+		{name: "无效游标", opts: models.TimelineOptions{Files: []string{"a.log"}, Cursor: "!!!"}, want: ErrTimelineInvalidCursor},
+		{name: "游标位置超出文件内容", opts: models.TimelineOptions{Files: []string{"a.log"}, Cursor: stale}, want: ErrTimelineInvalidCursor},
+	}
+
+	for _, compression := range []string{fileutil.StoragePlain, fileutil.StorageZstd} {
+		s := newTimelineTestService(t, compression, map[string]string{"a.log": "2024-01-01T00:00:01Z INFO a1\n"})
+		for _, tt := range tests {
+			t.Run(tt.name+"/"+compression, func(t *testing.T) {
+				_, err := s.GetTimeline("log1", tt.opts)
+				if !errors.Is(err, tt.want) {
+					t.Errorf("GetTimeline() 错误 = %v, 期望 %v", err, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestDecodeTimelineCursor(t *testing.T) {
+	tests := []struct {
+		name    string
+		cursor  string
+		wantErr bool
+	}{
+		{name: "空游标", cursor: ""},
+		{name: "有效游标", cursor: encodeTimelineCursor(map[string]timelinePosition{"a.log": {Line: 3, Offset: 42}})},
+		{name: "不是base64", cursor: "!!!", wantErr: true},
+		{name: "不是JSON", cursor: "bm90LWpzb24", wantErr: true},
+		{name: "负数偏移", cursor: encodeTimelineCursor(map[string]timelinePosition{"a.log": {Line: 1, Offset: -1}}), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeTimelineCursor(tt.cursor)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeTimelineCursor() 错误 = %v, 期望错误 %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -17,10 +17,12 @@ mkdir -p $BUILD_DIR
 
 # 构建参数
 LDFLAGS="-X main.Version=$VERSION -X main.BuildTime=$BUILD_TIME -s -w"
+# sqlite_fts5: 启用SQLite FTS5全文索引
+BUILD_TAGS="sqlite_fts5"
 
 # 构建应用
 echo "构建 $APP_NAME..."
-go build -ldflags "$LDFLAGS" -o $BUILD_DIR/$APP_NAME ./cmd/server
+go build -tags "$BUILD_TAGS" -ldflags "$LDFLAGS" -o $BUILD_DIR/$APP_NAME ./cmd/server
 
 echo "构建完成: $BUILD_DIR/$APP_NAME"
 echo "版本: $VERSION"
@@ -11,7 +11,7 @@ PORT=${PORT:-5001}
 
 # 构建应用
 echo "构建应用..."
-go build -o bin/logview-server ./cmd/server
+go build -tags sqlite_fts5 -o bin/logview-server ./cmd/server
 
 # 运行应用
 echo "运行应用 (端口: $PORT)..."
@@ -7,6 +7,14 @@ let currentFilePagination = {
     pageSize: 1000,
     isPaginated: false
 };
+// 当前级别过滤（分页模式下由服务端过滤）
+let currentLevelFilter = 'all';
+const LEVEL_FILTER_PARAMS = {
+    error: 'ERROR,FATAL',
+    warn: 'WARN',
+    info: 'INFO',
+    debug: 'DEBUG,TRACE'
+};
 
 // 请求缓存（减少重复 API 调用）
 const requestCache = new Map();
@@ -391,7 +399,8 @@ function downloadLogById(logId) {
         if (data.error) {
             alert('下载失败: ' + data.error);
         } else {
-            // 刷新日志列表
+            // 刷新日志列表，新日志在后台处理
+            requestCache.delete('/api/logs' + JSON.stringify({}));
             loadLogList();
         }
     })
@@ -438,6 +447,24 @@ function refreshAll() {
     }
 }
 
+// 有日志还在后台处理时定时刷新列表
+let logListRefreshTimer = null;
+const LOG_LIST_REFRESH_INTERVAL = 2000;
+
+// 导入处理状态的显示文字，处理完成时为空
+function importStatusText(log) {
+    switch (log.status) {
+        case 'pending':
+            return '等待处理';
+        case 'processing':
+            return log.progress ? `处理中 ${log.progress.done}/${log.progress.total} ${log.progress.step}` : '处理中';
+        case 'failed':
+            return '处理失败';
+        default:
+            return '';
+    }
+}
+
 // 加载日志列表（带缓存）
 function loadLogList() {
     const loadingEl = document.getElementById('logListLoading');
@@ -488,10 +515,15 @@ function loadLogList() {
                 const notesHtml = log.notes ?
                     `<div class="log-notes" title="${log.notes}">${log.notes}</div>` : '';
                 
+                // 处理导入状态显示
+                const statusText = importStatusText(log);
+                const statusHtml = statusText ?
+                    ` · <span class="log-status log-status-${log.status}" title="${log.status_error || ''}">${statusText}</span>` : '';
+                
                 li.innerHTML = `
                     <div class="log-main-info">
                         <div class="log-id">${log.log_id}</div>
-                        <div class="log-time">${formatTimeAgo(log.download_time)}</div>
+                        <div class="log-time">${formatTimeAgo(log.download_time)}${log.size ? ` · ${formatFileSize(log.size)}` : ''}${statusHtml}</div>
                         ${tagsHtml}
                         ${notesHtml}
                     </div>
@@ -528,6 +560,15 @@ function loadLogList() {
                 
                 itemsEl.appendChild(li);
             });
+            
+            // 还有日志在后台处理时，稍后重新获取列表
+            clearTimeout(logListRefreshTimer);
+            if (data.some(log => log.status === 'pending' || log.status === 'processing')) {
+                logListRefreshTimer = setTimeout(() => {
+                    requestCache.delete('/api/logs' + JSON.stringify({}));
+                    loadLogList();
+                }, LOG_LIST_REFRESH_INTERVAL);
+            }
         })
         .catch(error => {
             loadingEl.style.display = 'none';
@@ -586,7 +627,14 @@ function deleteLog(logId) {
     });
 }
 
-// 加载文件树（带缓存）
+// 目录列表每页条数
+const DIR_PAGE_SIZE = 500;
+// 文件搜索结果最多显示的条数
+const FILE_SEARCH_LIMIT = 200;
+// 文件搜索请求序号，用于丢弃过期的响应
+let fileSearchSeq = 0;
+
+// 加载文件树（只加载根目录，子目录展开时按需加载）
 function loadFileTree(logId) {
     const loadingEl = document.getElementById('fileTreeLoading');
     const emptyEl = document.getElementById('fileTreeEmpty');
@@ -597,49 +645,32 @@ function loadFileTree(logId) {
     emptyEl.style.display = 'none';
     containerEl.innerHTML = '';
     
-    // 使用缓存请求
-    const cacheKey = `/api/logs/${logId}/files`;
-    const cached = requestCache.get(cacheKey);
-    const cacheDuration = 5 * 60 * 1000; // 5分钟
-    
-    if (cached && Date.now() - cached.timestamp < cacheDuration) {
-        loadingEl.style.display = 'none';
-        console.log('使用缓存的文件树数据');
-        const treeHtml = renderFileTree(cached.data, logId);
-        containerEl.innerHTML = treeHtml;
-        bindTreeEvents();
-        bindSearchEvents();
-        saveInitialFolderStates();
-        setTimeout(() => {
-            openDefaultFile(logId);
-        }, 100);
-        return;
-    }
-    
-    fetch(`/api/logs/${logId}/files`)
-        .then(response => response.json())
+    fetchDir(logId, '')
         .then(data => {
             loadingEl.style.display = 'none';
             
-            console.log('File tree data:', data); // 添加调试日志
-            
             if (data.error) {
                 emptyEl.style.display = 'block';
                 return;
             }
             
-            // 渲染文件树
-            const treeHtml = renderFileTree(data, logId);
-            containerEl.innerHTML = treeHtml;
+            if (data.total === 0) {
+                containerEl.innerHTML = '<div class="empty-state"><i class="fas fa-folder-open"></i><p>该日志没有文件</p></div>';
+                return;
+            }
+            
+            // 渲染根目录
+            containerEl.innerHTML = `<div id="fileTreeRoot">${renderDirListing(data, logId, 0, 'node')}</div>`;
             
             // 绑定文件树事件
             bindTreeEvents();
             
-            // 绑定搜索功能
+            // 绑定搜索功能，切换日志时保留搜索词
             bindSearchEvents();
-            
-            // 保存初始文件夹状态
-            saveInitialFolderStates();
+            const searchInput = document.getElementById('fileSearch');
+            if (searchInput && searchInput.value.trim() !== '') {
+                searchFiles(searchInput.value);
+            }
             
             // 默认打开nettype.json文件
             setTimeout(() => {
@@ -652,21 +683,35 @@ function loadFileTree(logId) {
         });
 }
 
-// 渲染文件树
-function renderFileTree(nodes, logId, depth = 0) {
-    console.log('Rendering file tree with nodes:', nodes, 'logId:', logId); // 添加调试日志
-    
-    // 检查是否是单个节点对象而不是数组
-    if (nodes && typeof nodes === 'object' && !Array.isArray(nodes)) {
-        // 如果是单个节点（根目录），使用其子节点作为渲染对象
-        if (nodes.children && nodes.children.length > 0) {
-            nodes = nodes.children;
-        } else {
-            // 如果根目录没有子节点，显示空状态
-            return '<div class="empty-state"><i class="fas fa-folder-open"></i><p>该日志没有文件</p></div>';
-        }
+// 请求一页目录列表，params 可包含 q、glob、cursor、limit
+function fetchDir(logId, path, params = {}) {
+    const query = new URLSearchParams({ path: path, limit: DIR_PAGE_SIZE, ...params });
+    return fetch(`/api/logs/${logId}/dir?${query}`).then(response => response.json());
+}
+
+// 渲染一页目录列表，还有下一页时在末尾添加“加载更多”，shown 为之前各页已显示的条数
+function renderDirListing(data, logId, depth, scope, shown = 0) {
+    let html = renderFileTree(data.entries, logId, depth, scope);
+    if (data.has_more) {
+        html += renderLoadMore(data, depth, shown + data.entries.length);
     }
-    
+    return html;
+}
+
+// 渲染“加载更多”节点
+function renderLoadMore(data, depth, shown) {
+    const indentStyle = `style="padding-left: ${depth * 20}px;"`;
+    return `
+        <li class="tree-node tree-load-more" data-type="more" data-dir="${data.path}" data-cursor="${data.next_cursor}" data-shown="${shown}" data-depth="${depth}" ${indentStyle}>
+            <span class="toggle" style="visibility: hidden;"></span>
+            <span class="icon"><i class="fas fa-ellipsis-h"></i></span>
+            <span class="node-name">加载更多（剩余 ${data.total - shown} 项）</span>
+        </li>
+    `;
+}
+
+// 渲染文件树节点，目录的子节点在展开时加载
+function renderFileTree(nodes, logId, depth = 0, scope = 'node') {
     if (!nodes || nodes.length === 0) {
         return '<div class="empty-state"><i class="fas fa-folder-open"></i><p>该日志没有文件</p></div>';
     }
@@ -674,26 +719,23 @@ function renderFileTree(nodes, logId, depth = 0) {
     let html = '<ul class="tree-children expanded">';
     
     nodes.forEach(node => {
-        const nodeId = `node-${logId}-${node.path.replace(/[^a-zA-Z0-9]/g, '-')}`;
-        const iconClass = getFileIcon(node.name, node.type);
+        const nodeId = `${scope}-${logId}-${node.path.replace(/[^a-zA-Z0-9]/g, '-')}`;
+        const iconClass = node.binary ? 'fas fa-file' : getFileIcon(node.name, node.type);
         const indentStyle = `style="padding-left: ${depth * 20}px;"`;
         
         const displayName = truncateFileName(node.name);
         html += `
-            <li class="tree-node ${node.type} ${node.type === 'directory' ? 'collapsed' : ''}" data-path="${node.path}" data-type="${node.type}" id="${nodeId}" data-name="${node.name.toLowerCase()}" data-depth="${depth}" title="${node.name}" ${indentStyle}>
-                ${node.type === 'directory' ? '<span class="toggle"><i class="fas fa-chevron-down"></i></span>' : '<span class="toggle" style="visibility: hidden;"></span>'}
+            <li class="tree-node ${node.type} ${node.type === 'directory' ? 'collapsed' : ''} ${node.binary ? 'binary' : ''}" data-path="${node.path}" data-type="${node.type}" id="${nodeId}" data-name="${node.name.toLowerCase()}" data-depth="${depth}" title="${node.name}" ${indentStyle}>
+                ${node.type === 'directory' && node.child_count ? '<span class="toggle"><i class="fas fa-chevron-down"></i></span>' : '<span class="toggle" style="visibility: hidden;"></span>'}
                 <span class="icon"><i class="${iconClass}"></i></span>
                 <span class="node-name">${displayName}</span>
-                ${node.size !== undefined ? `<span class="node-size">${formatFileSize(node.size)}</span>` : ''}
+                ${node.type === 'file' ? `<span class="node-size">${formatFileSize(node.size || 0)}</span>` : ''}
             </li>
         `;
         
-        // 如果是目录且有子节点，递归渲染
-        if (node.type === 'directory' && node.children && node.children.length > 0) {
-            // 默认折叠子目录
-            html += `<ul class="tree-children collapsed" data-parent="${nodeId}">`;
-            html += renderFileTree(node.children, logId, depth + 1);
-            html += '</ul>';
+        // 非空目录先放置一个空的子节点容器，默认折叠
+        if (node.type === 'directory' && node.child_count) {
+            html += `<ul class="tree-children collapsed" data-parent="${nodeId}" data-dir="${node.path}" data-scope="${scope}" data-depth="${depth + 1}" style="display: none;"></ul>`;
         }
     });
     
@@ -764,31 +806,36 @@ function getFileIcon(fileName, fileType) {
     return 'fas fa-file-alt';
 }
 
-// 绑定文件树事件
+// 绑定文件树事件（事件委托，按需加载的节点无需重新绑定）
 function bindTreeEvents() {
-    // 绑定文件节点点击事件
-    document.querySelectorAll('.tree-node').forEach(node => {
-        node.addEventListener('click', function(e) {
-            const path = this.getAttribute('data-path');
-            const type = this.getAttribute('data-type');
-            
-            e.stopPropagation();
-            
-            if (type === 'directory') {
-                // 切换文件夹展开/折叠状态
-                toggleFolder(this);
-            } else {
-                // 选择文件
-                selectFile(this);
-            }
-        });
+    const containerEl = document.getElementById('treeContainer');
+    
+    containerEl.onclick = function(e) {
+        const node = e.target.closest('.tree-node');
+        if (!node) return;
         
-        // 绑定右键菜单事件
-        node.addEventListener('contextmenu', function(e) {
-            e.preventDefault();
-            showContextMenu(e, this);
-        });
-    });
+        e.stopPropagation();
+        
+        const type = node.getAttribute('data-type');
+        if (type === 'more') {
+            // 加载目录的下一页
+            loadMoreEntries(node);
+        } else if (type === 'directory') {
+            // 切换文件夹展开/折叠状态
+            toggleFolder(node);
+        } else {
+            // 选择文件
+            selectFile(node);
+        }
+    };
+    
+    // 绑定右键菜单事件
+    containerEl.oncontextmenu = function(e) {
+        const node = e.target.closest('.tree-node');
+        if (!node || node.getAttribute('data-type') === 'more') return;
+        e.preventDefault();
+        showContextMenu(e, node);
+    };
     
     // 绑定展开/折叠所有按钮
     bindExpandCollapseButtons();
@@ -797,6 +844,58 @@ function bindTreeEvents() {
     document.addEventListener('click', hideContextMenu);
 }
 
+// 加载目录的子节点（首次展开时）
+function loadDirChildren(container) {
+    if (!currentLogId || container.getAttribute('data-loaded') === 'true') {
+        return Promise.resolve();
+    }
+    container.setAttribute('data-loaded', 'true');
+    container.innerHTML = '<li class="loading"><i class="fas fa-spinner fa-spin"></i> 加载中...</li>';
+    
+    const depth = parseInt(container.getAttribute('data-depth'), 10) || 0;
+    const scope = container.getAttribute('data-scope') || 'node';
+    return fetchDir(currentLogId, container.getAttribute('data-dir'))
+        .then(data => {
+            if (data.error) {
+                container.innerHTML = '';
+                container.removeAttribute('data-loaded');
+                showToast(data.error);
+                return;
+            }
+            container.innerHTML = renderDirListing(data, currentLogId, depth, scope);
+        })
+        .catch(error => {
+            container.innerHTML = '';
+            container.removeAttribute('data-loaded');
+            console.error('Error loading directory:', error);
+        });
+}
+
+// 加载目录的下一页，替换“加载更多”节点
+function loadMoreEntries(moreNode) {
+    if (!currentLogId || moreNode.classList.contains('loading')) return;
+    moreNode.classList.add('loading');
+    
+    const depth = parseInt(moreNode.getAttribute('data-depth'), 10) || 0;
+    const shown = parseInt(moreNode.getAttribute('data-shown'), 10) || 0;
+    const container = moreNode.closest('[data-scope]');
+    const scope = container ? container.getAttribute('data-scope') : 'node';
+    fetchDir(currentLogId, moreNode.getAttribute('data-dir'), { cursor: moreNode.getAttribute('data-cursor') })
+        .then(data => {
+            if (data.error) {
+                moreNode.classList.remove('loading');
+                showToast(data.error);
+                return;
+            }
+            moreNode.insertAdjacentHTML('afterend', renderDirListing(data, currentLogId, depth, scope, shown));
+            moreNode.remove();
+        })
+        .catch(error => {
+            moreNode.classList.remove('loading');
+            console.error('Error loading directory:', error);
+        });
+}
+
 // 绑定展开/折叠所有按钮
 function bindExpandCollapseButtons() {
     const expandAllBtn = document.getElementById('expandAllBtn');
@@ -811,13 +910,13 @@ function bindExpandCollapseButtons() {
     }
 }
 
-// 展开所有文件夹
+// 展开所有已加载的文件夹（未加载的目录不逐个请求）
 function expandAllFolders() {
     document.querySelectorAll('.tree-node.directory').forEach(folder => {
         const nodeId = folder.getAttribute('id');
         const childrenContainer = document.querySelector(`[data-parent="${nodeId}"]`);
         
-        if (childrenContainer) {
+        if (childrenContainer && childrenContainer.getAttribute('data-loaded') === 'true') {
             folder.classList.remove('collapsed');
             folder.classList.add('expanded');
             childrenContainer.classList.remove('collapsed');
@@ -1016,142 +1115,70 @@ function showToast(message) {
     }, 2000);
 }
 
-// 保存初始文件夹状态
-function saveInitialFolderStates() {
-    window.initialFolderStates = {};
-    document.querySelectorAll('.tree-node.directory').forEach(folder => {
-        const nodeId = folder.getAttribute('id');
-        const isExpanded = folder.classList.contains('expanded');
-        window.initialFolderStates[nodeId] = isExpanded;
-    });
-}
-
-// 恢复初始文件夹状态
-function restoreInitialFolderStates() {
-    if (!window.initialFolderStates) return;
-    
-    Object.keys(window.initialFolderStates).forEach(nodeId => {
-        const folder = document.getElementById(nodeId);
-        const childrenContainer = document.querySelector(`[data-parent="${nodeId}"]`);
-        
-        if (folder && childrenContainer) {
-            const wasExpanded = window.initialFolderStates[nodeId];
-            if (wasExpanded) {
-                folder.classList.remove('collapsed');
-                folder.classList.add('expanded');
-                childrenContainer.classList.remove('collapsed');
-                childrenContainer.classList.add('expanded');
-                childrenContainer.style.display = '';
-            } else {
-                folder.classList.remove('expanded');
-                folder.classList.add('collapsed');
-                childrenContainer.classList.remove('expanded');
-                childrenContainer.classList.add('collapsed');
-                childrenContainer.style.display = 'none';
-            }
-        }
-    });
-}
-
 // 绑定搜索事件
 function bindSearchEvents() {
     const searchInput = document.getElementById('fileSearch');
     if (searchInput) {
-        searchInput.addEventListener('input', function() {
-            filterFiles(this.value);
-        });
+        searchInput.oninput = debounce(function() {
+            searchFiles(searchInput.value);
+        }, 300);
     }
 }
 
-// 过滤文件
-function filterFiles(searchTerm) {
-    const nodes = document.querySelectorAll('.tree-node');
-    const term = searchTerm.toLowerCase().trim();
+// 在服务端按名称搜索文件，结果平铺显示在文件树上方，清空搜索词时恢复文件树
+function searchFiles(searchTerm) {
+    const term = searchTerm.trim();
+    const treeRoot = document.getElementById('fileTreeRoot');
+    let resultsEl = document.getElementById('fileSearchResults');
+    const seq = ++fileSearchSeq;
     
-    if (term === '') {
-        // 清空搜索，恢复所有节点和初始状态
-        nodes.forEach(node => {
-            node.style.display = '';
-            node.classList.remove('highlighted');
-            // 移除路径显示
-            const pathDisplay = node.querySelector('.node-path');
-            if (pathDisplay) {
-                pathDisplay.remove();
-            }
-        });
-        
-        // 恢复所有容器的显示
-        document.querySelectorAll('.tree-children').forEach(container => {
-            container.style.display = '';
-        });
-        
-        // 恢复初始文件夹状态
-        restoreInitialFolderStates();
-        
-        // 隐藏搜索统计
+    if (term === '' || !currentLogId) {
+        if (resultsEl) {
+            resultsEl.remove();
+        }
+        if (treeRoot) {
+            treeRoot.style.display = '';
+        }
         hideSearchStats();
-    } else {
-        // 有搜索词时的过滤逻辑
-        let matchCount = 0;
-        let fileCount = 0;
-        let folderCount = 0;
-        
-        nodes.forEach(node => {
-            const fileName = node.getAttribute('data-name');
-            const type = node.getAttribute('data-type');
-            const filePath = node.getAttribute('data-path');
+        return;
+    }
+    
+    fetchDir(currentLogId, '', { q: term, limit: FILE_SEARCH_LIMIT })
+        .then(data => {
+            // 已有更新的搜索
+            if (seq !== fileSearchSeq) return;
             
-            if (fileName.includes(term)) {
-                // 匹配的文件
-                node.style.display = '';
-                node.classList.add('highlighted');
-                matchCount++;
-                
-                if (type === 'directory') {
-                    folderCount++;
-                } else {
-                    fileCount++;
-                    // 为文件添加路径显示
-                    addPathDisplay(node, filePath);
-                }
-                
-                // 展开并显示所有父级目录
-                let parent = node.closest('.tree-children');
-                while (parent) {
-                    parent.style.display = '';
-                    const parentFolder = document.querySelector(`[data-parent="${parent.getAttribute('data-parent')}"]`);
-                    if (parentFolder) {
-                        parentFolder.classList.remove('collapsed');
-                        parentFolder.classList.add('expanded');
-                        parentFolder.style.display = '';
-                    }
-                    parent = parent.parentElement.closest('.tree-children');
-                }
-            } else {
-                // 不匹配的文件
-                node.style.display = 'none';
-                node.classList.remove('highlighted');
-                // 移除路径显示
-                const pathDisplay = node.querySelector('.node-path');
-                if (pathDisplay) {
-                    pathDisplay.remove();
-                }
+            if (data.error) {
+                showToast(data.error);
+                return;
             }
-        });
-        
-        // 隐藏空的容器
-        document.querySelectorAll('.tree-children').forEach(container => {
-            const visibleChildren = container.querySelectorAll('.tree-node:not([style*="display: none"])');
-            if (visibleChildren.length === 0) {
-                container.style.display = 'none';
+            
+            if (!resultsEl) {
+                resultsEl = document.createElement('div');
+                resultsEl.id = 'fileSearchResults';
+                document.getElementById('treeContainer').appendChild(resultsEl);
+            }
+            if (treeRoot) {
+                treeRoot.style.display = 'none';
+            }
+            
+            if (data.entries.length === 0) {
+                resultsEl.innerHTML = '<div class="empty-state"><i class="fas fa-search"></i><p>没有匹配的文件</p></div>';
             } else {
-                container.style.display = '';
+                resultsEl.innerHTML = renderFileTree(data.entries, currentLogId, 0, 'search');
+                resultsEl.querySelectorAll('.tree-node[data-depth="0"]').forEach(node => {
+                    node.classList.add('highlighted');
+                    addPathDisplay(node, node.getAttribute('data-path'));
+                });
             }
+            
+            const fileCount = data.entries.filter(e => e.type === 'file').length;
+            const folderCount = data.entries.length - fileCount;
+            showSearchStats(term, data.total, fileCount, folderCount);
+        })
+        .catch(error => {
+            console.error('Error searching files:', error);
         });
-        
-        // 显示搜索统计
-        showSearchStats(term, matchCount, fileCount, folderCount);
-    }
 }
 
 // 添加路径显示
@@ -1234,6 +1261,9 @@ function toggleFolder(folderNode) {
         childrenContainer.classList.add('expanded');
         childrenContainer.style.display = '';
         
+        // 首次展开时加载子节点
+        loadDirChildren(childrenContainer);
+        
         // 更新图标
         const icon = folderNode.querySelector('.icon i');
         if (icon) {
@@ -1269,6 +1299,7 @@ function selectFile(fileNode) {
     const path = fileNode.getAttribute('data-path');
     if (currentLogId) {
         currentFilePath = path;
+        currentLevelFilter = 'all';
         loadFileContent(currentLogId, path);
     }
     
@@ -1423,6 +1454,9 @@ function loadFileContent(logId, filePath, offset = 0, limit = 0, append = false)
     if (limit > 0) {
         url += `&limit=${limit}`;
     }
+    if (currentLevelFilter !== 'all' && LEVEL_FILTER_PARAMS[currentLevelFilter]) {
+        url += `&level=${LEVEL_FILTER_PARAMS[currentLevelFilter]}`;
+    }
     
     fetch(url)
         .then(response => response.json())
@@ -1435,12 +1469,17 @@ function loadFileContent(logId, filePath, offset = 0, limit = 0, append = false)
                 return;
             }
             
+            // 显示语法错误（追加分页时保留已有的提示）
+            if (!append || (data.diagnostics && data.diagnostics.length > 0)) {
+                renderDiagnostics(data.diagnostics || [], append);
+            }
+            
             // 检查是否是分页响应
-            const isPaginated = data.total_lines !== undefined;
+            const isPaginated = data.paginated === true || data.total_lines !== undefined;
             
             if (isPaginated) {
                 // 分页响应
-                currentFilePagination.totalLines = data.total_lines;
+                currentFilePagination.totalLines = data.total_lines || 0;
                 currentFilePagination.currentOffset = offset;
                 currentFilePagination.isPaginated = true;
                 
@@ -1505,6 +1544,27 @@ function loadFileContent(logId, filePath, offset = 0, limit = 0, append = false)
         });
 }
 
+// 显示JSON/XML/YAML语法错误
+function renderDiagnostics(diagnostics, append = false) {
+    const diagnosticsEl = document.getElementById('contentDiagnostics');
+    if (!diagnosticsEl) return;
+    
+    if (!append) {
+        diagnosticsEl.innerHTML = '';
+    }
+    if (diagnostics.length === 0 && !append) {
+        diagnosticsEl.style.display = 'none';
+        return;
+    }
+    
+    diagnostics.forEach(d => {
+        const position = d.column ? `第 ${d.line} 行，第 ${d.column} 列` : (d.line ? `第 ${d.line} 行` : '');
+        diagnosticsEl.insertAdjacentHTML('beforeend',
+            `<div class="diagnostic-item"><i class="fas fa-exclamation-circle"></i> ${escapeHtml(position)} ${escapeHtml(d.message)}</div>`);
+    });
+    diagnosticsEl.style.display = diagnosticsEl.children.length > 0 ? 'block' : 'none';
+}
+
 // 显示内容工具栏
 function showContentToolbar(isLogFile) {
     const toolbar = document.getElementById('contentToolbar');
@@ -1584,6 +1644,13 @@ function bindLogFilterButtons() {
 
 // 过滤日志行
 function filterLogLines(filter) {
+    // 分页模式下当前页只是文件的一部分，交给服务端过滤整个文件
+    if (currentFilePagination.isPaginated) {
+        currentLevelFilter = filter;
+        loadFileContent(currentLogId, currentFilePath, 0, currentFilePagination.pageSize, false);
+        return;
+    }
+    
     const logLines = document.querySelectorAll('.log-line');
     const lineCount = document.getElementById('lineCount');
     let visibleCount = 0;
@@ -1619,6 +1686,20 @@ function applyContentFormatting(fileType, content, element, fileName, offset = 0
                       fileName.toLowerCase().includes('log') || content.includes('ERROR') ||
                       content.includes('WARN') || content.includes('INFO');
     
+    if (fileType === 'hex') {
+        // 二进制文件的十六进制视图，每行已带偏移量
+        element.classList.add('hex-content');
+        element.innerHTML = escapeHtml(content);
+        return false;
+    }
+    
+    if (fileType === 'jsonl') {
+        // 服务端已逐行格式化
+        element.classList.add('json-content');
+        element.innerHTML = addLineNumbers(syntaxHighlightJSON(content), offset);
+        return false;
+    }
+    
     if (fileType === 'json') {
         element.classList.add('json-content');
         try {
@@ -1793,6 +1874,7 @@ function clearFileContent() {
     textContentEl.textContent = '';
     fileInfoEl.textContent = '';
     currentFilePath = null;
+    renderDiagnostics([]);
     
     // 隐藏搜索控件并清除搜索
     hideSearchControls();